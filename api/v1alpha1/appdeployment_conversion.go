@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// ConvertTo converts this AppDeployment to the hub version.
+func (src *AppDeployment) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.AppDeployment)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1beta1.AppDeploymentSpec{
+		Provision:              src.Spec.Provision,
+		Teardown:               src.Spec.Teardown,
+		OperationID:            src.Spec.OpId,
+		Dependencies:           src.Spec.Dependencies,
+		MaxRetries:             src.Spec.MaxRetries,
+		FailedJobsHistoryLimit: src.Spec.FailedJobsHistoryLimit,
+		TeardownPolicy:         src.Spec.TeardownPolicy,
+	}
+	dst.Status = v1beta1.AppDeploymentStatus(src.Status)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this AppDeployment.
+func (dst *AppDeployment) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.AppDeployment)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = AppDeploymentSpec{
+		Provision:              src.Spec.Provision,
+		Teardown:               src.Spec.Teardown,
+		OpId:                   src.Spec.OperationID,
+		Dependencies:           src.Spec.Dependencies,
+		MaxRetries:             src.Spec.MaxRetries,
+		FailedJobsHistoryLimit: src.Spec.FailedJobsHistoryLimit,
+		TeardownPolicy:         src.Spec.TeardownPolicy,
+	}
+	dst.Status = AppDeploymentStatus(src.Status)
+	return nil
+}
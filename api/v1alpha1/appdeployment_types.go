@@ -23,6 +23,8 @@ import (
 
 const (
 	AppDeploymentOwnerKey = ".appDeployment.metadata.controller"
+	// AppDeploymentDependencyKey indexes app deployments by the names of the app deployments they depend on
+	AppDeploymentDependencyKey = ".appDeployment.spec.dependencies"
 
 	AppDeploymentFinalizerName = "finalizer.appdeployment.devinfra.goms.io"
 
@@ -33,6 +35,20 @@ const (
 	AppDeploymentPhaseReady     = "Ready"
 	AppDeploymentPhaseDeleting  = "Deleting"
 	AppDeploymentPhaseDeleted   = "Deleted"
+	AppDeploymentPhaseFailed    = "Failed"
+
+	// DefaultMaxRetries is the number of times a failed provision job is retried if maxRetries is not set
+	DefaultMaxRetries int32 = 3
+	// DefaultFailedJobsHistoryLimit is the number of failed provision jobs kept if failedJobsHistoryLimit is not set
+	DefaultFailedJobsHistoryLimit int32 = 3
+
+	// teardown policies
+	TeardownPolicyRetry  = "retry"
+	TeardownPolicyOrphan = "orphan"
+	TeardownPolicyBlock  = "block"
+
+	AppDeploymentConditionProvisionFailed = "ProvisionFailed"
+	AppDeploymentConditionTeardownFailed  = "TeardownFailed"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -48,6 +64,22 @@ type AppDeploymentSpec struct {
 	OpId      string          `json:"opId"`
 	// +kubebuilder:validation:Optional
 	Dependencies []string `json:"dependencies,omitempty"`
+	// MaxRetries is the number of times a failed provision job is retried before the app deployment fails.
+	// Defaults to 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// FailedJobsHistoryLimit is the number of failed provision jobs kept with their pods for debugging, they are
+	// deleted with the TTL of finished jobs at the latest. Defaults to 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+	// TeardownPolicy decides what happens when the teardown job fails: retry runs it again with backoff, orphan records
+	// the leaked resources and finishes the deletion, block stops until the app deployment is annotated to retry.
+	// Defaults to retry.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=retry;orphan;block
+	TeardownPolicy string `json:"teardownPolicy,omitempty"`
 }
 
 // AppDeploymentStatus defines the observed state of AppDeployment.
@@ -56,10 +88,33 @@ type AppDeploymentStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	Phase      string             `json:"phase"`
 	Conditions []metav1.Condition `json:"conditions"`
+	// Outputs are the non-secret outputs the provision job wrote to its termination message
+	// +kubebuilder:validation:Optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// OutputsSecretName is the name of the secret holding the secret outputs of the provision job
+	// +kubebuilder:validation:Optional
+	OutputsSecretName string `json:"outputsSecretName,omitempty"`
+	// ProvisionFailures is the number of failed provision jobs since the last successful or manually retried one
+	// +kubebuilder:validation:Optional
+	ProvisionFailures int32 `json:"provisionFailures,omitempty"`
+	// LastFailureTime is the time the last provision or teardown job failed, retries back off exponentially from it
+	// +kubebuilder:validation:Optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	// ProvisionAttempt is the index of the current provision attempt over the lifetime of the app deployment, retries
+	// are suffixed with it so the jobs of failed attempts can be kept
+	// +kubebuilder:validation:Optional
+	ProvisionAttempt int32 `json:"provisionAttempt,omitempty"`
+	// FailedJobs are the names of the kept failed provision jobs, the most recent last
+	// +kubebuilder:validation:Optional
+	FailedJobs []string `json:"failedJobs,omitempty"`
+	// TeardownFailures is the number of failed teardown jobs
+	// +kubebuilder:validation:Optional
+	TeardownFailures int32 `json:"teardownFailures,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:deprecatedversion:warning="controller.azure.github.com/v1alpha1 is deprecated, use controller.azure.github.com/v1beta1"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Owner",type="string",JSONPath=`.metadata.ownerReferences[0].name`
 // AppDeployment is the Schema for the appdeployments API.
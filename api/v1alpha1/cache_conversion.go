@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// ConvertTo converts this Cache to the hub version.
+func (src *Cache) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Cache)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertOperationSpecTo(&src.Spec.OperationTemplate, &dst.Spec.OperationTemplate, "spec.operationTemplate", &dst.ObjectMeta)
+	dst.Spec.Strategy = src.Spec.Strategy
+	dst.Spec.ExpireTime = convertTimeTo(src.Spec.ExpireTime, "spec.expireTime", &dst.ObjectMeta)
+	dst.Spec.MinKeepAliveCount = src.Spec.MinKeepAliveCount
+	dst.Spec.MaxKeepAliveCount = src.Spec.MaxKeepAliveCount
+	dst.Spec.ProvisionTimeout = src.Spec.ProvisionTimeout
+	dst.Spec.TTLAfterLastAccess = src.Spec.TTLAfterLastAccess
+	if src.Spec.Recycle != nil {
+		dst.Spec.Recycle = &v1beta1.CacheRecycle{Reset: src.Spec.Recycle.Reset, MaxReuse: src.Spec.Recycle.MaxReuse}
+	}
+	dst.Status = v1beta1.CacheStatus{
+		CacheKey:        src.Status.CacheKey,
+		KeepAliveCount:  src.Status.KeepAliveCount,
+		AvailableCaches: src.Status.AvailableCaches,
+		PoolSize:        src.Status.PoolSize,
+		Demand: v1beta1.CacheDemand{
+			Acquisitions: src.Status.Demand.Acquisitions,
+			Misses:       src.Status.Demand.Misses,
+		},
+		RecycledOperations:  src.Status.RecycledOperations,
+		Conditions:          src.Status.Conditions,
+		LastAccessTime:      src.Status.LastAccessTime,
+		LastAcquisitionTime: src.Status.LastAcquisitionTime,
+		PoolResizeTime:      src.Status.PoolResizeTime,
+		ExpireTime:          src.Status.ExpireTime,
+	}
+	if src.Status.Demand.Buckets != nil {
+		dst.Status.Demand.Buckets = make([]v1beta1.CacheDemandBucket, len(src.Status.Demand.Buckets))
+		for i, bucket := range src.Status.Demand.Buckets {
+			dst.Status.Demand.Buckets[i] = v1beta1.CacheDemandBucket(bucket)
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Cache.
+func (dst *Cache) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Cache)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertOperationSpecFrom(&src.Spec.OperationTemplate, &dst.Spec.OperationTemplate, "spec.operationTemplate", &dst.ObjectMeta)
+	dst.Spec.Strategy = src.Spec.Strategy
+	dst.Spec.ExpireTime = convertTimeFrom(src.Spec.ExpireTime, "spec.expireTime", &dst.ObjectMeta)
+	dst.Spec.MinKeepAliveCount = src.Spec.MinKeepAliveCount
+	dst.Spec.MaxKeepAliveCount = src.Spec.MaxKeepAliveCount
+	dst.Spec.ProvisionTimeout = src.Spec.ProvisionTimeout
+	dst.Spec.TTLAfterLastAccess = src.Spec.TTLAfterLastAccess
+	if src.Spec.Recycle != nil {
+		dst.Spec.Recycle = &CacheRecycle{Reset: src.Spec.Recycle.Reset, MaxReuse: src.Spec.Recycle.MaxReuse}
+	}
+	dst.Status = CacheStatus{
+		CacheKey:        src.Status.CacheKey,
+		KeepAliveCount:  src.Status.KeepAliveCount,
+		AvailableCaches: src.Status.AvailableCaches,
+		PoolSize:        src.Status.PoolSize,
+		Demand: CacheDemand{
+			Acquisitions: src.Status.Demand.Acquisitions,
+			Misses:       src.Status.Demand.Misses,
+		},
+		RecycledOperations:  src.Status.RecycledOperations,
+		Conditions:          src.Status.Conditions,
+		LastAccessTime:      src.Status.LastAccessTime,
+		LastAcquisitionTime: src.Status.LastAcquisitionTime,
+		PoolResizeTime:      src.Status.PoolResizeTime,
+		ExpireTime:          src.Status.ExpireTime,
+	}
+	if src.Status.Demand.Buckets != nil {
+		dst.Status.Demand.Buckets = make([]CacheDemandBucket, len(src.Status.Demand.Buckets))
+		for i, bucket := range src.Status.Demand.Buckets {
+			dst.Status.Demand.Buckets[i] = CacheDemandBucket(bucket)
+		}
+	}
+	return nil
+}
@@ -17,11 +17,22 @@ limitations under the License.
 package v1alpha1
 
 import (
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	CacheOwnerKey = ".metadata.controller.cache"
+
+	// strategy types
+	CacheStrategyFixed    = "fixed"
+	CacheStrategyOnDemand = "on-demand"
+	CacheStrategyAdaptive = "adaptive"
+
+	CacheConditionStrategyAccepted = "StrategyAccepted"
+
+	CacheConditionReasonStrategyAccepted = "StrategyAccepted"
+	CacheConditionReasonUnknownStrategy  = "UnknownStrategy"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -34,7 +45,7 @@ type CacheSpec struct {
 
 	OperationTemplate OperationSpec `json:"operationTemplate"`
 
-	// Strategy is the cache strategy
+	// Strategy is the cache strategy, one of fixed, on-demand or adaptive. Defaults to fixed.
 	// +kubebuilder:validation:optional
 	Strategy string `json:"strategy,omitempty"`
 
@@ -42,6 +53,58 @@ type CacheSpec struct {
 	// +kubebuilder:validation:optional
 	// +kubebuilder:validation:Pattern:=`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`
 	ExpireTime string `json:"expireTime,omitempty"`
+
+	// TTLAfterLastAccess expires the cache this long after a requirement last looked up a cached operation from it.
+	// +kubebuilder:validation:optional
+	TTLAfterLastAccess *metav1.Duration `json:"ttlAfterLastAccess,omitempty"`
+
+	// MinKeepAliveCount is the lower bound of the keepAliveCount calculated from the observed demand. Defaults to 0.
+	// +kubebuilder:validation:optional
+	// +kubebuilder:validation:Minimum=0
+	MinKeepAliveCount *int32 `json:"minKeepAlive,omitempty"`
+
+	// MaxKeepAliveCount is the upper bound of the keepAliveCount calculated from the observed demand. Defaults to 5.
+	// +kubebuilder:validation:optional
+	// +kubebuilder:validation:Minimum=0
+	MaxKeepAliveCount *int32 `json:"maxKeepAlive,omitempty"`
+
+	// ProvisionTimeout is how long a cached operation may take to become ready. Cached operations which are still not
+	// ready after it are considered stuck, deleted and replaced. If not set, cached operations are never recycled.
+	// +kubebuilder:validation:optional
+	ProvisionTimeout *metav1.Duration `json:"provisionTimeout,omitempty"`
+
+	// Recycle returns the cached operations released by their requirements to the cache pool instead of deleting
+	// them. If not set, released operations are deleted.
+	// +kubebuilder:validation:optional
+	Recycle *CacheRecycle `json:"recycle,omitempty"`
+}
+
+// CacheRecycle resets the cached operations released by their requirements, so they can be acquired again.
+type CacheRecycle struct {
+	// Reset is the job resetting a released operation, it gets the operation id like the jobs of the applications
+	Reset batchv1.JobSpec `json:"reset"`
+
+	// MaxReuse is how often an operation is returned to the cache pool, it is deleted when it is released once more.
+	// Defaults to 5.
+	// +kubebuilder:validation:optional
+	// +kubebuilder:validation:Minimum=0
+	MaxReuse *int32 `json:"maxReuse,omitempty"`
+}
+
+// CacheDemandBucket counts the demand for a cache within one slot of the demand window.
+type CacheDemandBucket struct {
+	Start        metav1.Time `json:"start"`
+	Acquisitions int32       `json:"acquisitions,omitempty"`
+	Misses       int32       `json:"misses,omitempty"`
+}
+
+// CacheDemand is the demand observed for a cache key over the demand window.
+type CacheDemand struct {
+	// Acquisitions is the number of cached operations acquired by requirements within the window
+	Acquisitions int32 `json:"acquisitions,omitempty"`
+	// Misses is the number of requirements which found no cached operation within the window
+	Misses  int32               `json:"misses,omitempty"`
+	Buckets []CacheDemandBucket `json:"buckets,omitempty"`
 }
 
 // CacheStatus defines the observed state of Cache.
@@ -51,10 +114,26 @@ type CacheStatus struct {
 	CacheKey        string   `json:"cacheKey"`
 	KeepAliveCount  int32    `json:"keepAlive"`
 	AvailableCaches []string `json:"availableCaches,omitempty"`
+	// PoolSize is the number of ready operations the cache strategy decided to keep
+	PoolSize int32 `json:"poolSize,omitempty"`
+	// Demand is the demand for this cache observed over the demand window
+	Demand CacheDemand `json:"demand,omitempty"`
+	// RecycledOperations is the number of stuck cached operations deleted and replaced since the cache was created
+	RecycledOperations int32              `json:"recycledOperations,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	// LastAccessTime is the time a requirement last looked up a cached operation from the cache
+	LastAccessTime *metav1.Time `json:"lastAccessTime,omitempty"`
+	// LastAcquisitionTime is the time a requirement last acquired a cached operation from the cache
+	LastAcquisitionTime *metav1.Time `json:"lastAcquisitionTime,omitempty"`
+	// PoolResizeTime is the time the cache strategy last changed the pool size
+	PoolResizeTime *metav1.Time `json:"poolResizeTime,omitempty"`
+	// ExpireTime is the time the cache is expired at, the earliest of spec.expireTime and the deadline of the ttl
+	ExpireTime *metav1.Time `json:"expireTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:deprecatedversion:warning="controller.azure.github.com/v1alpha1 is deprecated, use controller.azure.github.com/v1beta1"
 
 // Cache is the Schema for the caches API.
 type Cache struct {
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// timeLayout is the layout of the times of the v1alpha1 API, they are validated to be RFC3339 in UTC
+const timeLayout = "2006-01-02T15:04:05Z"
+
+// invalidTimeAnnotationPrefix prefixes the annotations of a hub object keeping the times of the v1alpha1 API which are
+// no valid dates, e.g. 2025-02-30T10:00:00Z matches the validated pattern, so they convert back unchanged
+const invalidTimeAnnotationPrefix = "v1alpha1.controller.azure.github.com/invalid-"
+
+// convertTimeTo converts a time of the v1alpha1 API, a time which is no valid date converts to nil and is kept in an
+// annotation of the hub object instead
+func convertTimeTo(src, field string, dst *metav1.ObjectMeta) *metav1.Time {
+	if src == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, src)
+	if err != nil {
+		metav1.SetMetaDataAnnotation(dst, invalidTimeAnnotationPrefix+field, src)
+		return nil
+	}
+	return &metav1.Time{Time: t}
+}
+
+// convertTimeFrom converts a time of the hub to the v1alpha1 API, an unset time converts back to the invalid time kept
+// in the annotation of the hub object
+func convertTimeFrom(src *metav1.Time, field string, dst *metav1.ObjectMeta) string {
+	key := invalidTimeAnnotationPrefix + field
+	invalid := dst.Annotations[key]
+	delete(dst.Annotations, key)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	if src == nil {
+		return invalid
+	}
+	return src.UTC().Format(timeLayout)
+}
+
+func convertApplicationsTo(src []ApplicationSpec) []v1beta1.ApplicationSpec {
+	if src == nil {
+		return nil
+	}
+	dst := make([]v1beta1.ApplicationSpec, len(src))
+	for i, app := range src {
+		dst[i] = v1beta1.ApplicationSpec(app)
+	}
+	return dst
+}
+
+func convertApplicationsFrom(src []v1beta1.ApplicationSpec) []ApplicationSpec {
+	if src == nil {
+		return nil
+	}
+	dst := make([]ApplicationSpec, len(src))
+	for i, app := range src {
+		dst[i] = ApplicationSpec(app)
+	}
+	return dst
+}
+
+func convertOutputsTo(src []ApplicationOutputs) []v1beta1.ApplicationOutputs {
+	if src == nil {
+		return nil
+	}
+	dst := make([]v1beta1.ApplicationOutputs, len(src))
+	for i, outputs := range src {
+		dst[i] = v1beta1.ApplicationOutputs(outputs)
+	}
+	return dst
+}
+
+func convertOutputsFrom(src []v1beta1.ApplicationOutputs) []ApplicationOutputs {
+	if src == nil {
+		return nil
+	}
+	dst := make([]ApplicationOutputs, len(src))
+	for i, outputs := range src {
+		dst[i] = ApplicationOutputs(outputs)
+	}
+	return dst
+}
+
+func convertOperationSpecTo(src *OperationSpec, dst *v1beta1.OperationSpec, field string, dstMeta *metav1.ObjectMeta) {
+	dst.Applications = convertApplicationsTo(src.Applications)
+	dst.ExpireAt = convertTimeTo(src.ExpireAt, field+".expireAt", dstMeta)
+	dst.TTLAfterReady = src.TTLAfterReady
+	dst.TTLAfterLastAccess = src.TTLAfterLastAccess
+}
+
+func convertOperationSpecFrom(src *v1beta1.OperationSpec, dst *OperationSpec, field string, dstMeta *metav1.ObjectMeta) {
+	dst.Applications = convertApplicationsFrom(src.Applications)
+	dst.ExpireAt = convertTimeFrom(src.ExpireAt, field+".expireAt", dstMeta)
+	dst.TTLAfterReady = src.TTLAfterReady
+	dst.TTLAfterLastAccess = src.TTLAfterLastAccess
+}
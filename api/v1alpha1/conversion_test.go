@@ -0,0 +1,246 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+const testTime = "2025-01-01T10:00:00Z"
+
+var (
+	testObjectMeta = metav1.ObjectMeta{Name: "test", Namespace: "default", Labels: map[string]string{"a": "b"}}
+	testJob        = batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "c", Image: "image"}}},
+		},
+	}
+	testConditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready"}}
+	testOutputs    = []ApplicationOutputs{{Name: "app", Outputs: map[string]string{"k": "v"}, SecretName: "secret"}}
+)
+
+func testOperationSpec() OperationSpec {
+	maxRetries := int32(2)
+	return OperationSpec{
+		Applications: []ApplicationSpec{{
+			Name:                  "app",
+			Provision:             testJob,
+			Teardown:              testJob,
+			Dependencies:          []string{"other"},
+			CacheKeyIgnoredFields: []string{"provision.template.metadata"},
+			MaxRetries:            &maxRetries,
+			TeardownPolicy:        "orphan",
+		}},
+		ExpireAt:           testTime,
+		TTLAfterReady:      &metav1.Duration{Duration: time.Hour},
+		TTLAfterLastAccess: &metav1.Duration{Duration: time.Minute},
+	}
+}
+
+func TestOperationConversion(t *testing.T) {
+	src := &Operation{
+		ObjectMeta: testObjectMeta,
+		Spec:       testOperationSpec(),
+		Status: OperationStatus{
+			Conditions:  testConditions,
+			Phase:       "Reconciled",
+			CacheKey:    "key",
+			OperationID: "id",
+			Outputs:     testOutputs,
+			ReadyTime:   &metav1.Time{Time: time.Unix(0, 0)},
+			ExpireAt:    &metav1.Time{Time: time.Unix(3600, 0)},
+			ReuseCount:  2,
+		},
+	}
+	hub := &v1beta1.Operation{}
+	require.NoError(t, src.ConvertTo(hub))
+	assert.Equal(t, "test", hub.Name)
+	require.NotNil(t, hub.Spec.ExpireAt)
+	assert.True(t, hub.Spec.ExpireAt.Equal(&metav1.Time{Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}))
+	assert.Equal(t, "orphan", hub.Spec.Applications[0].TeardownPolicy)
+	assert.Equal(t, "secret", hub.Status.Outputs[0].SecretName)
+	assert.Equal(t, time.Hour, hub.Spec.TTLAfterReady.Duration)
+	assert.Equal(t, src.Status.ExpireAt, hub.Status.ExpireAt)
+	assert.Equal(t, int32(2), hub.Status.ReuseCount)
+
+	dst := &Operation{}
+	require.NoError(t, dst.ConvertFrom(hub))
+	assert.Equal(t, src, dst)
+}
+
+func TestRequirementConversion(t *testing.T) {
+	for _, enableCache := range []bool{true, false} {
+		src := &Requirement{
+			ObjectMeta: testObjectMeta,
+			Spec: RequirementSpec{
+				Template:           testOperationSpec(),
+				EnableCache:        enableCache,
+				ExpireAt:           testTime,
+				TTLAfterReady:      &metav1.Duration{Duration: time.Hour},
+				TTLAfterLastAccess: &metav1.Duration{Duration: time.Minute},
+			},
+			Status: RequirementStatus{
+				OperationId:        "id",
+				OperationName:      "op",
+				CacheKey:           "key",
+				Phase:              "Ready",
+				Conditions:         testConditions,
+				Outputs:            testOutputs,
+				OperationNamespace: "pool",
+				ReadyTime:          &metav1.Time{Time: time.Unix(0, 0)},
+				ExpireAt:           &metav1.Time{Time: time.Unix(3600, 0)},
+				RenewUntil:         &metav1.Time{Time: time.Unix(3600, 0)},
+				LastHeartbeatTime:  &metav1.Time{Time: time.Unix(60, 0)},
+				ObservedRenewUntil: "1970-01-01T01:00:00Z",
+			},
+		}
+		hub := &v1beta1.Requirement{}
+		require.NoError(t, src.ConvertTo(hub))
+		assert.Equal(t, enableCache, hub.Spec.CacheEnabled())
+		assert.Equal(t, "id", hub.Status.OperationID)
+		assert.Equal(t, "key", hub.Status.CacheKey)
+		assert.Equal(t, time.Minute, hub.Spec.TTLAfterLastAccess.Duration)
+		assert.Equal(t, src.Status.ReadyTime, hub.Status.ReadyTime)
+		assert.Equal(t, src.Status.LastHeartbeatTime, hub.Status.LastHeartbeatTime)
+		assert.Equal(t, src.Status.ObservedRenewUntil, hub.Status.ObservedRenewUntil)
+
+		dst := &Requirement{}
+		require.NoError(t, dst.ConvertFrom(hub))
+		assert.Equal(t, src, dst)
+	}
+}
+
+func TestRequirementConversionFromHub(t *testing.T) {
+	// an unset cache policy is defaulted to enabled
+	hub := &v1beta1.Requirement{ObjectMeta: testObjectMeta}
+	dst := &Requirement{}
+	require.NoError(t, dst.ConvertFrom(hub))
+	assert.True(t, dst.Spec.EnableCache)
+	assert.Empty(t, dst.Spec.ExpireAt)
+
+	// times are written back in UTC
+	expireAt := metav1.NewTime(time.Date(2025, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)))
+	hub.Spec.ExpireAt = &expireAt
+	require.NoError(t, dst.ConvertFrom(hub))
+	assert.Equal(t, testTime, dst.Spec.ExpireAt)
+}
+
+func TestCacheConversion(t *testing.T) {
+	minKeepAlive, maxKeepAlive, maxReuse := int32(1), int32(5), int32(3)
+	src := &Cache{
+		ObjectMeta: testObjectMeta,
+		Spec: CacheSpec{
+			OperationTemplate:  testOperationSpec(),
+			Strategy:           "adaptive",
+			ExpireTime:         testTime,
+			MinKeepAliveCount:  &minKeepAlive,
+			MaxKeepAliveCount:  &maxKeepAlive,
+			ProvisionTimeout:   &metav1.Duration{Duration: time.Hour},
+			TTLAfterLastAccess: &metav1.Duration{Duration: time.Minute},
+			Recycle:            &CacheRecycle{Reset: testJob, MaxReuse: &maxReuse},
+		},
+		Status: CacheStatus{
+			CacheKey:        "key",
+			KeepAliveCount:  3,
+			AvailableCaches: []string{"op"},
+			PoolSize:        2,
+			Demand: CacheDemand{
+				Acquisitions: 4,
+				Misses:       1,
+				Buckets:      []CacheDemandBucket{{Start: metav1.NewTime(time.Unix(0, 0)), Acquisitions: 4, Misses: 1}},
+			},
+			RecycledOperations:  1,
+			Conditions:          testConditions,
+			LastAccessTime:      &metav1.Time{Time: time.Unix(0, 0)},
+			LastAcquisitionTime: &metav1.Time{Time: time.Unix(0, 0)},
+			PoolResizeTime:      &metav1.Time{Time: time.Unix(60, 0)},
+			ExpireTime:          &metav1.Time{Time: time.Unix(3600, 0)},
+		},
+	}
+	hub := &v1beta1.Cache{}
+	require.NoError(t, src.ConvertTo(hub))
+	require.NotNil(t, hub.Spec.ExpireTime)
+	assert.Equal(t, int32(3), hub.Status.KeepAliveCount)
+	assert.Equal(t, int32(5), *hub.Spec.MaxKeepAliveCount)
+	assert.Equal(t, src.Status.LastAccessTime, hub.Status.LastAccessTime)
+	assert.Equal(t, int32(3), *hub.Spec.Recycle.MaxReuse)
+	assert.Equal(t, src.Status.PoolResizeTime, hub.Status.PoolResizeTime)
+
+	dst := &Cache{}
+	require.NoError(t, dst.ConvertFrom(hub))
+	assert.Equal(t, src, dst)
+}
+
+func TestAppDeploymentConversion(t *testing.T) {
+	src := &AppDeployment{
+		ObjectMeta: testObjectMeta,
+		Spec: AppDeploymentSpec{
+			Provision:    testJob,
+			Teardown:     testJob,
+			OpId:         "id",
+			Dependencies: []string{"other"},
+		},
+		Status: AppDeploymentStatus{
+			Phase:            "Ready",
+			Conditions:       testConditions,
+			Outputs:          map[string]string{"k": "v"},
+			ProvisionAttempt: 1,
+		},
+	}
+	hub := &v1beta1.AppDeployment{}
+	require.NoError(t, src.ConvertTo(hub))
+	assert.Equal(t, "id", hub.Spec.OperationID)
+
+	dst := &AppDeployment{}
+	require.NoError(t, dst.ConvertFrom(hub))
+	assert.Equal(t, src, dst)
+}
+
+func TestConversionInvalidTime(t *testing.T) {
+	// the date matches the validated pattern of the v1alpha1 API, but February has no 30th
+	const invalidTime = "2025-02-30T10:00:00Z"
+
+	operation := &Operation{ObjectMeta: testObjectMeta, Spec: OperationSpec{ExpireAt: invalidTime}}
+	operationHub := &v1beta1.Operation{}
+	require.NoError(t, operation.ConvertTo(operationHub))
+	assert.Nil(t, operationHub.Spec.ExpireAt)
+	assert.Equal(t, invalidTime, operationHub.Annotations[invalidTimeAnnotationPrefix+"spec.expireAt"])
+	assert.Nil(t, operation.Annotations, "the source is left untouched")
+	operationDst := &Operation{}
+	require.NoError(t, operationDst.ConvertFrom(operationHub))
+	assert.Equal(t, operation, operationDst)
+
+	requirement := &Requirement{ObjectMeta: testObjectMeta, Spec: RequirementSpec{
+		Template: OperationSpec{ExpireAt: invalidTime},
+		ExpireAt: invalidTime,
+	}}
+	requirementHub := &v1beta1.Requirement{}
+	require.NoError(t, requirement.ConvertTo(requirementHub))
+	assert.Nil(t, requirementHub.Spec.ExpireAt)
+	assert.Nil(t, requirementHub.Spec.Template.ExpireAt)
+	requirementDst := &Requirement{}
+	require.NoError(t, requirementDst.ConvertFrom(requirementHub))
+	assert.Equal(t, requirement.Spec, requirementDst.Spec)
+	assert.Empty(t, requirementDst.Annotations)
+
+	cache := &Cache{ObjectMeta: testObjectMeta, Spec: CacheSpec{ExpireTime: invalidTime}}
+	cacheHub := &v1beta1.Cache{}
+	require.NoError(t, cache.ConvertTo(cacheHub))
+	assert.Nil(t, cacheHub.Spec.ExpireTime)
+	cacheDst := &Cache{}
+	require.NoError(t, cacheDst.ConvertFrom(cacheHub))
+	assert.Equal(t, invalidTime, cacheDst.Spec.ExpireTime)
+	assert.Empty(t, cacheDst.Annotations)
+
+	// a time set on the hub replaces the invalid one
+	cacheHub.Spec.ExpireTime = &metav1.Time{Time: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	require.NoError(t, cacheDst.ConvertFrom(cacheHub))
+	assert.Equal(t, "2025-03-01T10:00:00Z", cacheDst.Spec.ExpireTime)
+}
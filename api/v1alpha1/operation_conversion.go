@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// ConvertTo converts this Operation to the hub version.
+func (src *Operation) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Operation)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertOperationSpecTo(&src.Spec, &dst.Spec, "spec", &dst.ObjectMeta)
+	dst.Status = v1beta1.OperationStatus{
+		Conditions:  src.Status.Conditions,
+		Phase:       src.Status.Phase,
+		CacheKey:    src.Status.CacheKey,
+		OperationID: src.Status.OperationID,
+		Outputs:     convertOutputsTo(src.Status.Outputs),
+		ReadyTime:   src.Status.ReadyTime,
+		ExpireAt:    src.Status.ExpireAt,
+		ReuseCount:  src.Status.ReuseCount,
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Operation.
+func (dst *Operation) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Operation)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertOperationSpecFrom(&src.Spec, &dst.Spec, "spec", &dst.ObjectMeta)
+	dst.Status = OperationStatus{
+		Conditions:  src.Status.Conditions,
+		Phase:       src.Status.Phase,
+		CacheKey:    src.Status.CacheKey,
+		OperationID: src.Status.OperationID,
+		Outputs:     convertOutputsFrom(src.Status.Outputs),
+		ReadyTime:   src.Status.ReadyTime,
+		ExpireAt:    src.Status.ExpireAt,
+		ReuseCount:  src.Status.ReuseCount,
+	}
+	return nil
+}
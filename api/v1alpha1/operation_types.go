@@ -26,12 +26,29 @@ const (
 
 	OperationFinalizerName         = "finalizer.operation.controller.azure.com"
 	OperationAcquiredAnnotationKey = "operation.controller.azure.com/acquired"
+	// OperationAcquiredByAnnotationKey records the namespace/name of the requirement which acquired the operation from
+	// the cache pool of another namespace, owner references cannot cross namespaces
+	OperationAcquiredByAnnotationKey = "operation.controller.azure.com/acquired-by"
+	// OperationReleasedAnnotationKey records the time a requirement released the operation to the cache pool, the
+	// cache resets it before the operation can be acquired again
+	OperationReleasedAnnotationKey = "operation.controller.azure.com/released"
 
 	OperationPhaseEmpty       = ""
 	OperationPhaseReconciling = "Reconciling"
 	OperationPhaseReconciled  = "Reconciled"
 	OperationPhaseDeleting    = "Deleting"
 	OperationPhaseDeleted     = "Deleted"
+	OperationPhaseFailed      = "Failed"
+
+	OperationConditionAppsDeleted       = "AppsDeleted"
+	OperationConditionDependenciesValid = "DependenciesValid"
+	OperationConditionAppsFailed        = "AppsFailed"
+
+	OperationConditionReasonTeardownInProgress = "TeardownInProgress"
+	OperationConditionReasonTeardownCompleted  = "TeardownCompleted"
+	OperationConditionReasonDependenciesValid  = "DependenciesValid"
+	OperationConditionReasonInvalidDependency  = "InvalidDependency"
+	OperationConditionReasonProvisionFailed    = "ProvisionFailed"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -43,6 +60,37 @@ type ApplicationSpec struct {
 	Teardown  batchv1.JobSpec `json:"teardown"`
 	// +kubebuilder:validation:Optional
 	Dependencies []string `json:"dependencies,omitempty"`
+	// CacheKeyIgnoredFields are dot separated paths of fields of the application which do not change its cache key,
+	// e.g. provision.template.spec.containers.resources. A path crossing a list applies to every element of the list.
+	// +kubebuilder:validation:Optional
+	CacheKeyIgnoredFields []string `json:"cacheKeyIgnoredFields,omitempty"`
+	// MaxRetries is the number of times a failed provision job is retried before the application fails.
+	// Defaults to 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// FailedJobsHistoryLimit is the number of failed provision jobs kept with their pods for debugging.
+	// Defaults to 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+	// TeardownPolicy decides what happens when the teardown job fails, one of retry, orphan or block.
+	// Defaults to retry.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=retry;orphan;block
+	TeardownPolicy string `json:"teardownPolicy,omitempty"`
+}
+
+// ApplicationOutputs are the outputs of the provision job of an application.
+type ApplicationOutputs struct {
+	// Name is the name of the application in the operation spec
+	Name string `json:"name"`
+	// Outputs are the non-secret outputs of the provision job
+	// +kubebuilder:validation:Optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// SecretName is the name of the secret holding the secret outputs of the provision job
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
 }
 
 // OperationSpec defines the desired state of Operation.
@@ -56,6 +104,12 @@ type OperationSpec struct {
 	// +kubebuilder:validation:optional
 	// +kubebuilder:validation:Pattern:=`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`
 	ExpireAt string `json:"expireAt,omitempty"`
+	// TTLAfterReady deletes the operation this long after it got ready.
+	// +kubebuilder:validation:Optional
+	TTLAfterReady *metav1.Duration `json:"ttlAfterReady,omitempty"`
+	// TTLAfterLastAccess deletes the operation this long after it was last accessed, see the last-access annotation.
+	// +kubebuilder:validation:Optional
+	TTLAfterLastAccess *metav1.Duration `json:"ttlAfterLastAccess,omitempty"`
 }
 
 // OperationStatus defines the observed state of Operation.
@@ -68,10 +122,23 @@ type OperationStatus struct {
 	Phase       string             `json:"phase"`
 	CacheKey    string             `json:"cacheKey"`
 	OperationID string             `json:"operationId"`
+	// Outputs are the outputs of the applications, set once all of them are ready
+	// +kubebuilder:validation:Optional
+	Outputs []ApplicationOutputs `json:"outputs,omitempty"`
+	// ReadyTime is the time the operation first got ready
+	// +kubebuilder:validation:Optional
+	ReadyTime *metav1.Time `json:"readyTime,omitempty"`
+	// ExpireAt is the time the operation is deleted at, the earliest of spec.expireAt and the deadlines of the ttls
+	// +kubebuilder:validation:Optional
+	ExpireAt *metav1.Time `json:"expireAt,omitempty"`
+	// ReuseCount is the number of times the operation was reset and returned to the cache pool
+	// +kubebuilder:validation:Optional
+	ReuseCount int32 `json:"reuseCount,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:deprecatedversion:warning="controller.azure.github.com/v1alpha1 is deprecated, use controller.azure.github.com/v1beta1"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Key",type="string",JSONPath=`.status.cacheKey`
 
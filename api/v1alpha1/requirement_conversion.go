@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// ConvertTo converts this Requirement to the hub version.
+func (src *Requirement) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Requirement)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertOperationSpecTo(&src.Spec.Template, &dst.Spec.Template, "spec.template", &dst.ObjectMeta)
+	dst.Spec.ExpireAt = convertTimeTo(src.Spec.ExpireAt, "spec.expireAt", &dst.ObjectMeta)
+	dst.Spec.TTLAfterReady = src.Spec.TTLAfterReady
+	dst.Spec.TTLAfterLastAccess = src.Spec.TTLAfterLastAccess
+	dst.Spec.CachePolicy = v1beta1.CachePolicyDisabled
+	if src.Spec.EnableCache {
+		dst.Spec.CachePolicy = v1beta1.CachePolicyEnabled
+	}
+	dst.Status = v1beta1.RequirementStatus{
+		OperationID:        src.Status.OperationId,
+		OperationName:      src.Status.OperationName,
+		CacheKey:           src.Status.CacheKey,
+		Phase:              src.Status.Phase,
+		Conditions:         src.Status.Conditions,
+		Outputs:            convertOutputsTo(src.Status.Outputs),
+		OperationNamespace: src.Status.OperationNamespace,
+		ReadyTime:          src.Status.ReadyTime,
+		ExpireAt:           src.Status.ExpireAt,
+		RenewUntil:         src.Status.RenewUntil,
+		LastHeartbeatTime:  src.Status.LastHeartbeatTime,
+		ObservedRenewUntil: src.Status.ObservedRenewUntil,
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Requirement.
+func (dst *Requirement) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Requirement)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertOperationSpecFrom(&src.Spec.Template, &dst.Spec.Template, "spec.template", &dst.ObjectMeta)
+	dst.Spec.ExpireAt = convertTimeFrom(src.Spec.ExpireAt, "spec.expireAt", &dst.ObjectMeta)
+	dst.Spec.TTLAfterReady = src.Spec.TTLAfterReady
+	dst.Spec.TTLAfterLastAccess = src.Spec.TTLAfterLastAccess
+	dst.Spec.EnableCache = src.Spec.CacheEnabled()
+	dst.Status = RequirementStatus{
+		OperationId:        src.Status.OperationID,
+		OperationName:      src.Status.OperationName,
+		CacheKey:           src.Status.CacheKey,
+		Phase:              src.Status.Phase,
+		Conditions:         src.Status.Conditions,
+		Outputs:            convertOutputsFrom(src.Status.Outputs),
+		OperationNamespace: src.Status.OperationNamespace,
+		ReadyTime:          src.Status.ReadyTime,
+		ExpireAt:           src.Status.ExpireAt,
+		RenewUntil:         src.Status.RenewUntil,
+		LastHeartbeatTime:  src.Status.LastHeartbeatTime,
+		ObservedRenewUntil: src.Status.ObservedRenewUntil,
+	}
+	return nil
+}
@@ -35,6 +35,7 @@ const (
 	RequirementConditionReasonCacheCRFound         = "CacheCRFound"
 	RequirementConditionReasonCacheHit             = "CacheHit"
 	RequirementConditionReasonCacheMiss            = "CacheMiss"
+	RequirementConditionReasonOperationFailed      = "OperationFailed"
 
 	RequirementPhaseEmpty         = ""
 	RequirementPhaseCacheChecking = "CacheChecking"
@@ -42,6 +43,7 @@ const (
 	RequirementPhaseReady         = "Ready"
 	RequirementPhaseDeleted       = "Deleted"
 	RequirementPhaseDeleting      = "Deleting"
+	RequirementPhaseFailed        = "Failed"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -59,6 +61,12 @@ type RequirementSpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern:=`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`
 	ExpireAt string `json:"expireAt,omitempty"`
+	// TTLAfterReady deletes the requirement this long after it got ready.
+	// +kubebuilder:validation:Optional
+	TTLAfterReady *metav1.Duration `json:"ttlAfterReady,omitempty"`
+	// TTLAfterLastAccess deletes the requirement this long after it was last accessed, see the last-access annotation.
+	// +kubebuilder:validation:Optional
+	TTLAfterLastAccess *metav1.Duration `json:"ttlAfterLastAccess,omitempty"`
 }
 
 // RequirementStatus defines the observed state of Requirement.
@@ -70,10 +78,34 @@ type RequirementStatus struct {
 	CacheKey      string             `json:"originalCacheKey"`
 	Phase         string             `json:"phase"`
 	Conditions    []metav1.Condition `json:"conditions"`
+	// Outputs are the outputs of the applications of the operation the requirement is bound to
+	// +kubebuilder:validation:Optional
+	Outputs []ApplicationOutputs `json:"outputs,omitempty"`
+	// OperationNamespace is the namespace of the operation when it was acquired from the cache pool of another
+	// namespace, empty when the operation is in the namespace of the requirement
+	// +kubebuilder:validation:Optional
+	OperationNamespace string `json:"operationNamespace,omitempty"`
+	// ReadyTime is the time the requirement first got ready
+	// +kubebuilder:validation:Optional
+	ReadyTime *metav1.Time `json:"readyTime,omitempty"`
+	// ExpireAt is the time the requirement is deleted at, the earliest of spec.expireAt and the deadlines of the ttls,
+	// or the end of the lease while the consumer renews the requirement
+	// +kubebuilder:validation:Optional
+	ExpireAt *metav1.Time `json:"expireAt,omitempty"`
+	// RenewUntil is the end of the lease taken by the renew-until annotation, capped at the max lifetime
+	// +kubebuilder:validation:Optional
+	RenewUntil *metav1.Time `json:"renewUntil,omitempty"`
+	// LastHeartbeatTime is the time the consumer last renewed the requirement
+	// +kubebuilder:validation:Optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// ObservedRenewUntil is the renew-until annotation the controller last observed, every change of it is a heartbeat
+	// +kubebuilder:validation:Optional
+	ObservedRenewUntil string `json:"observedRenewUntil,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:deprecatedversion:warning="controller.azure.github.com/v1alpha1 is deprecated, use controller.azure.github.com/v1beta1"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="OperationId",type="string",JSONPath=`.status.operationId`
 
@@ -94,6 +94,16 @@ func (in *AppDeploymentSpec) DeepCopyInto(out *AppDeploymentSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedJobsHistoryLimit != nil {
+		in, out := &in.FailedJobsHistoryLimit, &out.FailedJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppDeploymentSpec.
@@ -116,6 +126,22 @@ func (in *AppDeploymentStatus) DeepCopyInto(out *AppDeploymentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedJobs != nil {
+		in, out := &in.FailedJobs, &out.FailedJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppDeploymentStatus.
@@ -128,6 +154,28 @@ func (in *AppDeploymentStatus) DeepCopy() *AppDeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationOutputs) DeepCopyInto(out *ApplicationOutputs) {
+	*out = *in
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationOutputs.
+func (in *ApplicationOutputs) DeepCopy() *ApplicationOutputs {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationOutputs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 	*out = *in
@@ -138,6 +186,21 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CacheKeyIgnoredFields != nil {
+		in, out := &in.CacheKeyIgnoredFields, &out.CacheKeyIgnoredFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedJobsHistoryLimit != nil {
+		in, out := &in.FailedJobsHistoryLimit, &out.FailedJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
@@ -177,6 +240,44 @@ func (in *Cache) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheDemand) DeepCopyInto(out *CacheDemand) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]CacheDemandBucket, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheDemand.
+func (in *CacheDemand) DeepCopy() *CacheDemand {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheDemand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheDemandBucket) DeepCopyInto(out *CacheDemandBucket) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheDemandBucket.
+func (in *CacheDemandBucket) DeepCopy() *CacheDemandBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheDemandBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CacheList) DeepCopyInto(out *CacheList) {
 	*out = *in
@@ -209,10 +310,56 @@ func (in *CacheList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheRecycle) DeepCopyInto(out *CacheRecycle) {
+	*out = *in
+	in.Reset.DeepCopyInto(&out.Reset)
+	if in.MaxReuse != nil {
+		in, out := &in.MaxReuse, &out.MaxReuse
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheRecycle.
+func (in *CacheRecycle) DeepCopy() *CacheRecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheRecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CacheSpec) DeepCopyInto(out *CacheSpec) {
 	*out = *in
 	in.OperationTemplate.DeepCopyInto(&out.OperationTemplate)
+	if in.TTLAfterLastAccess != nil {
+		in, out := &in.TTLAfterLastAccess, &out.TTLAfterLastAccess
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinKeepAliveCount != nil {
+		in, out := &in.MinKeepAliveCount, &out.MinKeepAliveCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxKeepAliveCount != nil {
+		in, out := &in.MaxKeepAliveCount, &out.MaxKeepAliveCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProvisionTimeout != nil {
+		in, out := &in.ProvisionTimeout, &out.ProvisionTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Recycle != nil {
+		in, out := &in.Recycle, &out.Recycle
+		*out = new(CacheRecycle)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheSpec.
@@ -233,6 +380,30 @@ func (in *CacheStatus) DeepCopyInto(out *CacheStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.Demand.DeepCopyInto(&out.Demand)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAccessTime != nil {
+		in, out := &in.LastAccessTime, &out.LastAccessTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAcquisitionTime != nil {
+		in, out := &in.LastAcquisitionTime, &out.LastAcquisitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PoolResizeTime != nil {
+		in, out := &in.PoolResizeTime, &out.PoolResizeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpireTime != nil {
+		in, out := &in.ExpireTime, &out.ExpireTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheStatus.
@@ -314,6 +485,16 @@ func (in *OperationSpec) DeepCopyInto(out *OperationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TTLAfterReady != nil {
+		in, out := &in.TTLAfterReady, &out.TTLAfterReady
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTLAfterLastAccess != nil {
+		in, out := &in.TTLAfterLastAccess, &out.TTLAfterLastAccess
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationSpec.
@@ -336,6 +517,21 @@ func (in *OperationStatus) DeepCopyInto(out *OperationStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]ApplicationOutputs, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadyTime != nil {
+		in, out := &in.ReadyTime, &out.ReadyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpireAt != nil {
+		in, out := &in.ExpireAt, &out.ExpireAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationStatus.
@@ -411,6 +607,16 @@ func (in *RequirementList) DeepCopyObject() runtime.Object {
 func (in *RequirementSpec) DeepCopyInto(out *RequirementSpec) {
 	*out = *in
 	in.Template.DeepCopyInto(&out.Template)
+	if in.TTLAfterReady != nil {
+		in, out := &in.TTLAfterReady, &out.TTLAfterReady
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTLAfterLastAccess != nil {
+		in, out := &in.TTLAfterLastAccess, &out.TTLAfterLastAccess
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequirementSpec.
@@ -433,6 +639,29 @@ func (in *RequirementStatus) DeepCopyInto(out *RequirementStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]ApplicationOutputs, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadyTime != nil {
+		in, out := &in.ReadyTime, &out.ReadyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpireAt != nil {
+		in, out := &in.ExpireAt, &out.ExpireAt
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewUntil != nil {
+		in, out := &in.RenewUntil, &out.RenewUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.LastHeartbeatTime != nil {
+		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequirementStatus.
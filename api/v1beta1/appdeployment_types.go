@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AppDeploymentOwnerKey = ".appDeployment.metadata.controller"
+	// AppDeploymentDependencyKey indexes app deployments by the names of the app deployments they depend on
+	AppDeploymentDependencyKey = ".appDeployment.spec.dependencies"
+
+	AppDeploymentFinalizerName = "finalizer.appdeployment.devinfra.goms.io"
+
+	// phase types
+	AppDeploymentPhaseEmpty     = ""
+	AppDeploymentPhasePending   = "Pending"
+	AppDeploymentPhaseDeploying = "Deploying"
+	AppDeploymentPhaseReady     = "Ready"
+	AppDeploymentPhaseDeleting  = "Deleting"
+	AppDeploymentPhaseDeleted   = "Deleted"
+	AppDeploymentPhaseFailed    = "Failed"
+
+	// DefaultMaxRetries is the number of times a failed provision job is retried if maxRetries is not set
+	DefaultMaxRetries int32 = 3
+	// DefaultFailedJobsHistoryLimit is the number of failed provision jobs kept if failedJobsHistoryLimit is not set
+	DefaultFailedJobsHistoryLimit int32 = 3
+
+	// teardown policies
+	TeardownPolicyRetry  = "retry"
+	TeardownPolicyOrphan = "orphan"
+	TeardownPolicyBlock  = "block"
+
+	AppDeploymentConditionProvisionFailed = "ProvisionFailed"
+	AppDeploymentConditionTeardownFailed  = "TeardownFailed"
+)
+
+// AppDeploymentSpec defines the desired state of AppDeployment.
+type AppDeploymentSpec struct {
+	// Provision is the job provisioning the application
+	Provision batchv1.JobSpec `json:"provision"`
+	// Teardown is the job tearing the application down
+	Teardown batchv1.JobSpec `json:"teardown"`
+	// OperationID is the id of the operation the app deployment belongs to
+	OperationID string `json:"operationId"`
+	// Dependencies are the names of the app deployments which are ready before this one is provisioned
+	// +optional
+	Dependencies []string `json:"dependencies,omitempty"`
+	// MaxRetries is the number of times a failed provision job is retried before the app deployment fails.
+	// Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// FailedJobsHistoryLimit is the number of failed provision jobs kept with their pods for debugging, they are
+	// deleted with the TTL of finished jobs at the latest. Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+	// TeardownPolicy decides what happens when the teardown job fails: retry runs it again with backoff, orphan records
+	// the leaked resources and finishes the deletion, block stops until the app deployment is annotated to retry.
+	// Defaults to retry.
+	// +optional
+	// +kubebuilder:validation:Enum=retry;orphan;block
+	TeardownPolicy string `json:"teardownPolicy,omitempty"`
+}
+
+// AppDeploymentStatus defines the observed state of AppDeployment.
+type AppDeploymentStatus struct {
+	// Phase is the phase of the app deployment
+	// +optional
+	// +kubebuilder:validation:Enum=Pending;Deploying;Ready;Deleting;Deleted;Failed
+	Phase string `json:"phase,omitempty"`
+	// Conditions describe the failures of the provision and teardown jobs
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Outputs are the non-secret outputs the provision job wrote to its termination message
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// OutputsSecretName is the name of the secret holding the secret outputs of the provision job
+	// +optional
+	OutputsSecretName string `json:"outputsSecretName,omitempty"`
+	// ProvisionFailures is the number of failed provision jobs since the last successful or manually retried one
+	// +optional
+	ProvisionFailures int32 `json:"provisionFailures,omitempty"`
+	// LastFailureTime is the time the last provision or teardown job failed, retries back off exponentially from it
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	// ProvisionAttempt is the index of the current provision attempt over the lifetime of the app deployment, retries
+	// are suffixed with it so the jobs of failed attempts can be kept
+	// +optional
+	ProvisionAttempt int32 `json:"provisionAttempt,omitempty"`
+	// FailedJobs are the names of the kept failed provision jobs, the most recent last
+	// +optional
+	FailedJobs []string `json:"failedJobs,omitempty"`
+	// TeardownFailures is the number of failed teardown jobs
+	// +optional
+	TeardownFailures int32 `json:"teardownFailures,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Owner",type="string",JSONPath=`.metadata.ownerReferences[0].name`
+
+// AppDeployment is the Schema for the appdeployments API.
+type AppDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppDeploymentSpec   `json:"spec,omitempty"`
+	Status AppDeploymentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AppDeploymentList contains a list of AppDeployment.
+type AppDeploymentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppDeployment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AppDeployment{}, &AppDeploymentList{})
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	CacheOwnerKey = ".metadata.controller.cache"
+
+	// strategy types
+	CacheStrategyFixed    = "fixed"
+	CacheStrategyOnDemand = "on-demand"
+	CacheStrategyAdaptive = "adaptive"
+
+	CacheConditionStrategyAccepted = "StrategyAccepted"
+
+	CacheConditionReasonStrategyAccepted = "StrategyAccepted"
+	CacheConditionReasonUnknownStrategy  = "UnknownStrategy"
+)
+
+// CacheSpec defines the desired state of Cache.
+type CacheSpec struct {
+	// OperationTemplate is the spec of the cached operations
+	OperationTemplate OperationSpec `json:"operationTemplate"`
+
+	// Strategy is the cache strategy, one of fixed, on-demand or adaptive. Defaults to fixed.
+	// +optional
+	// +kubebuilder:validation:Enum=fixed;on-demand;adaptive
+	Strategy string `json:"strategy,omitempty"`
+
+	// ExpireTime is the time the cache is expired at. If not set, the cache is never expired.
+	// +optional
+	ExpireTime *metav1.Time `json:"expireTime,omitempty"`
+
+	// TTLAfterLastAccess expires the cache this long after a requirement last looked up a cached operation from it.
+	// It counts from the creation of the cache if it was never accessed.
+	// +optional
+	TTLAfterLastAccess *metav1.Duration `json:"ttlAfterLastAccess,omitempty"`
+
+	// MinKeepAliveCount is the lower bound of the keepAliveCount calculated from the observed demand. Defaults to 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinKeepAliveCount *int32 `json:"minKeepAliveCount,omitempty"`
+
+	// MaxKeepAliveCount is the upper bound of the keepAliveCount calculated from the observed demand. Defaults to 5.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxKeepAliveCount *int32 `json:"maxKeepAliveCount,omitempty"`
+
+	// ProvisionTimeout is how long a cached operation may take to become ready. Cached operations which are still not
+	// ready after it are considered stuck, deleted and replaced. If not set, cached operations are never recycled.
+	// +optional
+	ProvisionTimeout *metav1.Duration `json:"provisionTimeout,omitempty"`
+
+	// Recycle returns the cached operations released by their requirements to the cache pool instead of deleting
+	// them. If not set, released operations are deleted.
+	// +optional
+	Recycle *CacheRecycle `json:"recycle,omitempty"`
+}
+
+// CacheRecycle resets the cached operations released by their requirements, so they can be acquired again.
+type CacheRecycle struct {
+	// Reset is the job resetting a released operation, it gets the operation id like the jobs of the applications
+	Reset batchv1.JobSpec `json:"reset"`
+
+	// MaxReuse is how often an operation is returned to the cache pool, it is deleted when it is released once more.
+	// Defaults to 5.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxReuse *int32 `json:"maxReuse,omitempty"`
+}
+
+// CacheDemandBucket counts the demand for a cache within one slot of the demand window.
+type CacheDemandBucket struct {
+	Start        metav1.Time `json:"start"`
+	Acquisitions int32       `json:"acquisitions,omitempty"`
+	Misses       int32       `json:"misses,omitempty"`
+}
+
+// CacheDemand is the demand observed for a cache key over the demand window.
+type CacheDemand struct {
+	// Acquisitions is the number of cached operations acquired by requirements within the window
+	Acquisitions int32 `json:"acquisitions,omitempty"`
+	// Misses is the number of requirements which found no cached operation within the window
+	Misses  int32               `json:"misses,omitempty"`
+	Buckets []CacheDemandBucket `json:"buckets,omitempty"`
+}
+
+// CacheStatus defines the observed state of Cache.
+type CacheStatus struct {
+	// CacheKey is the cache key of the operation template
+	// +optional
+	CacheKey string `json:"cacheKey,omitempty"`
+	// KeepAliveCount is the number of cached operations kept for the cache key
+	// +optional
+	KeepAliveCount int32 `json:"keepAliveCount,omitempty"`
+	// AvailableCaches are the names of the ready cached operations
+	// +optional
+	AvailableCaches []string `json:"availableCaches,omitempty"`
+	// PoolSize is the number of ready operations the cache strategy decided to keep
+	// +optional
+	PoolSize int32 `json:"poolSize,omitempty"`
+	// Demand is the demand for this cache observed over the demand window
+	// +optional
+	Demand CacheDemand `json:"demand,omitempty"`
+	// RecycledOperations is the number of stuck cached operations deleted and replaced since the cache was created
+	// +optional
+	RecycledOperations int32 `json:"recycledOperations,omitempty"`
+	// LastAccessTime is the time a requirement last looked up a cached operation from the cache
+	// +optional
+	LastAccessTime *metav1.Time `json:"lastAccessTime,omitempty"`
+	// LastAcquisitionTime is the time a requirement last acquired a cached operation from the cache
+	// +optional
+	LastAcquisitionTime *metav1.Time `json:"lastAcquisitionTime,omitempty"`
+	// PoolResizeTime is the time the cache strategy last changed the pool size
+	// +optional
+	PoolResizeTime *metav1.Time `json:"poolResizeTime,omitempty"`
+	// ExpireTime is the time the cache is expired at, the earliest of spec.expireTime and the deadline of the ttl
+	// +optional
+	ExpireTime *metav1.Time `json:"expireTime,omitempty"`
+	// Conditions describe the state of the cache
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// Cache is the Schema for the caches API.
+type Cache struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CacheSpec   `json:"spec,omitempty"`
+	Status CacheStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CacheList contains a list of Cache.
+type CacheList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Cache `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Cache{}, &CacheList{})
+}
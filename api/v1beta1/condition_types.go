@@ -0,0 +1,27 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// Conditions shared by requirements, operations and caches
+const (
+	// ConditionExpiringSoon is true while an object expires within the expiring soon window of the controller, so it
+	// can still be extended. It turns false when the expiry of the object is extended past the window.
+	ConditionExpiringSoon = "ExpiringSoon"
+
+	ConditionReasonDeadlineApproaching = "DeadlineApproaching"
+	ConditionReasonDeadlineExtended    = "DeadlineExtended"
+)
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	OperationOwnerKey = ".operation.metadata.controller"
+
+	OperationFinalizerName         = "finalizer.operation.controller.azure.com"
+	OperationAcquiredAnnotationKey = "operation.controller.azure.com/acquired"
+	// OperationAcquiredByAnnotationKey records the namespace/name of the requirement which acquired the operation from
+	// the cache pool of another namespace, owner references cannot cross namespaces
+	OperationAcquiredByAnnotationKey = "operation.controller.azure.com/acquired-by"
+	// OperationReleasedAnnotationKey records the time a requirement released the operation to the cache pool, the
+	// cache resets it before the operation can be acquired again
+	OperationReleasedAnnotationKey = "operation.controller.azure.com/released"
+
+	OperationPhaseEmpty       = ""
+	OperationPhaseReconciling = "Reconciling"
+	OperationPhaseReconciled  = "Reconciled"
+	OperationPhaseDeleting    = "Deleting"
+	OperationPhaseDeleted     = "Deleted"
+	OperationPhaseFailed      = "Failed"
+
+	OperationConditionAppsDeleted       = "AppsDeleted"
+	OperationConditionDependenciesValid = "DependenciesValid"
+	OperationConditionAppsFailed        = "AppsFailed"
+
+	OperationConditionReasonTeardownInProgress = "TeardownInProgress"
+	OperationConditionReasonTeardownCompleted  = "TeardownCompleted"
+	OperationConditionReasonDependenciesValid  = "DependenciesValid"
+	OperationConditionReasonInvalidDependency  = "InvalidDependency"
+	OperationConditionReasonProvisionFailed    = "ProvisionFailed"
+)
+
+// ApplicationSpec is an application of an operation, provisioned and torn down by jobs.
+type ApplicationSpec struct {
+	// Name is the name of the application, unique within the operation
+	Name string `json:"name"`
+	// Provision is the job provisioning the application
+	Provision batchv1.JobSpec `json:"provision"`
+	// Teardown is the job tearing the application down
+	Teardown batchv1.JobSpec `json:"teardown"`
+	// Dependencies are the names of the applications which are provisioned before this one
+	// +optional
+	Dependencies []string `json:"dependencies,omitempty"`
+	// CacheKeyIgnoredFields are dot separated paths of fields of the application which do not change its cache key,
+	// e.g. provision.template.spec.containers.resources. A path crossing a list applies to every element of the list.
+	// +optional
+	CacheKeyIgnoredFields []string `json:"cacheKeyIgnoredFields,omitempty"`
+	// MaxRetries is the number of times a failed provision job is retried before the application fails.
+	// Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// FailedJobsHistoryLimit is the number of failed provision jobs kept with their pods for debugging.
+	// Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+	// TeardownPolicy decides what happens when the teardown job fails, one of retry, orphan or block.
+	// Defaults to retry.
+	// +optional
+	// +kubebuilder:validation:Enum=retry;orphan;block
+	TeardownPolicy string `json:"teardownPolicy,omitempty"`
+}
+
+// ApplicationOutputs are the outputs of the provision job of an application.
+type ApplicationOutputs struct {
+	// Name is the name of the application in the operation spec
+	Name string `json:"name"`
+	// Outputs are the non-secret outputs of the provision job
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// SecretName is the name of the secret holding the secret outputs of the provision job
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// OperationSpec defines the desired state of Operation.
+type OperationSpec struct {
+	// Applications are the applications of the operation
+	// +kubebuilder:validation:MinItems=1
+	Applications []ApplicationSpec `json:"applications"`
+	// ExpireAt is the time the operation is deleted at. If not set, the operation is never expired.
+	// +optional
+	ExpireAt *metav1.Time `json:"expireAt,omitempty"`
+	// TTLAfterReady deletes the operation this long after it got ready.
+	// +optional
+	TTLAfterReady *metav1.Duration `json:"ttlAfterReady,omitempty"`
+	// TTLAfterLastAccess deletes the operation this long after it was last accessed, see the last-access annotation.
+	// It counts from the time the operation got ready if it was never accessed.
+	// +optional
+	TTLAfterLastAccess *metav1.Duration `json:"ttlAfterLastAccess,omitempty"`
+}
+
+// OperationStatus defines the observed state of Operation.
+type OperationStatus struct {
+	// Conditions describe the state of the applications of the operation
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Phase is the phase of the operation
+	// +optional
+	// +kubebuilder:validation:Enum=Reconciling;Reconciled;Deleting;Deleted;Failed
+	Phase string `json:"phase,omitempty"`
+	// CacheKey is the cache key of the applications of the operation
+	// +optional
+	CacheKey string `json:"cacheKey,omitempty"`
+	// OperationID identifies the app deployments of the operation, it is regenerated when the applications change
+	// +optional
+	OperationID string `json:"operationId,omitempty"`
+	// Outputs are the outputs of the applications, set once all of them are ready
+	// +optional
+	Outputs []ApplicationOutputs `json:"outputs,omitempty"`
+	// ReadyTime is the time the operation first got ready
+	// +optional
+	ReadyTime *metav1.Time `json:"readyTime,omitempty"`
+	// ExpireAt is the time the operation is deleted at, the earliest of spec.expireAt and the deadlines of the ttls
+	// +optional
+	ExpireAt *metav1.Time `json:"expireAt,omitempty"`
+	// ReuseCount is the number of times the operation was reset and returned to the cache pool
+	// +optional
+	ReuseCount int32 `json:"reuseCount,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Key",type="string",JSONPath=`.status.cacheKey`
+
+// Operation is the Schema for the operations API.
+type Operation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperationSpec   `json:"spec,omitempty"`
+	Status OperationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperationList contains a list of Operation.
+type OperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Operation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Operation{}, &OperationList{})
+}
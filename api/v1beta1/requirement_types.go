@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RequirementOwnerKey = ".requirement.metadata.controller"
+
+	RequirementFinalizerName = "finalizer.requirement.devinfra.goms.io"
+
+	RequirementConditionRequirementInitialized  = "RequirementInitialized"
+	RequirementConditionCacheResourceFound      = "CacheCRFound"
+	RequirementConditionCachedOperationAcquired = "CachedOpAcquired"
+	RequirementConditionOperationReady          = "OperationReady"
+
+	RequirementConditionReasonNoOperationAvailable = "NoOperationAvailable"
+	RequirementConditionReasonCacheCRNotFound      = "CacheCRNotFound"
+	RequirementConditionReasonCacheCRFound         = "CacheCRFound"
+	RequirementConditionReasonCacheHit             = "CacheHit"
+	RequirementConditionReasonCacheMiss            = "CacheMiss"
+	RequirementConditionReasonOperationFailed      = "OperationFailed"
+
+	RequirementPhaseEmpty         = ""
+	RequirementPhaseCacheChecking = "CacheChecking"
+	RequirementPhaseOperating     = "Operating"
+	RequirementPhaseReady         = "Ready"
+	RequirementPhaseDeleted       = "Deleted"
+	RequirementPhaseDeleting      = "Deleting"
+	RequirementPhaseFailed        = "Failed"
+
+	// cache policies
+	CachePolicyEnabled  = "Enabled"
+	CachePolicyDisabled = "Disabled"
+)
+
+// RequirementSpec defines the desired state of Requirement.
+type RequirementSpec struct {
+	// Template is the spec of the operation the requirement is bound to
+	Template OperationSpec `json:"template"`
+	// CachePolicy decides whether the operation is acquired from the cache, one of Enabled or Disabled.
+	// Defaults to Enabled.
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +kubebuilder:default=Enabled
+	CachePolicy string `json:"cachePolicy,omitempty"`
+	// ExpireAt is the time the requirement is deleted at. If not set, the requirement is never expired.
+	// +optional
+	ExpireAt *metav1.Time `json:"expireAt,omitempty"`
+	// TTLAfterReady deletes the requirement this long after it got ready.
+	// +optional
+	TTLAfterReady *metav1.Duration `json:"ttlAfterReady,omitempty"`
+	// TTLAfterLastAccess deletes the requirement this long after it was last accessed, see the last-access annotation.
+	// It counts from the time the requirement got ready if it was never accessed.
+	// +optional
+	TTLAfterLastAccess *metav1.Duration `json:"ttlAfterLastAccess,omitempty"`
+}
+
+// CacheEnabled returns whether the operation of the requirement is acquired from the cache.
+func (s *RequirementSpec) CacheEnabled() bool {
+	return s.CachePolicy != CachePolicyDisabled
+}
+
+// RequirementStatus defines the observed state of Requirement.
+type RequirementStatus struct {
+	// OperationID identifies the operation the requirement is bound to
+	// +optional
+	OperationID string `json:"operationId,omitempty"`
+	// OperationName is the name of the operation the requirement is bound to
+	// +optional
+	OperationName string `json:"operationName,omitempty"`
+	// CacheKey is the cache key of the template
+	// +optional
+	CacheKey string `json:"cacheKey,omitempty"`
+	// Phase is the phase of the requirement
+	// +optional
+	// +kubebuilder:validation:Enum=CacheChecking;Operating;Ready;Deleted;Deleting;Failed
+	Phase string `json:"phase,omitempty"`
+	// Conditions describe the state of the requirement
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Outputs are the outputs of the applications of the operation the requirement is bound to
+	// +optional
+	Outputs []ApplicationOutputs `json:"outputs,omitempty"`
+	// OperationNamespace is the namespace of the operation when it was acquired from the cache pool of another
+	// namespace, empty when the operation is in the namespace of the requirement
+	// +optional
+	OperationNamespace string `json:"operationNamespace,omitempty"`
+	// ReadyTime is the time the requirement first got ready
+	// +optional
+	ReadyTime *metav1.Time `json:"readyTime,omitempty"`
+	// ExpireAt is the time the requirement is deleted at, the earliest of spec.expireAt and the deadlines of the ttls,
+	// or the end of the lease while the consumer renews the requirement
+	// +optional
+	ExpireAt *metav1.Time `json:"expireAt,omitempty"`
+	// RenewUntil is the end of the lease taken by the renew-until annotation, capped at the max lifetime
+	// +optional
+	RenewUntil *metav1.Time `json:"renewUntil,omitempty"`
+	// LastHeartbeatTime is the time the consumer last renewed the requirement
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// ObservedRenewUntil is the renew-until annotation the controller last observed, every change of it is a heartbeat
+	// +optional
+	ObservedRenewUntil string `json:"observedRenewUntil,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="OperationId",type="string",JSONPath=`.status.operationId`
+
+// Requirement is the Schema for the requirements API.
+type Requirement struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RequirementSpec   `json:"spec,omitempty"`
+	Status RequirementStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RequirementList contains a list of Requirement.
+type RequirementList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Requirement `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Requirement{}, &RequirementList{})
+}
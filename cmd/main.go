@@ -17,28 +17,41 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
-	v1alpha1 "github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/advisor"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	"github.com/Azure/operation-cache-controller/internal/controller"
+	"github.com/Azure/operation-cache-controller/internal/migration"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+	webhookv1beta1 "github.com/Azure/operation-cache-controller/internal/webhook/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,8 +62,10 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -63,6 +78,15 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var advisorURL string
+	var advisorTimeout time.Duration
+	var requirementDefaultTTL time.Duration
+	var configFile string
+	var configReloadInterval time.Duration
+	var requeueDelay, cacheCheckInterval, cacheExpireTime time.Duration
+	var maxConcurrentReconciles int
+	var watchNamespaces, watchNamespaceSelector string
+	var migrateStorageVersion bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,6 +105,37 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&advisorURL, "keepalive-advisor-url", "",
+		"The URL of an external keep-alive advisor which sizes the cache pools. "+
+			"Leave empty to calculate the keepAliveCount from the observed demand only.")
+	flag.DurationVar(&advisorTimeout, "keepalive-advisor-timeout", 5*time.Second,
+		"The timeout of a keep-alive advisor request, the local keepAliveCount is used when it is exceeded.")
+	flag.DurationVar(&requirementDefaultTTL, "requirement-default-ttl", 0,
+		"The time to live of requirements created without expireAt, set by the defaulting webhook. "+
+			"Leave as 0 to keep such requirements until they are deleted.")
+	flag.StringVar(&configFile, "config", "",
+		"The path of a ControllerConfig file with the tunables of the controllers, it is reloaded when it changes. "+
+			"The flags below override its values when they are set.")
+	flag.DurationVar(&configReloadInterval, "config-reload-interval", 30*time.Second,
+		"How often the config file is checked for changes.")
+	flag.DurationVar(&requeueDelay, "requeue-delay", config.DefaultRequeueDelay,
+		"How long a reconciler waits before it retries a failed or unfinished step.")
+	flag.DurationVar(&cacheCheckInterval, "cache-check-interval", config.DefaultCacheCheckInterval,
+		"How often the cache controller checks every cache.")
+	flag.DurationVar(&cacheExpireTime, "cache-expire-time", config.DefaultCacheExpireTime,
+		"How long a cache created for a requirement lives.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 0,
+		"The number of concurrent reconciles of every controller, read at startup only. "+
+			"Leave as 0 to use the config file or the defaults of each controller.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"A comma separated list of namespaces the controllers are restricted to. "+
+			"Leave empty, together with --watch-namespace-selector, to watch all namespaces.")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "",
+		"A label selector of namespaces the controllers are restricted to, in addition to --watch-namespaces. "+
+			"The matching namespaces are resolved at startup, restart the controller to pick up new ones.")
+	flag.BoolVar(&migrateStorageVersion, "migrate-storage-version", true,
+		"If set, the leader rewrites the objects stored in an older api version in the storage version and drops "+
+			"the older versions from the stored versions of the CRDs. Needs cluster-wide access to the CRDs.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -89,6 +144,50 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// flags set on the command line override the config file
+	overrides := []func(*config.ControllerConfig){}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "requeue-delay":
+			overrides = append(overrides, func(c *config.ControllerConfig) { c.RequeueDelay.Duration = requeueDelay })
+		case "cache-check-interval":
+			overrides = append(overrides, func(c *config.ControllerConfig) { c.CacheCheckInterval.Duration = cacheCheckInterval })
+		case "cache-expire-time":
+			overrides = append(overrides, func(c *config.ControllerConfig) { c.CacheExpireTime.Duration = cacheExpireTime })
+		case "max-concurrent-reconciles":
+			overrides = append(overrides, func(c *config.ControllerConfig) {
+				c.MaxConcurrentReconciles = config.ConcurrencyConfig{
+					AppDeployment: maxConcurrentReconciles,
+					Operation:     maxConcurrentReconciles,
+					Requirement:   maxConcurrentReconciles,
+					Cache:         maxConcurrentReconciles,
+				}
+			})
+		}
+	})
+	override := func(c *config.ControllerConfig) {
+		for _, o := range overrides {
+			o(c)
+		}
+	}
+	var configWatcher *config.Watcher
+	if len(configFile) > 0 {
+		setupLog.Info("Loading controller config", "config", configFile)
+		configWatcher = config.NewWatcher(configFile, configReloadInterval, override, ctrl.Log.WithName("config"))
+		if err := configWatcher.Load(); err != nil {
+			setupLog.Error(err, "unable to load controller config")
+			os.Exit(1)
+		}
+	} else {
+		cfg := config.Default()
+		override(cfg)
+		if err := cfg.Validate(); err != nil {
+			setupLog.Error(err, "invalid controller config")
+			os.Exit(1)
+		}
+		config.Set(cfg)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -178,13 +277,49 @@ func main() {
 		})
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	restConfig := ctrl.GetConfigOrDie()
+
+	// restricting the cache to some namespaces lets the controllers run with namespaced roles only
+	var cacheOptions cache.Options
+	if len(watchNamespaces) > 0 || len(watchNamespaceSelector) > 0 {
+		apiReader, err := client.New(restConfig, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+		// the cache pool is shared by the watched namespaces, it needs to be watched as well. The config watcher keeps
+		// the cache pool of the startup config, so the watched namespaces stay in sync with it.
+		listed := ctrlutils.ParseNamespaces(watchNamespaces)
+		if pool := config.Current().CachePool.Namespace; len(pool) > 0 {
+			listed = append(listed, pool)
+		}
+		namespaces, err := ctrlutils.WatchNamespaces(context.Background(), apiReader, listed, watchNamespaceSelector)
+		if err != nil {
+			setupLog.Error(err, "unable to resolve the namespaces to watch")
+			os.Exit(1)
+		}
+		setupLog.Info("Restricting controllers to namespaces", "namespaces", namespaces)
+		cacheOptions.DefaultNamespaces = make(map[string]cache.Config, len(namespaces))
+		for _, ns := range namespaces {
+			cacheOptions.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOptions,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "ae654853.github.com",
+		// job pods, output secrets and the roles granting access to them are only read once per provision job,
+		// caching them would watch every pod, secret and role of the cluster
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{
+				&corev1.Pod{}, &corev1.Secret{}, &rbacv1.Role{}, &rbacv1.RoleBinding{},
+			}},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -216,9 +351,15 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Operation")
 		os.Exit(1)
 	}
+	var keepAliveAdvisor advisor.KeepAliveAdvisor
+	if len(advisorURL) > 0 {
+		setupLog.Info("Using external keep-alive advisor", "keepalive-advisor-url", advisorURL)
+		keepAliveAdvisor = advisor.NewHTTPAdvisor(advisorURL, advisorTimeout)
+	}
 	if err = (&controller.CacheReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Advisor: keepAliveAdvisor,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cache")
 		os.Exit(1)
@@ -230,8 +371,44 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Requirement")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookv1beta1.SetupAppDeploymentWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AppDeployment")
+			os.Exit(1)
+		}
+		if err = webhookv1beta1.SetupOperationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Operation")
+			os.Exit(1)
+		}
+		if err = webhookv1beta1.SetupCacheWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Cache")
+			os.Exit(1)
+		}
+		if err = webhookv1beta1.SetupRequirementWebhookWithManager(mgr, requirementDefaultTTL); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Requirement")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if migrateStorageVersion {
+		setupLog.Info("Adding storage version migrator to manager")
+		migrator := migration.NewStorageVersionMigrator(mgr.GetClient(), mgr.GetAPIReader(), ctrl.Log.WithName("migration"))
+		if err := mgr.Add(migrator); err != nil {
+			setupLog.Error(err, "unable to add storage version migrator to manager")
+			os.Exit(1)
+		}
+	}
+
+	if configWatcher != nil {
+		setupLog.Info("Adding controller config watcher to manager")
+		if err := mgr.Add(configWatcher); err != nil {
+			setupLog.Error(err, "unable to add controller config watcher to manager")
+			os.Exit(1)
+		}
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -0,0 +1,90 @@
+package advisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrInvalidAdvice = errors.New("invalid keep-alive advice")
+)
+
+// Request is what the cache controller sends to the keep-alive advisor for every cache it sizes.
+type Request struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	CacheKey  string `json:"cacheKey"`
+	// Acquisitions and Misses are the demand observed over the last WindowSeconds
+	Acquisitions  int32 `json:"acquisitions"`
+	Misses        int32 `json:"misses"`
+	WindowSeconds int64 `json:"windowSeconds"`
+	// KeepAliveCount and AvailableCount describe the current pool
+	KeepAliveCount int32 `json:"keepAliveCount"`
+	AvailableCount int32 `json:"availableCount"`
+	// LocalKeepAliveCount is what the controller would use without the advisor
+	LocalKeepAliveCount int32 `json:"localKeepAliveCount"`
+	MinKeepAliveCount   int32 `json:"minKeepAliveCount"`
+	MaxKeepAliveCount   int32 `json:"maxKeepAliveCount"`
+}
+
+// Response is the advice returned by the keep-alive advisor.
+type Response struct {
+	KeepAliveCount int32 `json:"keepAliveCount"`
+}
+
+//go:generate mockgen -destination=./mocks/mock_advisor.go -package=mocks github.com/Azure/operation-cache-controller/internal/advisor KeepAliveAdvisor
+type KeepAliveAdvisor interface {
+	// KeepAliveCount returns the number of operations the advisor wants the cache to keep alive
+	KeepAliveCount(ctx context.Context, req Request) (int32, error)
+}
+
+// HTTPAdvisor queries a keep-alive advisor which accepts a JSON encoded Request in a POST to its URL
+// and answers with a JSON encoded Response.
+type HTTPAdvisor struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPAdvisor(url string, timeout time.Duration) *HTTPAdvisor {
+	return &HTTPAdvisor{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HTTPAdvisor) KeepAliveCount(ctx context.Context, req Request) (int32, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode advisor request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create advisor request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query advisor: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("advisor responded with %s: %s", resp.Status, string(msg))
+	}
+	advice := Response{}
+	if err := json.NewDecoder(resp.Body).Decode(&advice); err != nil {
+		return 0, fmt.Errorf("failed to decode advisor response: %w", err)
+	}
+	if advice.KeepAliveCount < 0 {
+		return 0, fmt.Errorf("%w: negative keepAliveCount %d", ErrInvalidAdvice, advice.KeepAliveCount)
+	}
+	return advice.KeepAliveCount, nil
+}
@@ -0,0 +1,96 @@
+package advisor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeAdvisor starts a fake keep-alive advisor which answers with the given handler
+func newFakeAdvisor(t *testing.T, handler func(req Request) (int, any)) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		req := Request{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		status, body := handler(req)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPAdvisorKeepAliveCount(t *testing.T) {
+	ctx := context.Background()
+	req := Request{
+		Namespace:           "test-ns",
+		Name:                "cache-test",
+		CacheKey:            "test-key",
+		Acquisitions:        4,
+		Misses:              2,
+		WindowSeconds:       3600,
+		KeepAliveCount:      3,
+		AvailableCount:      1,
+		LocalKeepAliveCount: 5,
+		MaxKeepAliveCount:   5,
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		server := newFakeAdvisor(t, func(got Request) (int, any) {
+			assert.Equal(t, req, got)
+			return http.StatusOK, Response{KeepAliveCount: 7}
+		})
+		count, err := NewHTTPAdvisor(server.URL, time.Second).KeepAliveCount(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, int32(7), count)
+	})
+
+	t.Run("sad path: advisor error", func(t *testing.T) {
+		server := newFakeAdvisor(t, func(Request) (int, any) {
+			return http.StatusInternalServerError, "forecast unavailable"
+		})
+		_, err := NewHTTPAdvisor(server.URL, time.Second).KeepAliveCount(ctx, req)
+		assert.ErrorContains(t, err, "forecast unavailable")
+	})
+
+	t.Run("sad path: malformed response", func(t *testing.T) {
+		server := newFakeAdvisor(t, func(Request) (int, any) {
+			return http.StatusOK, "seven"
+		})
+		_, err := NewHTTPAdvisor(server.URL, time.Second).KeepAliveCount(ctx, req)
+		assert.ErrorContains(t, err, "failed to decode advisor response")
+	})
+
+	t.Run("sad path: negative keepAliveCount", func(t *testing.T) {
+		server := newFakeAdvisor(t, func(Request) (int, any) {
+			return http.StatusOK, Response{KeepAliveCount: -1}
+		})
+		_, err := NewHTTPAdvisor(server.URL, time.Second).KeepAliveCount(ctx, req)
+		assert.ErrorIs(t, err, ErrInvalidAdvice)
+	})
+
+	t.Run("sad path: advisor unreachable", func(t *testing.T) {
+		server := newFakeAdvisor(t, func(Request) (int, any) {
+			return http.StatusOK, Response{}
+		})
+		server.Close()
+		_, err := NewHTTPAdvisor(server.URL, time.Second).KeepAliveCount(ctx, req)
+		assert.ErrorContains(t, err, "failed to query advisor")
+	})
+
+	t.Run("sad path: advisor timeout", func(t *testing.T) {
+		server := newFakeAdvisor(t, func(Request) (int, any) {
+			time.Sleep(200 * time.Millisecond)
+			return http.StatusOK, Response{KeepAliveCount: 1}
+		})
+		_, err := NewHTTPAdvisor(server.URL, 50*time.Millisecond).KeepAliveCount(ctx, req)
+		assert.ErrorContains(t, err, "failed to query advisor")
+	})
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Azure/operation-cache-controller/internal/advisor (interfaces: KeepAliveAdvisor)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_advisor.go -package=mocks github.com/Azure/operation-cache-controller/internal/advisor KeepAliveAdvisor
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	advisor "github.com/Azure/operation-cache-controller/internal/advisor"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeepAliveAdvisor is a mock of KeepAliveAdvisor interface.
+type MockKeepAliveAdvisor struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeepAliveAdvisorMockRecorder
+	isgomock struct{}
+}
+
+// MockKeepAliveAdvisorMockRecorder is the mock recorder for MockKeepAliveAdvisor.
+type MockKeepAliveAdvisorMockRecorder struct {
+	mock *MockKeepAliveAdvisor
+}
+
+// NewMockKeepAliveAdvisor creates a new mock instance.
+func NewMockKeepAliveAdvisor(ctrl *gomock.Controller) *MockKeepAliveAdvisor {
+	mock := &MockKeepAliveAdvisor{ctrl: ctrl}
+	mock.recorder = &MockKeepAliveAdvisorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeepAliveAdvisor) EXPECT() *MockKeepAliveAdvisorMockRecorder {
+	return m.recorder
+}
+
+// KeepAliveCount mocks base method.
+func (m *MockKeepAliveAdvisor) KeepAliveCount(ctx context.Context, req advisor.Request) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeepAliveCount", ctx, req)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeepAliveCount indicates an expected call of KeepAliveCount.
+func (mr *MockKeepAliveAdvisorMockRecorder) KeepAliveCount(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepAliveCount", reflect.TypeOf((*MockKeepAliveAdvisor)(nil).KeepAliveCount), ctx, req)
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	APIVersion = "config.controller.azure.github.com/v1alpha1"
+	Kind       = "ControllerConfig"
+
+	DefaultRequeueDelay       = 10 * time.Second
+	DefaultCacheCheckInterval = 60 * time.Second
+	DefaultCacheExpireTime    = 2 * time.Hour
+
+	DefaultJobBackoffLimit            int32 = 0
+	DefaultJobTTLSecondsAfterFinished int32 = 3600
+	MinJobTTLSecondsAfterFinished     int32 = 600
+	DefaultJobRetryBaseDelay                = 10 * time.Second
+	DefaultJobRetryMaxDelay                 = 5 * time.Minute
+
+	DefaultExpiringSoonWindow    = 10 * time.Minute
+	DefaultExpiryMaxRequeueDelay = time.Hour
+
+	DefaultLeaseMaxLifetime = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid controller config")
+)
+
+// ControllerConfig holds the tunables of the controllers. Unset fields take their defaults.
+type ControllerConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// MaxConcurrentReconciles of each controller, only read at startup
+	MaxConcurrentReconciles ConcurrencyConfig `json:"maxConcurrentReconciles,omitempty"`
+	// RequeueDelay is how long a reconciler waits before it retries a failed or unfinished step
+	RequeueDelay metav1.Duration `json:"requeueDelay,omitempty"`
+	// CacheCheckInterval is how often the cache controller checks every cache
+	CacheCheckInterval metav1.Duration `json:"cacheCheckInterval,omitempty"`
+	// CacheExpireTime is how long a cache created for a requirement lives
+	CacheExpireTime metav1.Duration `json:"cacheExpireTime,omitempty"`
+	// Job holds the defaults of the provision and teardown jobs
+	Job JobConfig `json:"job,omitempty"`
+	// CachePool shares caches across namespaces, it is disabled when no namespace is set. Only read at startup, the
+	// namespace of the pool is watched from then on.
+	CachePool CachePoolConfig `json:"cachePool,omitempty"`
+	// Expiry tunes how the expiry of requirements, operations and caches is scheduled
+	Expiry ExpiryConfig `json:"expiry,omitempty"`
+	// Lease is the policy for the renewal of requirements by their consumers
+	Lease LeaseConfig `json:"lease,omitempty"`
+}
+
+type ConcurrencyConfig struct {
+	AppDeployment int `json:"appDeployment,omitempty"`
+	Operation     int `json:"operation,omitempty"`
+	Requirement   int `json:"requirement,omitempty"`
+	Cache         int `json:"cache,omitempty"`
+}
+
+type JobConfig struct {
+	// BackoffLimit of jobs which do not set their own
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// TTLSecondsAfterFinished of jobs which do not set their own
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// RetryBaseDelay and RetryMaxDelay bound the exponential backoff between retries of failed jobs
+	RetryBaseDelay metav1.Duration `json:"retryBaseDelay,omitempty"`
+	RetryMaxDelay  metav1.Duration `json:"retryMaxDelay,omitempty"`
+}
+
+type CachePoolConfig struct {
+	// Namespace holds the shared caches and their operations
+	Namespace string `json:"namespace,omitempty"`
+	// AllowedNamespaces lists the namespaces whose requirements acquire their operations from the pool, "*" allows
+	// all namespaces
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+type ExpiryConfig struct {
+	// ExpiringSoonWindow is how long before it expires an object is marked as expiring soon, so it can be extended
+	ExpiringSoonWindow metav1.Duration `json:"expiringSoonWindow,omitempty"`
+	// MaxRequeueDelay caps the wait for the next expiry check of an object which expires later
+	MaxRequeueDelay metav1.Duration `json:"maxRequeueDelay,omitempty"`
+}
+
+type LeaseConfig struct {
+	// MaxLifetime is how long after its creation a requirement can be renewed until at most
+	MaxLifetime metav1.Duration `json:"maxLifetime,omitempty"`
+}
+
+// AllNamespaces in the allowed namespaces of the cache pool allows every namespace
+const AllNamespaces = "*"
+
+// Allows reports whether the requirements of the namespace use the cache pool
+func (c CachePoolConfig) Allows(namespace string) bool {
+	if len(c.Namespace) == 0 {
+		return false
+	}
+	return slices.Contains(c.AllowedNamespaces, AllNamespaces) || slices.Contains(c.AllowedNamespaces, namespace)
+}
+
+// Default returns the config the controllers run with when no config file is given
+func Default() *ControllerConfig {
+	cfg := &ControllerConfig{TypeMeta: metav1.TypeMeta{APIVersion: APIVersion, Kind: Kind}}
+	cfg.SetDefaults()
+	return cfg
+}
+
+// SetDefaults fills in the unset fields
+func (c *ControllerConfig) SetDefaults() {
+	setDefault := func(value *int, defaultValue int) {
+		if *value == 0 {
+			*value = defaultValue
+		}
+	}
+	setDefault(&c.MaxConcurrentReconciles.AppDeployment, 100)
+	setDefault(&c.MaxConcurrentReconciles.Operation, 100)
+	setDefault(&c.MaxConcurrentReconciles.Requirement, 100)
+	setDefault(&c.MaxConcurrentReconciles.Cache, 50)
+
+	setDefaultDuration := func(value *metav1.Duration, defaultValue time.Duration) {
+		if value.Duration == 0 {
+			value.Duration = defaultValue
+		}
+	}
+	setDefaultDuration(&c.RequeueDelay, DefaultRequeueDelay)
+	setDefaultDuration(&c.CacheCheckInterval, DefaultCacheCheckInterval)
+	setDefaultDuration(&c.CacheExpireTime, DefaultCacheExpireTime)
+	setDefaultDuration(&c.Job.RetryBaseDelay, DefaultJobRetryBaseDelay)
+	setDefaultDuration(&c.Job.RetryMaxDelay, DefaultJobRetryMaxDelay)
+	setDefaultDuration(&c.Expiry.ExpiringSoonWindow, DefaultExpiringSoonWindow)
+	setDefaultDuration(&c.Expiry.MaxRequeueDelay, DefaultExpiryMaxRequeueDelay)
+	setDefaultDuration(&c.Lease.MaxLifetime, DefaultLeaseMaxLifetime)
+
+	if c.Job.BackoffLimit == nil {
+		backoffLimit := DefaultJobBackoffLimit
+		c.Job.BackoffLimit = &backoffLimit
+	}
+	if c.Job.TTLSecondsAfterFinished == nil {
+		ttl := DefaultJobTTLSecondsAfterFinished
+		c.Job.TTLSecondsAfterFinished = &ttl
+	}
+}
+
+// MinJobTTLSecondsAfterFinished returns the shortest time finished jobs are kept. The controller reads the result of a
+// finished job on a later reconcile, a job deleted before that is provisioned again.
+func (c *ControllerConfig) MinJobTTLSecondsAfterFinished() int32 {
+	longestInterval := max(c.RequeueDelay.Duration, c.CacheCheckInterval.Duration)
+	return max(MinJobTTLSecondsAfterFinished, int32(10*longestInterval/time.Second))
+}
+
+// Validate checks a defaulted config
+func (c *ControllerConfig) Validate() error {
+	var errs error
+	if c.APIVersion != APIVersion || c.Kind != Kind {
+		errs = errors.Join(errs, fmt.Errorf("unsupported config %s %s, expected %s %s", c.APIVersion, c.Kind, APIVersion, Kind))
+	}
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"maxConcurrentReconciles.appDeployment", c.MaxConcurrentReconciles.AppDeployment},
+		{"maxConcurrentReconciles.operation", c.MaxConcurrentReconciles.Operation},
+		{"maxConcurrentReconciles.requirement", c.MaxConcurrentReconciles.Requirement},
+		{"maxConcurrentReconciles.cache", c.MaxConcurrentReconciles.Cache},
+	} {
+		if field.value < 1 {
+			errs = errors.Join(errs, fmt.Errorf("%s must be at least 1", field.name))
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"requeueDelay", c.RequeueDelay.Duration},
+		{"cacheCheckInterval", c.CacheCheckInterval.Duration},
+		{"cacheExpireTime", c.CacheExpireTime.Duration},
+		{"job.retryBaseDelay", c.Job.RetryBaseDelay.Duration},
+		{"job.retryMaxDelay", c.Job.RetryMaxDelay.Duration},
+		{"expiry.expiringSoonWindow", c.Expiry.ExpiringSoonWindow.Duration},
+		{"expiry.maxRequeueDelay", c.Expiry.MaxRequeueDelay.Duration},
+		{"lease.maxLifetime", c.Lease.MaxLifetime.Duration},
+	} {
+		if field.value <= 0 {
+			errs = errors.Join(errs, fmt.Errorf("%s must be positive", field.name))
+		}
+	}
+	if c.Job.RetryMaxDelay.Duration < c.Job.RetryBaseDelay.Duration {
+		errs = errors.Join(errs, fmt.Errorf("job.retryMaxDelay must not be less than job.retryBaseDelay"))
+	}
+	if c.Job.BackoffLimit != nil && *c.Job.BackoffLimit < 0 {
+		errs = errors.Join(errs, fmt.Errorf("job.backoffLimit must not be negative"))
+	}
+	if c.Job.TTLSecondsAfterFinished != nil && *c.Job.TTLSecondsAfterFinished < c.MinJobTTLSecondsAfterFinished() {
+		errs = errors.Join(errs, fmt.Errorf("job.ttlSecondsAfterFinished must be at least %d", c.MinJobTTLSecondsAfterFinished()))
+	}
+	if len(c.CachePool.Namespace) > 0 {
+		for _, msg := range validation.IsDNS1123Label(c.CachePool.Namespace) {
+			errs = errors.Join(errs, fmt.Errorf("cachePool.namespace: %s", msg))
+		}
+	} else if len(c.CachePool.AllowedNamespaces) > 0 {
+		errs = errors.Join(errs, fmt.Errorf("cachePool.allowedNamespaces requires cachePool.namespace"))
+	}
+	for _, ns := range c.CachePool.AllowedNamespaces {
+		if ns == AllNamespaces {
+			continue
+		}
+		for _, msg := range validation.IsDNS1123Label(ns) {
+			errs = errors.Join(errs, fmt.Errorf("cachePool.allowedNamespaces %q: %s", ns, msg))
+		}
+	}
+	if errs != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errs)
+	}
+	return nil
+}
+
+// Parse reads a config document, defaults and validates it. Unknown fields are rejected so typos do not go unnoticed.
+func Parse(data []byte) (*ControllerConfig, error) {
+	cfg := &ControllerConfig{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+var current atomic.Pointer[ControllerConfig]
+
+func init() {
+	current.Store(Default())
+}
+
+// Current returns the config the controllers run with, it changes when the config file is reloaded
+func Current() *ControllerConfig {
+	return current.Load()
+}
+
+// Set replaces the config the controllers run with
+func Set(cfg *ControllerConfig) {
+	current.Store(cfg)
+}
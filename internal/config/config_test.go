@@ -0,0 +1,105 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := Parse([]byte("apiVersion: " + APIVersion + "\nkind: " + Kind + "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, Default(), cfg)
+		assert.Equal(t, 100, cfg.MaxConcurrentReconciles.AppDeployment)
+		assert.Equal(t, 50, cfg.MaxConcurrentReconciles.Cache)
+		assert.Equal(t, DefaultRequeueDelay, cfg.RequeueDelay.Duration)
+		assert.Equal(t, DefaultJobBackoffLimit, *cfg.Job.BackoffLimit)
+		assert.Equal(t, DefaultJobTTLSecondsAfterFinished, *cfg.Job.TTLSecondsAfterFinished)
+	})
+
+	t.Run("tunables", func(t *testing.T) {
+		cfg, err := Parse([]byte(`
+apiVersion: config.controller.azure.github.com/v1alpha1
+kind: ControllerConfig
+maxConcurrentReconciles:
+  cache: 10
+requeueDelay: 30s
+cacheCheckInterval: 5m
+cacheExpireTime: 24h
+job:
+  backoffLimit: 2
+  ttlSecondsAfterFinished: 3000
+  retryBaseDelay: 1m
+  retryMaxDelay: 1h
+expiry:
+  expiringSoonWindow: 30m
+lease:
+  maxLifetime: 48h
+`))
+		require.NoError(t, err)
+		assert.Equal(t, 10, cfg.MaxConcurrentReconciles.Cache)
+		assert.Equal(t, 100, cfg.MaxConcurrentReconciles.Operation)
+		assert.Equal(t, 30*time.Second, cfg.RequeueDelay.Duration)
+		assert.Equal(t, 5*time.Minute, cfg.CacheCheckInterval.Duration)
+		assert.Equal(t, 24*time.Hour, cfg.CacheExpireTime.Duration)
+		assert.Equal(t, int32(2), *cfg.Job.BackoffLimit)
+		// an explicit value is kept
+		assert.Equal(t, int32(3000), *cfg.Job.TTLSecondsAfterFinished)
+		assert.Equal(t, time.Minute, cfg.Job.RetryBaseDelay.Duration)
+		assert.Equal(t, time.Hour, cfg.Job.RetryMaxDelay.Duration)
+		assert.Equal(t, 30*time.Minute, cfg.Expiry.ExpiringSoonWindow.Duration)
+		assert.Equal(t, DefaultExpiryMaxRequeueDelay, cfg.Expiry.MaxRequeueDelay.Duration)
+		assert.Equal(t, 48*time.Hour, cfg.Lease.MaxLifetime.Duration)
+	})
+
+	invalid := map[string]string{
+		"unknown version":  "apiVersion: config.controller.azure.github.com/v2\nkind: ControllerConfig\n",
+		"missing kind":     "apiVersion: " + APIVersion + "\n",
+		"unknown field":    "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nrequeueDelays: 1s\n",
+		"negative delay":   "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nrequeueDelay: -1s\n",
+		"bad duration":     "apiVersion: " + APIVersion + "\nkind: " + Kind + "\ncacheExpireTime: soon\n",
+		"concurrency":      "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nmaxConcurrentReconciles:\n  operation: -1\n",
+		"backoff limit":    "apiVersion: " + APIVersion + "\nkind: " + Kind + "\njob:\n  backoffLimit: -1\n",
+		"job ttl":          "apiVersion: " + APIVersion + "\nkind: " + Kind + "\njob:\n  ttlSecondsAfterFinished: 0\n",
+		"job ttl interval": "apiVersion: " + APIVersion + "\nkind: " + Kind + "\ncacheCheckInterval: 5m\njob:\n  ttlSecondsAfterFinished: 600\n",
+		"retry delays":     "apiVersion: " + APIVersion + "\nkind: " + Kind + "\njob:\n  retryBaseDelay: 1h\n  retryMaxDelay: 1m\n",
+		"expiry window":    "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nexpiry:\n  expiringSoonWindow: -1m\n",
+		"lease lifetime":   "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nlease:\n  maxLifetime: -1h\n",
+		"pool namespace":   "apiVersion: " + APIVersion + "\nkind: " + Kind + "\ncachePool:\n  namespace: Pool\n",
+		"pool allowed":     "apiVersion: " + APIVersion + "\nkind: " + Kind + "\ncachePool:\n  allowedNamespaces: [team-a]\n",
+	}
+	for name, doc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(doc))
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
+
+func TestCachePoolAllows(t *testing.T) {
+	assert.False(t, CachePoolConfig{AllowedNamespaces: []string{AllNamespaces}}.Allows("team-a"))
+
+	pool := CachePoolConfig{Namespace: "pool", AllowedNamespaces: []string{"team-a"}}
+	assert.True(t, pool.Allows("team-a"))
+	assert.False(t, pool.Allows("team-b"))
+
+	pool.AllowedNamespaces = []string{AllNamespaces}
+	assert.True(t, pool.Allows("team-b"))
+
+	cfg, err := Parse([]byte("apiVersion: " + APIVersion + "\nkind: " + Kind + "\ncachePool:\n  namespace: pool\n  allowedNamespaces: [team-a, \"*\"]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "pool", cfg.CachePool.Namespace)
+}
+
+func TestCurrent(t *testing.T) {
+	t.Cleanup(func() { Set(Default()) })
+	assert.Equal(t, Default(), Current())
+
+	cfg := Default()
+	cfg.RequeueDelay.Duration = time.Minute
+	Set(cfg)
+	assert.Equal(t, time.Minute, Current().RequeueDelay.Duration)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// Watcher loads the config file and reloads it when its content changes. The file is polled since a mounted config
+// map is updated by swapping symlinks. Overrides, e.g. the flags set on the command line, are applied on top of every
+// loaded config.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	override func(*ControllerConfig)
+	logger   logr.Logger
+
+	data []byte
+}
+
+func NewWatcher(path string, interval time.Duration, override func(*ControllerConfig), logger logr.Logger) *Watcher {
+	return &Watcher{
+		path:     path,
+		interval: interval,
+		override: override,
+		logger:   logger,
+	}
+}
+
+// Load loads the config file and makes it the current config
+func (w *Watcher) Load() error {
+	_, err := w.reload()
+	return err
+}
+
+// Start reloads the config file until the context is done
+func (w *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			reloaded, err := w.reload()
+			if err != nil {
+				// keep running with the last valid config
+				w.logger.Error(err, "failed to reload controller config", "path", w.path)
+				continue
+			}
+			if reloaded {
+				w.logger.Info("reloaded controller config", "path", w.path)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection is false since every replica runs with the config, e.g. the webhooks
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// reload makes the config file the current config if its content changed
+func (w *Watcher) reload() (bool, error) {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read controller config %s: %w", w.path, err)
+	}
+	if w.data != nil && bytes.Equal(data, w.data) {
+		return false, nil
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return false, err
+	}
+	if w.override != nil {
+		w.override(cfg)
+		if err := cfg.Validate(); err != nil {
+			return false, err
+		}
+	}
+	if previous := Current(); w.data != nil {
+		if previous.MaxConcurrentReconciles != cfg.MaxConcurrentReconciles {
+			w.logger.Info("maxConcurrentReconciles changed, it takes effect after a restart", "path", w.path)
+		}
+		// the namespace of the cache pool is only watched if it is configured at startup, the pool keeps its startup
+		// config until the restart
+		if !equality.Semantic.DeepEqual(previous.CachePool, cfg.CachePool) {
+			w.logger.Info("cachePool changed, it is ignored until a restart", "path", w.path)
+			cfg.CachePool = previous.CachePool
+		}
+	}
+	w.data = data
+	Set(cfg)
+	return true, nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Cleanup(func() { Set(Default()) })
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(requeueDelay string) {
+		doc := "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nrequeueDelay: " + requeueDelay + "\n"
+		require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+	}
+	override := func(c *ControllerConfig) { c.CacheExpireTime.Duration = time.Hour }
+	watcher := NewWatcher(path, 10*time.Millisecond, override, logr.Discard())
+
+	t.Run("load", func(t *testing.T) {
+		write("20s")
+		require.NoError(t, watcher.Load())
+		assert.Equal(t, 20*time.Second, Current().RequeueDelay.Duration)
+		// overrides apply on top of the file
+		assert.Equal(t, time.Hour, Current().CacheExpireTime.Duration)
+	})
+
+	t.Run("reload on change", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- watcher.Start(ctx) }()
+		defer func() {
+			cancel()
+			assert.NoError(t, <-done)
+		}()
+
+		write("40s")
+		assert.Eventually(t, func() bool { return Current().RequeueDelay.Duration == 40*time.Second }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, time.Hour, Current().CacheExpireTime.Duration)
+
+		// an invalid config is ignored, the last valid one stays current
+		write("-1s")
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, 40*time.Second, Current().RequeueDelay.Duration)
+	})
+
+	t.Run("cache pool is only read at startup", func(t *testing.T) {
+		poolWatcher := NewWatcher(path, time.Second, nil, logr.Discard())
+		doc := "apiVersion: " + APIVersion + "\nkind: " + Kind + "\ncachePool:\n  namespace: pool\n"
+		require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+		require.NoError(t, poolWatcher.Load())
+		assert.Equal(t, "pool", Current().CachePool.Namespace)
+
+		// the other fields of the changed file are applied, the cache pool keeps its startup config
+		doc = "apiVersion: " + APIVersion + "\nkind: " + Kind + "\nrequeueDelay: 30s\ncachePool:\n  namespace: other-pool\n"
+		require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+		reloaded, err := poolWatcher.reload()
+		require.NoError(t, err)
+		assert.True(t, reloaded)
+		assert.Equal(t, 30*time.Second, Current().RequeueDelay.Duration)
+		assert.Equal(t, "pool", Current().CachePool.Namespace)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		missing := NewWatcher(filepath.Join(t.TempDir(), "missing.yaml"), time.Second, nil, logr.Discard())
+		assert.Error(t, missing.Load())
+	})
+}
@@ -19,18 +19,26 @@ package controller
 import (
 	"context"
 
+	"github.com/samber/lo"
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
 	klog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	"github.com/Azure/operation-cache-controller/internal/handler"
 	"github.com/Azure/operation-cache-controller/internal/log"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
@@ -47,6 +55,10 @@ type AppDeploymentReconciler struct {
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=batch,resources=jobs/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -59,7 +71,7 @@ type AppDeploymentReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
 func (r *AppDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := klog.FromContext(ctx).WithValues(log.FieldKeyAppDeploymentJobName, req.NamespacedName)
-	appdeployment := &v1alpha1.AppDeployment{}
+	appdeployment := &v1beta1.AppDeployment{}
 	if err := r.Get(ctx, req.NamespacedName, appdeployment); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -94,25 +106,64 @@ func appDeploymentIndexerFunc(rawObj client.Object) []string {
 	if owner == nil {
 		return nil
 	}
-	if owner.APIVersion != v1alpha1.GroupVersion.String() || owner.Kind != "AppDeployment" {
+	if !ctrlutils.IsOwnerOfKind(owner, "AppDeployment") {
 		return nil
 	}
 	return []string{owner.Name}
 }
 
+// appDeploymentDependencyIndexerFunc indexes an app deployment by the names of the app deployments it depends on
+func appDeploymentDependencyIndexerFunc(rawObj client.Object) []string {
+	appDeployment := rawObj.(*v1beta1.AppDeployment)
+	return lo.Map(appDeployment.Spec.Dependencies, func(dep string, _ int) string {
+		return ctrlutils.OperationScopedAppDeployment(dep, appDeployment.Spec.OperationID)
+	})
+}
+
+// dependencyReadyPredicate passes the app deployments which became ready, so their dependents can start provisioning
+var dependencyReadyPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return e.Object.(*v1beta1.AppDeployment).Status.Phase == v1beta1.AppDeploymentPhaseReady
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.(*v1beta1.AppDeployment).Status.Phase != v1beta1.AppDeploymentPhaseReady &&
+			e.ObjectNew.(*v1beta1.AppDeployment).Status.Phase == v1beta1.AppDeploymentPhaseReady
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// dependentAppDeployments maps an app deployment to the app deployments depending on it
+func (r *AppDeploymentReconciler) dependentAppDeployments(ctx context.Context, obj client.Object) []reconcile.Request {
+	dependents := &v1beta1.AppDeploymentList{}
+	if err := r.List(ctx, dependents, client.InNamespace(obj.GetNamespace()), client.MatchingFields{v1beta1.AppDeploymentDependencyKey: obj.GetName()}); err != nil {
+		klog.FromContext(ctx).Error(err, "failed to list dependent app deployments", "dependency", obj.GetName())
+		return nil
+	}
+	return lo.Map(dependents.Items, func(app v1beta1.AppDeployment, _ int) reconcile.Request {
+		return reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&app)}
+	})
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AppDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.Job{}, v1alpha1.AppDeploymentOwnerKey, appDeploymentIndexerFunc); err != nil {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &batchv1.Job{}, v1beta1.AppDeploymentOwnerKey, appDeploymentIndexerFunc); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.AppDeployment{}, v1beta1.AppDeploymentDependencyKey, appDeploymentDependencyIndexerFunc); err != nil {
 		return err
 	}
 
 	r.recorder = mgr.GetEventRecorderFor("AppDeployment")
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.AppDeployment{}).
+		For(&v1beta1.AppDeployment{}).
 		Owns(&batchv1.Job{}).
+		Watches(&v1beta1.AppDeployment{},
+			crhandler.EnqueueRequestsFromMapFunc(r.dependentAppDeployments),
+			builder.WithPredicates(dependencyReadyPredicate)).
 		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 100,
+			MaxConcurrentReconciles: config.Current().MaxConcurrentReconciles.AppDeployment,
 		}).
 		Named("appdeployment").
 		Complete(r)
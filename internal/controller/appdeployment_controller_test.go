@@ -26,13 +26,14 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 	"github.com/Azure/operation-cache-controller/internal/handler"
 	hmocks "github.com/Azure/operation-cache-controller/internal/handler/mocks"
 	utilsmock "github.com/Azure/operation-cache-controller/internal/utils/mocks"
@@ -98,11 +99,11 @@ var _ = Describe("AppDeployment Controller", func() {
 			Name:      resourceName,
 			Namespace: "default", // TODO(user):Modify as needed
 		}
-		appdeployment := &v1alpha1.AppDeployment{
-			Spec: v1alpha1.AppDeploymentSpec{
-				OpId:      "test-op-id",
-				Provision: newTestJobSpec(),
-				Teardown:  newTestJobSpec(),
+		appdeployment := &v1beta1.AppDeployment{
+			Spec: v1beta1.AppDeploymentSpec{
+				OperationID: "test-op-id",
+				Provision:   newTestJobSpec(),
+				Teardown:    newTestJobSpec(),
 			},
 		}
 
@@ -110,12 +111,12 @@ var _ = Describe("AppDeployment Controller", func() {
 			By("creating the custom resource for the Kind AppDeployment")
 			err := k8sClient.Get(ctx, typeNamespacedName, appdeployment)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &v1alpha1.AppDeployment{
+				resource := &v1beta1.AppDeployment{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					Spec: v1alpha1.AppDeploymentSpec{
+					Spec: v1beta1.AppDeploymentSpec{
 						Provision: newTestJobSpec(),
 						Teardown:  newTestJobSpec(),
 					}}
@@ -129,7 +130,7 @@ var _ = Describe("AppDeployment Controller", func() {
 
 		AfterEach(func() {
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &v1alpha1.AppDeployment{}
+			resource := &v1beta1.AppDeployment{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -238,7 +239,7 @@ var _ = Describe("AppDeployment Controller", func() {
 					Namespace: "default",
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: v1alpha1.GroupVersion.String(),
+							APIVersion: v1beta1.GroupVersion.String(),
 							Kind:       "AppDeployment",
 							Name:       ownerName,
 							UID:        "67890",
@@ -259,7 +260,7 @@ var _ = Describe("AppDeployment Controller", func() {
 					Namespace: "default",
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: v1alpha1.GroupVersion.String(),
+							APIVersion: v1beta1.GroupVersion.String(),
 							Kind:       "AppDeployment",
 							Name:       "test-appdeployment",
 							UID:        "13579",
@@ -273,4 +274,29 @@ var _ = Describe("AppDeployment Controller", func() {
 			Expect(result).To(BeNil())
 		})
 	})
+
+	Context("dependency watch tests", func() {
+		It("should index an AppDeployment by its operation scoped dependencies", func() {
+			app := &v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID:  "test-op",
+					Dependencies: []string{"db", "cache"},
+				},
+			}
+			Expect(appDeploymentDependencyIndexerFunc(app)).To(Equal([]string{"test-op-db", "test-op-cache"}))
+			Expect(appDeploymentDependencyIndexerFunc(&v1beta1.AppDeployment{})).To(BeEmpty())
+		})
+
+		It("should only pass AppDeployments which became ready", func() {
+			pending := &v1beta1.AppDeployment{Status: v1beta1.AppDeploymentStatus{Phase: v1beta1.AppDeploymentPhaseDeploying}}
+			ready := &v1beta1.AppDeployment{Status: v1beta1.AppDeploymentStatus{Phase: v1beta1.AppDeploymentPhaseReady}}
+
+			Expect(dependencyReadyPredicate.Update(event.UpdateEvent{ObjectOld: pending, ObjectNew: ready})).To(BeTrue())
+			Expect(dependencyReadyPredicate.Update(event.UpdateEvent{ObjectOld: ready, ObjectNew: ready})).To(BeFalse())
+			Expect(dependencyReadyPredicate.Update(event.UpdateEvent{ObjectOld: pending, ObjectNew: pending})).To(BeFalse())
+			Expect(dependencyReadyPredicate.Create(event.CreateEvent{Object: ready})).To(BeTrue())
+			Expect(dependencyReadyPredicate.Create(event.CreateEvent{Object: pending})).To(BeFalse())
+			Expect(dependencyReadyPredicate.Delete(event.DeleteEvent{Object: ready})).To(BeFalse())
+		})
+	})
 })
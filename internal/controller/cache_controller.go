@@ -28,19 +28,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/advisor"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	"github.com/Azure/operation-cache-controller/internal/handler"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
-const (
-	defaultCacheCheckInterval = time.Second * 60
-)
-
 // CacheReconciler reconciles a Cache object
 type CacheReconciler struct {
 	client.Client
-	Scheme   *runtime.Scheme
+	Scheme *runtime.Scheme
+	// Advisor is the optional external keep-alive advisor
+	Advisor  advisor.KeepAliveAdvisor
 	recorder record.EventRecorder
 }
 
@@ -48,7 +49,8 @@ type CacheReconciler struct {
 // +kubebuilder:rbac:groups=controller.azure.github.com,resources=caches/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=controller.azure.github.com,resources=caches/finalizers,verbs=update
 // +kubebuilder:rbac:groups=controller.azure.github.com,resources=operations,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=controller.azure.github.com,resources=operations/status,verbs=get
+// +kubebuilder:rbac:groups=controller.azure.github.com,resources=operations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -61,12 +63,12 @@ type CacheReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.1/pkg/reconcile
 func (r *CacheReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
-	cache := &v1alpha1.Cache{}
+	cache := &v1beta1.Cache{}
 	if err := r.Get(ctx, req.NamespacedName, cache); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	return r.reconcileHandler(ctx, handler.NewCacheHandler(ctx, cache, logger, r.Client, r.Scheme, r.recorder, ctrl.SetControllerReference))
+	return r.reconcileHandler(ctx, handler.NewCacheHandler(ctx, cache, logger, r.Client, r.Scheme, r.recorder, ctrl.SetControllerReference, r.Advisor))
 }
 
 func (r *CacheReconciler) reconcileHandler(ctx context.Context, h handler.CacheHandlerInterface) (ctrl.Result, error) {
@@ -74,35 +76,41 @@ func (r *CacheReconciler) reconcileHandler(ctx context.Context, h handler.CacheH
 
 	operations := []reconciler.ReconcileOperation{
 		h.CheckCacheExpiry,
+		h.EnsureCacheKeyMigrated,
 		h.EnsureCacheInitialized,
 		h.CalculateKeepAliveCount,
 		h.AdjustCache,
 	}
 
+	// the cache is checked every interval, and by the time it expires if that is sooner
+	var requeueWithin time.Duration
+	requeueAfter := config.Current().CacheCheckInterval.Duration
 	for _, operation := range operations {
 		result, err := operation(ctx)
+		requeueWithin = reconciler.Sooner(requeueWithin, result.RequeueWithin)
 		if err != nil || result.RequeueRequest {
 			logger.Error(err, "cache operation failed")
-			return ctrl.Result{RequeueAfter: result.RequeueDelay}, err
+			return ctrl.Result{RequeueAfter: reconciler.Sooner(result.RequeueDelay, requeueWithin)}, err
 		}
+		requeueAfter = reconciler.Sooner(requeueAfter, requeueWithin)
 		if result.CancelRequest {
-			logger.Info("cache reconcile canceled, requeue after 60 seconds")
-			return ctrl.Result{RequeueAfter: defaultCacheCheckInterval}, nil
+			logger.Info("cache reconcile canceled", "requeueAfter", requeueAfter)
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 		}
 	}
-	logger.Info("cache reconcile completed, requeue after 60 seconds")
-	return ctrl.Result{RequeueAfter: defaultCacheCheckInterval}, nil
+	logger.Info("cache reconcile completed", "requeueAfter", requeueAfter)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func cacheOperationIndexerFunc(obj client.Object) []string {
 	// grab the operation object, extract the owner...
-	operation := obj.(*v1alpha1.Operation)
+	operation := obj.(*v1beta1.Operation)
 	owner := metav1.GetControllerOf(operation)
 	if owner == nil {
 		return nil
 	}
 	// ...make sure it's a Cache...
-	if owner.APIVersion != v1alpha1.GroupVersion.String() || owner.Kind != "Cache" {
+	if !ctrlutils.IsOwnerOfKind(owner, "Cache") {
 		return nil
 	}
 	// ...and if so, return it
@@ -111,17 +119,17 @@ func cacheOperationIndexerFunc(obj client.Object) []string {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *CacheReconciler) SetupWithManager(mgr ctrl.Manager) error { // +gocover:ignore:block init controller
-	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Operation{}, v1alpha1.CacheOwnerKey, cacheOperationIndexerFunc); err != nil { // +gocover:ignore:block init controller
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.Operation{}, v1beta1.CacheOwnerKey, cacheOperationIndexerFunc); err != nil { // +gocover:ignore:block init controller
 		return err
 	}
 	// +gocover:ignore:block init controller
 	r.recorder = mgr.GetEventRecorderFor("Cache")
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Cache{}).
-		Owns(&v1alpha1.Operation{}).
+		For(&v1beta1.Cache{}).
+		Owns(&v1beta1.Operation{}).
 		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 50,
+			MaxConcurrentReconciles: config.Current().MaxConcurrentReconciles.Cache,
 		}).
 		Named("cache").
 		Complete(r)
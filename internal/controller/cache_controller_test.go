@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -30,7 +31,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	"github.com/Azure/operation-cache-controller/internal/handler/mocks"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
@@ -64,12 +66,13 @@ func TestReconcileHandler(t *testing.T) {
 		mockCacheAdapterCtrl := gomock.NewController(t)
 		cacheAdapter := mocks.NewMockCacheHandlerInterface(mockCacheAdapterCtrl)
 		cacheAdapter.EXPECT().CheckCacheExpiry(ctx).Return(reconciler.OperationResult{}, nil)
+		cacheAdapter.EXPECT().EnsureCacheKeyMigrated(ctx).Return(reconciler.OperationResult{}, nil)
 		cacheAdapter.EXPECT().EnsureCacheInitialized(ctx).Return(reconciler.OperationResult{}, nil)
 		cacheAdapter.EXPECT().CalculateKeepAliveCount(ctx).Return(reconciler.OperationResult{}, nil)
 		cacheAdapter.EXPECT().AdjustCache(ctx).Return(reconciler.OperationResult{}, nil)
 		res, err := cacheReconciler.reconcileHandler(ctx, cacheAdapter)
 		assert.NoError(t, err)
-		assert.Equal(t, defaultCacheCheckInterval, res.RequeueAfter)
+		assert.Equal(t, config.DefaultCacheCheckInterval, res.RequeueAfter)
 	})
 
 	t.Run("reconcile canceled", func(t *testing.T) {
@@ -87,7 +90,19 @@ func TestReconcileHandler(t *testing.T) {
 		cacheAdapter.EXPECT().CheckCacheExpiry(ctx).Return(reconciler.OperationResult{CancelRequest: true}, nil)
 		res, err := cacheReconciler.reconcileHandler(ctx, cacheAdapter)
 		assert.NoError(t, err)
-		assert.Equal(t, defaultCacheCheckInterval, res.RequeueAfter)
+		assert.Equal(t, config.DefaultCacheCheckInterval, res.RequeueAfter)
+	})
+
+	t.Run("reconcile again when the cache expires", func(t *testing.T) {
+		cacheReconciler := CacheReconciler{}
+		mockCacheAdapterCtrl := gomock.NewController(t)
+		cacheAdapter := mocks.NewMockCacheHandlerInterface(mockCacheAdapterCtrl)
+		cacheAdapter.EXPECT().CheckCacheExpiry(ctx).Return(reconciler.OperationResult{RequeueWithin: time.Second}, nil)
+		cacheAdapter.EXPECT().EnsureCacheKeyMigrated(ctx).Return(reconciler.OperationResult{}, nil)
+		cacheAdapter.EXPECT().EnsureCacheInitialized(ctx).Return(reconciler.OperationResult{CancelRequest: true}, nil)
+		res, err := cacheReconciler.reconcileHandler(ctx, cacheAdapter)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, res.RequeueAfter)
 	})
 
 	t.Run("reconcile err", func(t *testing.T) {
@@ -111,12 +126,12 @@ func TestReconcileHandler(t *testing.T) {
 func TestCacheOperationIndexerFunc(t *testing.T) {
 	t.Run("with Cache owner", func(t *testing.T) {
 		// Create an operation with a Cache owner
-		operation := &v1alpha1.Operation{
+		operation := &v1beta1.Operation{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "test-operation",
 				OwnerReferences: []metav1.OwnerReference{
 					{
-						APIVersion: v1alpha1.GroupVersion.String(),
+						APIVersion: v1beta1.GroupVersion.String(),
 						Kind:       "Cache",
 						Name:       "test-cache",
 						Controller: func() *bool { b := true; return &b }(),
@@ -134,7 +149,7 @@ func TestCacheOperationIndexerFunc(t *testing.T) {
 
 	t.Run("with no owner", func(t *testing.T) {
 		// Create an operation with no owner
-		operation := &v1alpha1.Operation{
+		operation := &v1beta1.Operation{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "test-operation",
 			},
@@ -149,12 +164,12 @@ func TestCacheOperationIndexerFunc(t *testing.T) {
 
 	t.Run("with non-Cache owner", func(t *testing.T) {
 		// Create an operation with a non-Cache owner
-		operation := &v1alpha1.Operation{
+		operation := &v1beta1.Operation{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "test-operation",
 				OwnerReferences: []metav1.OwnerReference{
 					{
-						APIVersion: v1alpha1.GroupVersion.String(),
+						APIVersion: v1beta1.GroupVersion.String(),
 						Kind:       "Requirement",
 						Name:       "test-requirement",
 						Controller: func() *bool { b := true; return &b }(),
@@ -172,12 +187,12 @@ func TestCacheOperationIndexerFunc(t *testing.T) {
 
 	t.Run("with non-controller owner", func(t *testing.T) {
 		// Create an operation with a non-controller owner reference
-		operation := &v1alpha1.Operation{
+		operation := &v1beta1.Operation{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "test-operation",
 				OwnerReferences: []metav1.OwnerReference{
 					{
-						APIVersion: v1alpha1.GroupVersion.String(),
+						APIVersion: v1beta1.GroupVersion.String(),
 						Kind:       "Cache",
 						Name:       "test-cache",
 						Controller: func() *bool { b := false; return &b }(),
@@ -195,7 +210,7 @@ func TestCacheOperationIndexerFunc(t *testing.T) {
 
 	t.Run("with different API version", func(t *testing.T) {
 		// Create an operation with a different API version
-		operation := &v1alpha1.Operation{
+		operation := &v1beta1.Operation{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "test-operation",
 				OwnerReferences: []metav1.OwnerReference{
@@ -249,20 +264,20 @@ var _ = Describe("Cache Controller", func() {
 	// 		Name:      resourceName,
 	// 		Namespace: "default", // TODO(user):Modify as needed
 	// 	}
-	// 	cache := &v1alpha1.Cache{}
+	// 	cache := &v1beta1.Cache{}
 
 	// 	BeforeEach(func() {
 	// 		By("creating the custom resource for the Kind Cache")
 	// 		err := k8sClient.Get(ctx, typeNamespacedName, cache)
 	// 		if err != nil && errors.IsNotFound(err) {
-	// 			resource := &v1alpha1.Cache{
+	// 			resource := &v1beta1.Cache{
 	// 				ObjectMeta: metav1.ObjectMeta{
 	// 					Name:      resourceName,
 	// 					Namespace: "default",
 	// 				},
-	// 				Spec: v1alpha1.CacheSpec{
-	// 					OperationTemplate: v1alpha1.OperationSpec{
-	// 						Applications: []v1alpha1.ApplicationSpec{
+	// 				Spec: v1beta1.CacheSpec{
+	// 					OperationTemplate: v1beta1.OperationSpec{
+	// 						Applications: []v1beta1.ApplicationSpec{
 	// 							{
 	// 								Name:      "app1",
 	// 								Provision: newTestJobSpec(),
@@ -280,7 +295,7 @@ var _ = Describe("Cache Controller", func() {
 
 	// 	AfterEach(func() {
 	// 		// TODO(user): Cleanup logic after each test, like removing the resource instance.
-	// 		resource := &v1alpha1.Cache{}
+	// 		resource := &v1beta1.Cache{}
 	// 		err := k8sClient.Get(ctx, typeNamespacedName, resource)
 	// 		Expect(err).NotTo(HaveOccurred())
 
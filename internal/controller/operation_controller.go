@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -27,8 +28,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	"github.com/Azure/operation-cache-controller/internal/handler"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
@@ -55,7 +58,7 @@ type OperationReconciler struct {
 func (r *OperationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	operation := &v1alpha1.Operation{}
+	operation := &v1beta1.Operation{}
 	if err := r.Get(ctx, req.NamespacedName, operation); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -73,28 +76,34 @@ func (r *OperationReconciler) ReconcileHandler(ctx context.Context, h handler.Op
 		h.EnsureAllAppsAreDeleted,
 	}
 
+	// the operation is reconciled again by the time it expires, even if nothing else changes
+	var requeueWithin time.Duration
 	for _, operation := range operations {
 		result, err := operation(ctx)
-		if err != nil || result.RequeueRequest {
-			return ctrl.Result{RequeueAfter: reconciler.DefaultRequeueDelay}, err
+		if err != nil {
+			return ctrl.Result{RequeueAfter: reconciler.RequeueDelay()}, err
+		}
+		requeueWithin = reconciler.Sooner(requeueWithin, result.RequeueWithin)
+		if result.RequeueRequest {
+			return ctrl.Result{RequeueAfter: reconciler.Sooner(reconciler.RequeueDelay(), requeueWithin)}, nil
 		}
 		if result.CancelRequest {
-			return ctrl.Result{}, nil
+			return ctrl.Result{RequeueAfter: requeueWithin}, nil
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueWithin}, nil
 }
 
 func operationIndexerFunc(rawObj client.Object) []string {
 	// grab the AppDeployment object, extract the owner
-	adp := rawObj.(*v1alpha1.AppDeployment)
+	adp := rawObj.(*v1beta1.AppDeployment)
 	owner := metav1.GetControllerOf(adp)
 	if owner == nil {
 		return nil
 	}
 	// Make sure the owner is a Operation object
-	if owner.APIVersion != v1alpha1.GroupVersion.String() || owner.Kind != "Operation" {
+	if !ctrlutils.IsOwnerOfKind(owner, "Operation") {
 		return nil
 	}
 	return []string{owner.Name}
@@ -103,15 +112,15 @@ func operationIndexerFunc(rawObj client.Object) []string {
 // SetupWithManager sets up the controller with the Manager.
 func (r *OperationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
-		&v1alpha1.AppDeployment{}, v1alpha1.OperationOwnerKey, operationIndexerFunc); err != nil {
+		&v1beta1.AppDeployment{}, v1beta1.OperationOwnerKey, operationIndexerFunc); err != nil {
 		return err
 	}
 	r.recorder = mgr.GetEventRecorderFor("Operation")
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Operation{}).
-		Owns(&v1alpha1.AppDeployment{}).
+		For(&v1beta1.Operation{}).
+		Owns(&v1beta1.AppDeployment{}).
 		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 100,
+			MaxConcurrentReconciles: config.Current().MaxConcurrentReconciles.Operation,
 		}).
 		Named("operation").
 		Complete(r)
@@ -33,7 +33,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 	"github.com/Azure/operation-cache-controller/internal/handler"
 	"github.com/Azure/operation-cache-controller/internal/handler/mocks"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
@@ -71,13 +71,13 @@ var _ = Describe("Operation Controller", func() {
 				Name:      "test-operation",
 				Namespace: "default",
 			}
-			operation := &v1alpha1.Operation{
+			operation := &v1beta1.Operation{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      key.Name,
 					Namespace: key.Namespace,
 				},
-				Spec: v1alpha1.OperationSpec{
-					Applications: []v1alpha1.ApplicationSpec{
+				Spec: v1beta1.OperationSpec{
+					Applications: []v1beta1.ApplicationSpec{
 						{
 							Name:      "test-app1",
 							Provision: newTestJobSpec(),
@@ -95,7 +95,7 @@ var _ = Describe("Operation Controller", func() {
 
 			Expect(k8sClient.Create(context.Background(), operation)).To(Succeed())
 
-			feched := &v1alpha1.Operation{}
+			feched := &v1beta1.Operation{}
 			Eventually(func() bool {
 				err := k8sClient.Get(context.Background(), key, feched)
 				return err == nil
@@ -188,19 +188,19 @@ var _ = Describe("Operation Controller", func() {
 			Name:      resourceName,
 			Namespace: "default", // TODO(user):Modify as needed
 		}
-		operation := &v1alpha1.Operation{}
+		operation := &v1beta1.Operation{}
 
 		BeforeEach(func() {
 			By("creating the custom resource for the Kind Operation")
 			err := k8sClient.Get(ctx, typeNamespacedName, operation)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &v1alpha1.Operation{
+				resource := &v1beta1.Operation{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					Spec: v1alpha1.OperationSpec{
-						Applications: []v1alpha1.ApplicationSpec{
+					Spec: v1beta1.OperationSpec{
+						Applications: []v1beta1.ApplicationSpec{
 							{
 								Name:      "test-app1",
 								Provision: newTestJobSpec(),
@@ -221,7 +221,7 @@ var _ = Describe("Operation Controller", func() {
 
 		AfterEach(func() {
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &v1alpha1.Operation{}
+			resource := &v1beta1.Operation{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -248,7 +248,7 @@ var _ = Describe("Operation Controller", func() {
 func TestOperationIndexerFunc(t *testing.T) {
 	// Test case 1: AppDeployment without an owner reference
 	t.Run("AppDeployment without an owner reference", func(t *testing.T) {
-		appDeployment := &v1alpha1.AppDeployment{
+		appDeployment := &v1beta1.AppDeployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "app-without-owner",
 				Namespace: "default",
@@ -260,13 +260,13 @@ func TestOperationIndexerFunc(t *testing.T) {
 
 	// Test case 2: AppDeployment with a non-controller owner reference
 	t.Run("AppDeployment with a non-controller owner reference", func(t *testing.T) {
-		appDeployment := &v1alpha1.AppDeployment{
+		appDeployment := &v1beta1.AppDeployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "app-with-non-controller-owner",
 				Namespace: "default",
 				OwnerReferences: []metav1.OwnerReference{
 					{
-						APIVersion: v1alpha1.GroupVersion.String(),
+						APIVersion: v1beta1.GroupVersion.String(),
 						Kind:       "Operation",
 						Name:       "test-operation",
 						UID:        "12345",
@@ -281,13 +281,13 @@ func TestOperationIndexerFunc(t *testing.T) {
 
 	// Test case 3: AppDeployment with an owner that's not an Operation
 	t.Run("AppDeployment with an owner that's not an Operation", func(t *testing.T) {
-		appDeployment := &v1alpha1.AppDeployment{
+		appDeployment := &v1beta1.AppDeployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "app-with-wrong-owner-kind",
 				Namespace: "default",
 				OwnerReferences: []metav1.OwnerReference{
 					{
-						APIVersion: v1alpha1.GroupVersion.String(),
+						APIVersion: v1beta1.GroupVersion.String(),
 						Kind:       "SomeOtherKind",
 						Name:       "test-owner",
 						UID:        "12345",
@@ -303,13 +303,13 @@ func TestOperationIndexerFunc(t *testing.T) {
 	// Test case 4: AppDeployment with a valid Operation controller reference
 	t.Run("AppDeployment with a valid Operation controller reference", func(t *testing.T) {
 		operationName := "test-operation"
-		appDeployment := &v1alpha1.AppDeployment{
+		appDeployment := &v1beta1.AppDeployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "app-with-operation-owner",
 				Namespace: "default",
 				OwnerReferences: []metav1.OwnerReference{
 					{
-						APIVersion: v1alpha1.GroupVersion.String(),
+						APIVersion: v1beta1.GroupVersion.String(),
 						Kind:       "Operation",
 						Name:       operationName,
 						UID:        "12345",
@@ -324,7 +324,7 @@ func TestOperationIndexerFunc(t *testing.T) {
 
 	// Test case 5: AppDeployment with wrong API version owner reference
 	t.Run("AppDeployment with wrong API version owner reference", func(t *testing.T) {
-		appDeployment := &v1alpha1.AppDeployment{
+		appDeployment := &v1beta1.AppDeployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "app-with-wrong-api-version",
 				Namespace: "default",
@@ -18,18 +18,24 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	"github.com/Azure/operation-cache-controller/internal/handler"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
@@ -45,6 +51,7 @@ type RequirementReconciler struct {
 // +kubebuilder:rbac:groups=controller.azure.github.com,resources=requirements,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=controller.azure.github.com,resources=requirements/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=controller.azure.github.com,resources=requirements/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -58,7 +65,7 @@ type RequirementReconciler struct {
 func (r *RequirementReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithValues("requirement", req.NamespacedName)
 
-	requirement := &v1alpha1.Requirement{}
+	requirement := &v1beta1.Requirement{}
 	if err := r.Get(ctx, req.NamespacedName, requirement); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -67,6 +74,7 @@ func (r *RequirementReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 }
 func (r *RequirementReconciler) ReconcileHandler(ctx context.Context, h handler.RequirementHandlerInterface) (ctrl.Result, error) {
 	operations := []reconciler.ReconcileOperation{
+		h.EnsureFinalizerDeleted,
 		h.EnsureNotExpired,
 		h.EnsureInitialized,
 		h.EnsureCacheExisted,
@@ -74,48 +82,66 @@ func (r *RequirementReconciler) ReconcileHandler(ctx context.Context, h handler.
 		h.EnsureOperationReady,
 	}
 
+	// the requirement is reconciled again by the time it expires, even if nothing else changes
+	var requeueWithin time.Duration
 	for _, operation := range operations {
 		result, err := operation(ctx)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+		requeueWithin = reconciler.Sooner(requeueWithin, result.RequeueWithin)
 		if result.RequeueRequest {
-			return ctrl.Result{RequeueAfter: reconciler.DefaultRequeueDelay}, err
+			return ctrl.Result{RequeueAfter: reconciler.Sooner(reconciler.RequeueDelay(), requeueWithin)}, err
 		}
 		if result.CancelRequest {
-			return ctrl.Result{}, nil
+			return ctrl.Result{RequeueAfter: requeueWithin}, nil
 		}
 	}
 
-	return ctrl.Result{RequeueAfter: defaultCheckInterval}, nil
+	return ctrl.Result{RequeueAfter: reconciler.Sooner(defaultCheckInterval, requeueWithin)}, nil
 }
 
 func requirementIndexerFunc(rawObj client.Object) []string {
 	// grab the Operation object, extract the owner
-	op := rawObj.(*v1alpha1.Operation)
+	op := rawObj.(*v1beta1.Operation)
 	owner := metav1.GetControllerOf(op)
 	if owner == nil {
 		return nil
 	}
 	// Make sure the owner is a Requirement object
-	if owner.APIVersion != v1alpha1.GroupVersion.String() || owner.Kind != "Requirement" {
+	if !ctrlutils.IsOwnerOfKind(owner, "Requirement") {
 		return nil
 	}
 	return []string{owner.Name}
 }
 
+// acquiringRequirement maps an operation acquired from the cache pool to the requirement of another namespace which
+// acquired it, which does not own it
+func acquiringRequirement(_ context.Context, obj client.Object) []reconcile.Request {
+	acquiredBy, ok := obj.GetAnnotations()[v1beta1.OperationAcquiredByAnnotationKey]
+	if !ok {
+		return nil
+	}
+	namespace, name, found := strings.Cut(acquiredBy, "/")
+	if !found {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *RequirementReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
-		&v1alpha1.Operation{}, v1alpha1.RequirementOwnerKey, requirementIndexerFunc); err != nil {
+		&v1beta1.Operation{}, v1beta1.RequirementOwnerKey, requirementIndexerFunc); err != nil {
 		return err
 	}
 	r.recorder = mgr.GetEventRecorderFor("Requirement")
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Requirement{}).
-		Owns(&v1alpha1.Operation{}).
+		For(&v1beta1.Requirement{}).
+		Owns(&v1beta1.Operation{}).
+		Watches(&v1beta1.Operation{}, crhandler.EnqueueRequestsFromMapFunc(acquiringRequirement)).
 		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 100,
+			MaxConcurrentReconciles: config.Current().MaxConcurrentReconciles.Requirement,
 		}).
 		Named("requirement").
 		Complete(r)
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -29,14 +30,17 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 	"github.com/Azure/operation-cache-controller/internal/handler"
 	"github.com/Azure/operation-cache-controller/internal/handler/mocks"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
@@ -72,14 +76,14 @@ var _ = Describe("Requirement Controller", func() {
 				Name:      "test-requirement",
 				Namespace: "default",
 			}
-			requirement := &v1alpha1.Requirement{
+			requirement := &v1beta1.Requirement{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      key.Name,
 					Namespace: key.Namespace,
 				},
-				Spec: v1alpha1.RequirementSpec{
-					Template: v1alpha1.OperationSpec{
-						Applications: []v1alpha1.ApplicationSpec{
+				Spec: v1beta1.RequirementSpec{
+					Template: v1beta1.OperationSpec{
+						Applications: []v1beta1.ApplicationSpec{
 							{
 								Name: "test-app",
 								Provision: batchv1.JobSpec{
@@ -111,7 +115,7 @@ var _ = Describe("Requirement Controller", func() {
 			}
 			Expect(k8sClient.Create(context.Background(), requirement)).Should(Succeed())
 
-			fetchedRequirement := &v1alpha1.Requirement{}
+			fetchedRequirement := &v1beta1.Requirement{}
 			Eventually(func() bool {
 				err := k8sClient.Get(context.Background(), key, fetchedRequirement)
 				return err == nil
@@ -152,6 +156,7 @@ var _ = Describe("Requirement Controller", func() {
 		})
 
 		It("Should reconcile the resource with adapter", func() {
+			mockAdapter.EXPECT().EnsureFinalizerDeleted(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
 			mockAdapter.EXPECT().EnsureNotExpired(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
 			mockAdapter.EXPECT().EnsureInitialized(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
 			mockAdapter.EXPECT().EnsureCacheExisted(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
@@ -168,6 +173,7 @@ var _ = Describe("Requirement Controller", func() {
 			By("Reconciling the resource with adapter")
 			ctx := context.Background()
 			testErr := fmt.Errorf("test-error")
+			mockAdapter.EXPECT().EnsureFinalizerDeleted(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
 			mockAdapter.EXPECT().EnsureNotExpired(gomock.Any()).Return(reconciler.RequeueWithError(testErr))
 
 			result, err := requirementReconciler.Reconcile(context.WithValue(ctx, handler.RequiremenContextKey{}, mockAdapter), ctrl.Request{
@@ -181,6 +187,7 @@ var _ = Describe("Requirement Controller", func() {
 			By("Reconciling the created resource")
 			ctx := context.Background()
 
+			mockAdapter.EXPECT().EnsureFinalizerDeleted(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
 			mockAdapter.EXPECT().EnsureNotExpired(gomock.Any()).Return(reconciler.OperationResult{
 				CancelRequest: true,
 			}, nil)
@@ -191,6 +198,22 @@ var _ = Describe("Requirement Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result).Should(Equal(reconcile.Result{}))
 		})
+
+		It("should reconcile a ready requirement again when it expires", func() {
+			ctx := context.Background()
+
+			mockAdapter.EXPECT().EnsureFinalizerDeleted(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
+			mockAdapter.EXPECT().EnsureNotExpired(gomock.Any()).Return(reconciler.ContinueProcessingWithin(time.Minute))
+			mockAdapter.EXPECT().EnsureInitialized(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
+			mockAdapter.EXPECT().EnsureCacheExisted(gomock.Any()).Return(reconciler.ContinueOperationResult(), nil)
+			mockAdapter.EXPECT().EnsureCachedOperationAcquired(gomock.Any()).Return(reconciler.StopOperationResult(), nil)
+
+			result, err := requirementReconciler.Reconcile(context.WithValue(ctx, handler.RequiremenContextKey{}, mockAdapter), ctrl.Request{
+				NamespacedName: key,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).Should(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+		})
 	})
 	Context("When reconciling a resource", func() {
 		const resourceName = "test-req-resource"
@@ -201,20 +224,20 @@ var _ = Describe("Requirement Controller", func() {
 			Name:      resourceName,
 			Namespace: "default",
 		}
-		requirement := &v1alpha1.Requirement{}
+		requirement := &v1beta1.Requirement{}
 
 		BeforeEach(func() {
 			By("Creating a new Requirement resource")
 			err := k8sClient.Get(ctx, typeNamespacedName, requirement)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &v1alpha1.Requirement{
+				resource := &v1beta1.Requirement{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      typeNamespacedName.Name,
 						Namespace: typeNamespacedName.Namespace,
 					},
-					Spec: v1alpha1.RequirementSpec{
-						Template: v1alpha1.OperationSpec{
-							Applications: []v1alpha1.ApplicationSpec{
+					Spec: v1beta1.RequirementSpec{
+						Template: v1beta1.OperationSpec{
+							Applications: []v1beta1.ApplicationSpec{
 								{
 									Name: "test-app",
 									Provision: batchv1.JobSpec{
@@ -250,7 +273,7 @@ var _ = Describe("Requirement Controller", func() {
 
 		AfterEach(func() {
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &v1alpha1.Requirement{}
+			resource := &v1beta1.Requirement{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -271,15 +294,122 @@ var _ = Describe("Requirement Controller", func() {
 		})
 	})
 
+	Context("When requirements race for the same cached operation", func() {
+		const (
+			namespace = "default"
+			count     = 5
+		)
+
+		newApplications := func() []v1beta1.ApplicationSpec {
+			jobSpec := batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "test", Image: "test-image"}},
+					},
+				},
+			}
+			return []v1beta1.ApplicationSpec{{Name: "race-app", Provision: jobSpec, Teardown: jobSpec}}
+		}
+
+		It("Should hand every ready operation to exactly one requirement", func() {
+			ctx := context.Background()
+			cacheKey := ctrlutils.NewCacheHelper().NewCacheKeyFromApplications(newApplications())
+
+			By("Creating a cache with ready operations")
+			cache := &v1beta1.Cache{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache-" + cacheKey, Namespace: namespace},
+				Spec: v1beta1.CacheSpec{
+					OperationTemplate: v1beta1.OperationSpec{Applications: newApplications()},
+				},
+			}
+			Expect(k8sClient.Create(ctx, cache)).To(Succeed())
+			operationNames := []string{}
+			for i := range count {
+				operation := &v1beta1.Operation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            fmt.Sprintf("race-operation-%d", i),
+						Namespace:       namespace,
+						OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cache, v1beta1.GroupVersion.WithKind("Cache"))},
+					},
+					Spec: v1beta1.OperationSpec{Applications: newApplications()},
+				}
+				Expect(k8sClient.Create(ctx, operation)).To(Succeed())
+				operation.Status = v1beta1.OperationStatus{
+					Phase:       v1beta1.OperationPhaseReconciled,
+					CacheKey:    cacheKey,
+					OperationID: operation.Name,
+					Conditions:  []metav1.Condition{},
+				}
+				Expect(k8sClient.Status().Update(ctx, operation)).To(Succeed())
+				operationNames = append(operationNames, operation.Name)
+			}
+			cache.Status.CacheKey = cacheKey
+			cache.Status.KeepAliveCount = count
+			cache.Status.AvailableCaches = operationNames
+			Expect(k8sClient.Status().Update(ctx, cache)).To(Succeed())
+
+			By("Creating requirements which all selected the same operation")
+			requirements := []*v1beta1.Requirement{}
+			for i := range count {
+				requirement := &v1beta1.Requirement{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("race-requirement-%d", i), Namespace: namespace},
+					Spec: v1beta1.RequirementSpec{
+						Template: v1beta1.OperationSpec{Applications: newApplications()},
+					},
+				}
+				Expect(k8sClient.Create(ctx, requirement)).To(Succeed())
+				requirement.Status = v1beta1.RequirementStatus{
+					Phase:         v1beta1.RequirementPhaseCacheChecking,
+					CacheKey:      cacheKey,
+					OperationName: operationNames[0],
+					OperationID:   fmt.Sprintf("race-%d", i),
+					Conditions:    []metav1.Condition{},
+				}
+				Expect(k8sClient.Status().Update(ctx, requirement)).To(Succeed())
+				requirements = append(requirements, requirement)
+			}
+
+			By("Acquiring concurrently")
+			var wg sync.WaitGroup
+			for _, requirement := range requirements {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					h := handler.NewRequirementHandler(ctx, requirement, GinkgoLogr, k8sClient, record.NewFakeRecorder(10))
+					_, err := h.EnsureCachedOperationAcquired(ctx)
+					Expect(err).NotTo(HaveOccurred())
+				}()
+			}
+			wg.Wait()
+
+			By("Checking that every requirement got its own operation")
+			acquired := map[string]string{}
+			for _, requirement := range requirements {
+				fetched := &v1beta1.Requirement{}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(requirement), fetched)).To(Succeed())
+				Expect(fetched.Status.Phase).To(Equal(v1beta1.RequirementPhaseReady))
+				Expect(acquired).NotTo(HaveKey(fetched.Status.OperationName))
+				acquired[fetched.Status.OperationName] = fetched.Name
+
+				operation := &v1beta1.Operation{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: fetched.Status.OperationName, Namespace: namespace}, operation)).To(Succeed())
+				Expect(operation.Annotations).To(HaveKey(v1beta1.OperationAcquiredAnnotationKey))
+				Expect(metav1.GetControllerOf(operation).UID).To(Equal(fetched.UID))
+			}
+			Expect(acquired).To(HaveLen(count))
+		})
+	})
+
 	Context("Testing requirementIndexerFunc", func() {
 		It("Should return the owner name for an Operation owned by a Requirement", func() {
 			ownerName := "test-requirement-owner"
-			operation := &v1alpha1.Operation{
+			operation := &v1beta1.Operation{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "test-operation",
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: v1alpha1.GroupVersion.String(),
+							APIVersion: v1beta1.GroupVersion.String(),
 							Kind:       "Requirement",
 							Name:       ownerName,
 							Controller: &[]bool{true}[0],
@@ -294,7 +424,7 @@ var _ = Describe("Requirement Controller", func() {
 		})
 
 		It("Should return nil if the Operation has no owner", func() {
-			operation := &v1alpha1.Operation{
+			operation := &v1beta1.Operation{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "test-operation-no-owner",
 				},
@@ -305,7 +435,7 @@ var _ = Describe("Requirement Controller", func() {
 		})
 
 		It("Should return nil if the Operation is owned by a non-Requirement resource", func() {
-			operation := &v1alpha1.Operation{
+			operation := &v1beta1.Operation{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "test-operation-wrong-owner",
 					OwnerReferences: []metav1.OwnerReference{
@@ -324,12 +454,12 @@ var _ = Describe("Requirement Controller", func() {
 		})
 
 		It("Should return nil if the owner is a Requirement but not the controller", func() {
-			operation := &v1alpha1.Operation{
+			operation := &v1beta1.Operation{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "test-operation-not-controlled",
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: v1alpha1.GroupVersion.String(),
+							APIVersion: v1beta1.GroupVersion.String(),
 							Kind:       "Requirement",
 							Name:       "non-controller-requirement",
 							Controller: &[]bool{false}[0],
@@ -342,4 +472,30 @@ var _ = Describe("Requirement Controller", func() {
 			Expect(indexKeys).To(BeNil())
 		})
 	})
+
+	Context("Testing acquiringRequirement", func() {
+		It("Should map an operation acquired from the cache pool to its requirement", func() {
+			operation := &v1beta1.Operation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-pooled-operation",
+					Namespace:   "pool",
+					Annotations: map[string]string{v1beta1.OperationAcquiredByAnnotationKey: "team-a/test-requirement"},
+				},
+			}
+
+			requests := acquiringRequirement(context.Background(), operation)
+			Expect(requests).To(Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "test-requirement"}}}))
+		})
+
+		It("Should return nil for operations not acquired from the cache pool", func() {
+			operation := &v1beta1.Operation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-operation",
+					Annotations: map[string]string{v1beta1.OperationAcquiredAnnotationKey: "2025-01-01T00:00:00Z"},
+				},
+			}
+
+			Expect(acquiringRequirement(context.Background(), operation)).To(BeNil())
+		})
+	})
 })
@@ -5,19 +5,26 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/samber/lo"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 	"github.com/Azure/operation-cache-controller/internal/log"
+	"github.com/Azure/operation-cache-controller/internal/metrics"
 	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
@@ -38,7 +45,7 @@ type AppDeploymentHandlerInterface interface {
 }
 
 type AppDeploymentHandler struct {
-	appDeployment *v1alpha1.AppDeployment
+	appDeployment *v1beta1.AppDeployment
 	logger        logr.Logger
 	client        client.Client
 	recorder      record.EventRecorder
@@ -46,7 +53,7 @@ type AppDeploymentHandler struct {
 	apdutil ctrlutils.AppDeploymentHelper
 }
 
-func NewAppDeploymentHandler(ctx context.Context, appDeployment *v1alpha1.AppDeployment, logger logr.Logger, client client.Client, recorder record.EventRecorder) AppDeploymentHandlerInterface {
+func NewAppDeploymentHandler(ctx context.Context, appDeployment *v1beta1.AppDeployment, logger logr.Logger, client client.Client, recorder record.EventRecorder) AppDeploymentHandlerInterface {
 	if appdeploymentHandler, ok := ctx.Value(AppdeploymentHandlerContextKey{}).(AppDeploymentHandlerInterface); ok {
 		return appdeploymentHandler
 	}
@@ -76,9 +83,9 @@ func (a *AppDeploymentHandler) EnsureApplicationValid(ctx context.Context) (reco
 		return reconciler.RequeueWithError(err)
 	}
 	// initialize the appdeployment status
-	if a.phaseIs(v1alpha1.AppDeploymentPhaseEmpty) {
+	if a.phaseIs(v1beta1.AppDeploymentPhaseEmpty) {
 		a.logger.V(1).Info("Initializing appdeployment status")
-		a.appDeployment.Status.Phase = v1alpha1.AppDeploymentPhasePending
+		a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhasePending
 		a.apdutil.ClearConditions(ctx, a.appDeployment)
 		return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
 	}
@@ -88,23 +95,23 @@ func (a *AppDeploymentHandler) EnsureApplicationValid(ctx context.Context) (reco
 
 func (a *AppDeploymentHandler) EnsureFinalizer(ctx context.Context) (reconciler.OperationResult, error) {
 	a.logger.V(1).Info("Operation EnsureFinalizer")
-	if a.appDeployment.ObjectMeta.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(a.appDeployment, v1alpha1.AppDeploymentFinalizerName) {
-		controllerutil.AddFinalizer(a.appDeployment, v1alpha1.AppDeploymentFinalizerName)
+	if a.appDeployment.ObjectMeta.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(a.appDeployment, v1beta1.AppDeploymentFinalizerName) {
+		controllerutil.AddFinalizer(a.appDeployment, v1beta1.AppDeploymentFinalizerName)
 	}
 	return reconciler.RequeueOnErrorOrContinue(a.client.Update(ctx, a.appDeployment))
 }
 
 func (a *AppDeploymentHandler) EnsureFinalizerDeleted(ctx context.Context) (reconciler.OperationResult, error) {
 	a.logger.V(1).Info("Operation EnsureFinalizerDeleted")
-	if !a.appDeployment.ObjectMeta.DeletionTimestamp.IsZero() && controllerutil.ContainsFinalizer(a.appDeployment, v1alpha1.AppDeploymentFinalizerName) {
-		if a.phaseIs(v1alpha1.AppDeploymentPhaseDeleted) {
+	if !a.appDeployment.ObjectMeta.DeletionTimestamp.IsZero() && controllerutil.ContainsFinalizer(a.appDeployment, v1beta1.AppDeploymentFinalizerName) {
+		if a.phaseIs(v1beta1.AppDeploymentPhaseDeleted) {
 			a.logger.V(1).Info("All app deleted removing finalizer")
-			controllerutil.RemoveFinalizer(a.appDeployment, v1alpha1.AppDeploymentFinalizerName)
+			controllerutil.RemoveFinalizer(a.appDeployment, v1beta1.AppDeploymentFinalizerName)
 			return reconciler.RequeueOnErrorOrContinue(a.client.Update(ctx, a.appDeployment))
 		}
-		if !a.phaseIs(v1alpha1.AppDeploymentPhaseDeleting) {
+		if !a.phaseIs(v1beta1.AppDeploymentPhaseDeleting) {
 			a.logger.V(1).Info("App is not deleted yet, setting phase to deleting")
-			a.appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleting
+			a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
 			return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
 		}
 	}
@@ -112,25 +119,27 @@ func (a *AppDeploymentHandler) EnsureFinalizerDeleted(ctx context.Context) (reco
 }
 
 func (a *AppDeploymentHandler) EnsureDependenciesReady(ctx context.Context) (reconciler.OperationResult, error) {
-	if !a.phaseIs(v1alpha1.AppDeploymentPhasePending) {
+	if !a.phaseIs(v1beta1.AppDeploymentPhasePending) {
 		return reconciler.ContinueProcessing()
 	}
 	a.logger.V(1).Info("Operation EnsureDependenciesReady")
 	// list all dependencies and check if they are ready
 	for _, dep := range a.appDeployment.Spec.Dependencies {
 		// check if dependency is ready
-		appdeployment := &v1alpha1.AppDeployment{}
-		realAppName := ctrlutils.OperationScopedAppDeployment(dep, a.appDeployment.Spec.OpId)
+		appdeployment := &v1beta1.AppDeployment{}
+		realAppName := ctrlutils.OperationScopedAppDeployment(dep, a.appDeployment.Spec.OperationID)
 		if err := a.client.Get(ctx, client.ObjectKey{Namespace: a.appDeployment.Namespace, Name: realAppName}, appdeployment); err != nil {
 			a.logger.V(1).Error(err, "dependency not found", "dependency", realAppName)
 			return reconciler.RequeueWithError(fmt.Errorf("dependency not found: %s ", realAppName))
 		}
-		if appdeployment.Status.Phase != v1alpha1.AppDeploymentPhaseReady {
-			return reconciler.RequeueWithError(fmt.Errorf("dependency is not ready: %s", realAppName))
+		if appdeployment.Status.Phase != v1beta1.AppDeploymentPhaseReady {
+			// the controller watches the dependencies and reconciles this app deployment once they are ready
+			a.logger.V(1).Info("waiting for dependency to be ready", "dependency", realAppName)
+			return reconciler.StopProcessing()
 		}
 	}
 	// all dependencies are ready
-	a.appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+	a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
 	return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
 }
 
@@ -138,6 +147,15 @@ var (
 	errJobNotCompleted = fmt.Errorf("job not completed")
 )
 
+// jobFailedError is returned for a failed job, a retry is up to the caller
+type jobFailedError struct {
+	failure ctrlutils.JobFailure
+}
+
+func (e *jobFailedError) Error() string { return e.failure.Message }
+
+func (e *jobFailedError) Unwrap() error { return ErrJobFailed }
+
 func (a *AppDeploymentHandler) createJob(ctx context.Context, jobTemplate *batchv1.Job) error {
 	if err := ctrl.SetControllerReference(a.appDeployment, jobTemplate, a.client.Scheme()); err != nil {
 		return fmt.Errorf("failed to set controller reference for job %s: %w", jobTemplate.Name, err)
@@ -149,12 +167,22 @@ func (a *AppDeploymentHandler) createJob(ctx context.Context, jobTemplate *batch
 }
 
 func (a *AppDeploymentHandler) initializeJobAndAwaitCompletion(ctx context.Context, jobTemplate *batchv1.Job) error {
+	jobType := ctrlutils.JobTypeProvision
+	if strings.HasPrefix(jobTemplate.Name, ctrlutils.JobTypeTeardown) {
+		jobType = ctrlutils.JobTypeTeardown
+	}
 	job := &batchv1.Job{}
 	// check if the job exists
 	if err := a.client.Get(ctx, client.ObjectKey{Namespace: a.appDeployment.Namespace, Name: jobTemplate.Name}, job); err != nil {
 		if !apierror.IsNotFound(err) {
 			return fmt.Errorf("failed to get job %s: %w", jobTemplate.Name, err)
 		}
+		if jobType == ctrlutils.JobTypeProvision {
+			if err := a.prepareOutputsSecret(ctx); err != nil {
+				a.recorder.Event(a.appDeployment, "Error", "FailedPrepareJobOutputs", err.Error())
+				return err
+			}
+		}
 		// create a new job
 		if err := a.createJob(ctx, jobTemplate); err != nil {
 			a.recorder.Event(a.appDeployment, "Error", "FailedCreateJob", err.Error())
@@ -162,34 +190,44 @@ func (a *AppDeploymentHandler) initializeJobAndAwaitCompletion(ctx context.Conte
 		}
 		return errJobNotCompleted // requeue
 	}
+	if !job.DeletionTimestamp.IsZero() {
+		// a deleted job has been accounted for already, wait until it is gone
+		return errJobNotCompleted
+	}
 
 	// check if the job is running
 	switch ctrlutils.CheckJobStatus(ctx, job) {
-	// if job is failed then delete the job and create a new one
+	// if job is failed then keep a provision job for debugging and delete a teardown job, retries are up to the caller
 	case ctrlutils.JobStatusFailed:
+		metrics.ObserveJobFinished(jobType, false, jobDuration(job))
+		// the failure details are gone with the pods of the job, read them before deleting it
+		pods, err := a.listJobPods(ctx, job)
+		if err != nil {
+			return err
+		}
+		failure := ctrlutils.JobFailureFromPods(job, pods)
+		if jobType == ctrlutils.JobTypeProvision {
+			if err := a.keepFailedJob(ctx, job); err != nil {
+				return err
+			}
+			return &jobFailedError{failure: failure}
+		}
 		// delete the failed job
 		if err := a.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
 			a.recorder.Event(a.appDeployment, "Error", "FailedDeleteJob", err.Error())
 			return fmt.Errorf("failed to delete job %s: %w", job.Name, err)
 		}
-		// complete the job if it is a teardown job
-		if strings.HasPrefix(jobTemplate.Name, ctrlutils.JobTypeTeardown) {
-			a.logger.Error(ErrJobFailed, "teardown job failed", log.FieldKeyAppDeploymentJobName, jobTemplate.Name)
-			a.recorder.Event(a.appDeployment, "Warning", "TeardownJobFailed", fmt.Sprintf("Teardown job %s failed, requeuing for retry", jobTemplate.Name))
-			// return nil to make the teardown job complete
-			return nil
-		}
-
-		// create a new job if it is not a teardown job
-		if err := ctrl.SetControllerReference(a.appDeployment, jobTemplate, a.client.Scheme()); err != nil {
-			return fmt.Errorf("failed to set controller reference for job %s: %w", job.Name, err)
-		}
-		if err := a.client.Create(ctx, jobTemplate); err != nil {
-			return fmt.Errorf("failed to create job %s: %w", jobTemplate.Name, err)
-		}
+		return &jobFailedError{failure: failure}
 
 	// if job is succeeded then delete the job
 	case ctrlutils.JobStatusSucceeded:
+		metrics.ObserveJobFinished(jobType, true, jobDuration(job))
+		// the outputs are gone with the pods of the job, collect them before deleting it
+		if jobType == ctrlutils.JobTypeProvision {
+			if err := a.collectJobOutputs(ctx, job); err != nil {
+				return err
+			}
+		}
 		// delete the succeeded job
 		if err := a.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
 			return fmt.Errorf("failed to delete succeeded job %s: %w", job.Name, err)
@@ -199,49 +237,339 @@ func (a *AppDeploymentHandler) initializeJobAndAwaitCompletion(ctx context.Conte
 	return errJobNotCompleted
 }
 
+// prepareOutputsSecret creates the empty secret a provision job writes its secret outputs to, and grants the service
+// account of the job write access to it. Secrets of a previous run are cleared, a consumer must not read secrets the
+// current run did not produce.
+func (a *AppDeploymentHandler) prepareOutputsSecret(ctx context.Context) error {
+	objectMeta := metav1.ObjectMeta{
+		Name:      ctrlutils.OutputsSecretName(a.appDeployment),
+		Namespace: a.appDeployment.Namespace,
+	}
+	secret := &corev1.Secret{ObjectMeta: objectMeta}
+	if _, err := controllerutil.CreateOrUpdate(ctx, a.client, secret, func() error {
+		secret.Data = nil
+		return ctrl.SetControllerReference(a.appDeployment, secret, a.client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to prepare secret outputs %s: %w", secret.Name, err)
+	}
+	role := &rbacv1.Role{ObjectMeta: objectMeta}
+	if _, err := controllerutil.CreateOrUpdate(ctx, a.client, role, func() error {
+		role.Rules = ctrlutils.OutputsRoleRules(a.appDeployment)
+		return ctrl.SetControllerReference(a.appDeployment, role, a.client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to prepare role %s: %w", role.Name, err)
+	}
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: objectMeta}
+	if _, err := controllerutil.CreateOrUpdate(ctx, a.client, roleBinding, func() error {
+		roleBinding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name}
+		roleBinding.Subjects = ctrlutils.OutputsRoleSubjects(a.appDeployment)
+		return ctrl.SetControllerReference(a.appDeployment, roleBinding, a.client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to prepare role binding %s: %w", roleBinding.Name, err)
+	}
+	return nil
+}
+
+// collectJobOutputs reads the outputs the provision job wrote to the termination message of its first container into
+// the status, and references the secret outputs it wrote to the outputs secret. The write access of the job is revoked,
+// later pods of its service account must not change the secret outputs.
+func (a *AppDeploymentHandler) collectJobOutputs(ctx context.Context, job *batchv1.Job) error {
+	pods, err := a.listJobPods(ctx, job)
+	if err != nil {
+		return err
+	}
+	outputs, err := ctrlutils.JobOutputsFromPods(pods, ctrlutils.OutputsContainerName(job))
+	if err != nil {
+		// the message of a finished job never changes, retrying would block the app deployment forever
+		a.logger.Error(err, "ignoring outputs of provision job", log.FieldKeyAppDeploymentJobName, job.Name)
+		a.recorder.Event(a.appDeployment, "Warning", "InvalidJobOutputs", err.Error())
+		outputs = &ctrlutils.JobOutputs{}
+	}
+	a.appDeployment.Status.Outputs = outputs.Outputs
+
+	objectMeta := metav1.ObjectMeta{
+		Name:      ctrlutils.OutputsSecretName(a.appDeployment),
+		Namespace: a.appDeployment.Namespace,
+	}
+	for _, grant := range []client.Object{&rbacv1.RoleBinding{ObjectMeta: objectMeta}, &rbacv1.Role{ObjectMeta: objectMeta}} {
+		if err := a.client.Delete(ctx, grant); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to revoke access to secret outputs of job %s: %w", job.Name, err)
+		}
+	}
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: objectMeta.Namespace, Name: objectMeta.Name}, secret); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get secret outputs of job %s: %w", job.Name, err)
+	}
+	a.appDeployment.Status.OutputsSecretName = ""
+	if len(secret.Data) == 0 {
+		// the job has no secret outputs
+		return a.deleteOutputsSecret(ctx, objectMeta.Name)
+	}
+	a.appDeployment.Status.OutputsSecretName = objectMeta.Name
+	return nil
+}
+
+// deleteOutputsSecret deletes the empty secret of a job without secret outputs
+func (a *AppDeploymentHandler) deleteOutputsSecret(ctx context.Context, name string) error {
+	if len(name) == 0 {
+		return nil
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: a.appDeployment.Namespace}}
+	if err := a.client.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		a.recorder.Event(a.appDeployment, "Error", "FailedDeleteJobOutputs", err.Error())
+		return fmt.Errorf("failed to delete secret outputs %s: %w", name, err)
+	}
+	return nil
+}
+
+// keepFailedJob records the failed provision job in the status instead of deleting it, so its pods and their logs stay
+// around for debugging. The oldest failed jobs beyond the history limit are deleted, the others are deleted with the
+// TTL of finished jobs.
+func (a *AppDeploymentHandler) keepFailedJob(ctx context.Context, job *batchv1.Job) error {
+	failedJobs := append(lo.Without(a.appDeployment.Status.FailedJobs, job.Name), job.Name)
+	for len(failedJobs) > int(a.apdutil.FailedJobsHistoryLimit(a.appDeployment)) {
+		oldest := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: failedJobs[0], Namespace: a.appDeployment.Namespace}}
+		if err := a.client.Delete(ctx, oldest, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			a.recorder.Event(a.appDeployment, "Error", "FailedDeleteJob", err.Error())
+			return fmt.Errorf("failed to delete job %s: %w", oldest.Name, err)
+		}
+		failedJobs = failedJobs[1:]
+	}
+	a.appDeployment.Status.FailedJobs = failedJobs
+	return nil
+}
+
+func (a *AppDeploymentHandler) listJobPods(ctx context.Context, job *batchv1.Job) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := a.client.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list pods of job %s: %w", job.Name, err)
+	}
+	return pods.Items, nil
+}
+
+// jobDuration returns how long the finished job ran, failed jobs have no completion time so the time of the
+// failure is used instead
+func jobDuration(job *batchv1.Job) time.Duration {
+	if job.Status.StartTime == nil {
+		return 0
+	}
+	end := time.Now()
+	if job.Status.CompletionTime != nil {
+		end = job.Status.CompletionTime.Time
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			end = condition.LastTransitionTime.Time
+		}
+	}
+	return end.Sub(job.Status.StartTime.Time)
+}
+
 // EnsureDeployingFinished checks if the provision job exists
-// if not exist then create a new provision job
+// if not exist then create a new provision job, after backing off from the last failure
 // if job is exist && running then requeue and waiting for the job complete
-// if job is exist && failed then delete the job and create a new one
+// if job is exist && failed then delete the job and retry it, or fail the appdeployment once the retries are used up
 // if job is exist && succeeded then update the appdeployment status to ready
+// a failed appdeployment is only retried when it is annotated to be retried
 func (a *AppDeploymentHandler) EnsureDeployingFinished(ctx context.Context) (reconciler.OperationResult, error) {
 	a.logger.V(1).Info("Operation EnsureDeployingFinished")
-	if !a.phaseIs(v1alpha1.AppDeploymentPhaseDeploying) {
+	if a.phaseIs(v1beta1.AppDeploymentPhaseFailed) {
+		return a.retryIfRequested(ctx)
+	}
+	if !a.phaseIs(v1beta1.AppDeploymentPhaseDeploying) {
 		return reconciler.ContinueProcessing()
 	}
+	if a.appDeployment.Status.LastFailureTime != nil {
+		backoff := a.apdutil.RetryBackoff(a.appDeployment.Status.ProvisionFailures)
+		if wait := time.Until(a.appDeployment.Status.LastFailureTime.Add(backoff)); wait > 0 {
+			a.logger.V(1).Info("backing off before retrying provision job", "wait", wait)
+			return reconciler.RequeueAfter(wait, nil)
+		}
+	}
 	provisionJob := ctrlutils.ProvisionJobFromAppDeploymentSpec(a.appDeployment)
 	err := a.initializeJobAndAwaitCompletion(ctx, provisionJob)
-	switch err {
-	case nil:
+	var jobErr *jobFailedError
+	switch {
+	case err == nil:
 		// provision job is succeeded move the appdeployment to ready phase
-		a.appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseReady
+		a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseReady
+		a.resetProvisionFailures()
 		return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
-	case errJobNotCompleted:
+	case errors.Is(err, errJobNotCompleted):
 		a.logger.V(1).WithValues(log.FieldKeyAppDeploymentJobName, provisionJob.Name).Info("provision job is not completed yet")
 		return reconciler.Requeue()
+	case errors.As(err, &jobErr):
+		return a.recordProvisionFailure(ctx, jobErr.failure)
 	default:
 		a.logger.Error(err, "provision job failed %s", provisionJob.Name)
 		return reconciler.RequeueWithError(err)
 	}
 }
 
+// recordProvisionFailure counts a failed provision job and schedules its retry, or fails the appdeployment once the
+// retries are used up
+func (a *AppDeploymentHandler) recordProvisionFailure(ctx context.Context, failure ctrlutils.JobFailure) (reconciler.OperationResult, error) {
+	a.appDeployment.Status.ProvisionFailures++
+	// the failed job is kept, the next attempt gets a job of its own
+	a.appDeployment.Status.ProvisionAttempt++
+	a.appDeployment.Status.LastFailureTime = ptr.Of(metav1.Now())
+	meta.SetStatusCondition(&a.appDeployment.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.AppDeploymentConditionProvisionFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  failure.Reason,
+		Message: failure.Message,
+	})
+	failures, maxRetries := a.appDeployment.Status.ProvisionFailures, a.apdutil.MaxRetries(a.appDeployment)
+	if failures > maxRetries {
+		a.logger.Error(ErrJobFailed, "provision job failed, no retries left", "failures", failures)
+		a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseFailed
+		a.recorder.Event(a.appDeployment, "Warning", "ProvisionFailed",
+			fmt.Sprintf("Provision job failed %d times, annotate with %s to retry: %s", failures, ctrlutils.AnnotationNameRetry, failure.Reason))
+		return reconciler.RequeueOnErrorOrStop(a.client.Status().Update(ctx, a.appDeployment))
+	}
+	backoff := a.apdutil.RetryBackoff(failures)
+	a.logger.Info("provision job failed, retrying", "failures", failures, "maxRetries", maxRetries, "backoff", backoff)
+	a.recorder.Event(a.appDeployment, "Warning", "ProvisionJobFailed",
+		fmt.Sprintf("Provision job failed (%d of %d retries), retrying in %s: %s", failures, maxRetries, backoff, failure.Reason))
+	metrics.RecordJobRetry(ctrlutils.JobTypeProvision)
+	if err := a.client.Status().Update(ctx, a.appDeployment); err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	return reconciler.RequeueAfter(backoff, nil)
+}
+
+// retryIfRequested deploys a failed appdeployment again with a fresh retry budget if it is annotated to be retried
+func (a *AppDeploymentHandler) retryIfRequested(ctx context.Context) (reconciler.OperationResult, error) {
+	if _, ok := a.appDeployment.Annotations[ctrlutils.AnnotationNameRetry]; !ok {
+		return reconciler.StopProcessing()
+	}
+	// remove the annotation first, so a retry is not repeated if the status update fails
+	delete(a.appDeployment.Annotations, ctrlutils.AnnotationNameRetry)
+	if err := a.client.Update(ctx, a.appDeployment); err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	a.logger.Info("retrying failed appdeployment")
+	a.recorder.Event(a.appDeployment, "Normal", "Retrying", "Retrying failed provision job")
+	a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
+	a.resetProvisionFailures()
+	return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
+}
+
+func (a *AppDeploymentHandler) resetProvisionFailures() {
+	a.appDeployment.Status.ProvisionFailures = 0
+	a.appDeployment.Status.LastFailureTime = nil
+	meta.RemoveStatusCondition(&a.appDeployment.Status.Conditions, v1beta1.AppDeploymentConditionProvisionFailed)
+}
+
+// EnsureTeardownFinished runs the teardown job of a deleting appdeployment
+// if job is succeeded then update the appdeployment status to deleted
+// if job is failed then follow the teardown policy: retry it after a backoff, record the orphaned resources and update
+// the appdeployment status to deleted, or block until the appdeployment is annotated to be retried
 func (a *AppDeploymentHandler) EnsureTeardownFinished(ctx context.Context) (reconciler.OperationResult, error) {
 	a.logger.V(1).Info("Operation EnsureTeardownFinished")
-	if !a.phaseIs(v1alpha1.AppDeploymentPhaseDeleting) {
+	if !a.phaseIs(v1beta1.AppDeploymentPhaseDeleting) {
 		return reconciler.ContinueProcessing()
 	}
+	if a.apdutil.TeardownPolicy(a.appDeployment) == v1beta1.TeardownPolicyBlock &&
+		meta.IsStatusConditionTrue(a.appDeployment.Status.Conditions, v1beta1.AppDeploymentConditionTeardownFailed) {
+		return a.retryTeardownIfRequested(ctx)
+	}
+	if a.appDeployment.Status.TeardownFailures > 0 && a.appDeployment.Status.LastFailureTime != nil {
+		backoff := a.apdutil.RetryBackoff(a.appDeployment.Status.TeardownFailures)
+		if wait := time.Until(a.appDeployment.Status.LastFailureTime.Add(backoff)); wait > 0 {
+			a.logger.V(1).Info("backing off before retrying teardown job", "wait", wait)
+			return reconciler.RequeueAfter(wait, nil)
+		}
+	}
 	teardownJob := ctrlutils.TeardownJobFromAppDeploymentSpec(a.appDeployment)
 	err := a.initializeJobAndAwaitCompletion(ctx, teardownJob)
-	switch err {
-	case nil:
+	var jobErr *jobFailedError
+	switch {
+	case err == nil:
 		// teardown job is succeeded move the appdeployment to deleted phase
-		a.appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleted
+		a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleted
 		return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
-	case errJobNotCompleted:
+	case errors.Is(err, errJobNotCompleted):
 		a.logger.V(1).WithValues(log.FieldKeyAppDeploymentJobName, teardownJob.Name).Info("teardown job is not completed yet")
 		return reconciler.Requeue()
+	case errors.As(err, &jobErr):
+		return a.recordTeardownFailure(ctx, teardownJob.Name, jobErr.failure)
+	default:
+		a.logger.WithValues(log.FieldKeyAppDeploymentJobName, teardownJob.Name).Error(err, "teardown job failed")
+		return reconciler.RequeueWithError(err)
+	}
+}
+
+// recordTeardownFailure counts a failed teardown job and handles it according to the teardown policy
+func (a *AppDeploymentHandler) recordTeardownFailure(ctx context.Context, jobName string, failure ctrlutils.JobFailure) (reconciler.OperationResult, error) {
+	a.appDeployment.Status.TeardownFailures++
+	a.appDeployment.Status.LastFailureTime = ptr.Of(metav1.Now())
+	meta.SetStatusCondition(&a.appDeployment.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.AppDeploymentConditionTeardownFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  failure.Reason,
+		Message: failure.Message,
+	})
+	logger := a.logger.WithValues(log.FieldKeyAppDeploymentJobName, jobName, "failures", a.appDeployment.Status.TeardownFailures)
+	switch policy := a.apdutil.TeardownPolicy(a.appDeployment); policy {
+	case v1beta1.TeardownPolicyOrphan:
+		if err := a.recordOrphanedResource(ctx, jobName, failure); err != nil {
+			return reconciler.RequeueWithError(err)
+		}
+		logger.Error(ErrJobFailed, "teardown job failed, orphaning resources")
+		a.recorder.Event(a.appDeployment, "Warning", "TeardownOrphaned",
+			fmt.Sprintf("Teardown job %s failed, resources recorded in config map %s: %s", jobName, ctrlutils.OrphanedResourcesConfigMapName, failure.Reason))
+		a.appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleted
+		return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
+	case v1beta1.TeardownPolicyBlock:
+		logger.Error(ErrJobFailed, "teardown job failed, blocking deletion")
+		a.recorder.Event(a.appDeployment, "Warning", "TeardownBlocked",
+			fmt.Sprintf("Teardown job %s failed, annotate with %s to retry: %s", jobName, ctrlutils.AnnotationNameRetry, failure.Reason))
+		return reconciler.RequeueOnErrorOrStop(a.client.Status().Update(ctx, a.appDeployment))
 	default:
-		a.logger.WithValues(log.FieldKeyAppDeploymentJobName, teardownJob.Name).Error(err, "teardown job failed %s")
+		backoff := a.apdutil.RetryBackoff(a.appDeployment.Status.TeardownFailures)
+		logger.Info("teardown job failed, retrying", "backoff", backoff)
+		a.recorder.Event(a.appDeployment, "Warning", "TeardownJobFailed",
+			fmt.Sprintf("Teardown job %s failed, retrying in %s: %s", jobName, backoff, failure.Reason))
+		metrics.RecordJobRetry(ctrlutils.JobTypeTeardown)
+		if err := a.client.Status().Update(ctx, a.appDeployment); err != nil {
+			return reconciler.RequeueWithError(err)
+		}
+		return reconciler.RequeueAfter(backoff, nil)
+	}
+}
+
+// recordOrphanedResource records the resources left behind by the failed teardown job in the orphaned resources config
+// map, the config map is not owned by the appdeployment so the record outlives it
+func (a *AppDeploymentHandler) recordOrphanedResource(ctx context.Context, jobName string, failure ctrlutils.JobFailure) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctrlutils.OrphanedResourcesConfigMapName,
+			Namespace: a.appDeployment.Namespace,
+		},
+	}
+	resource := ctrlutils.NewOrphanedResource(a.appDeployment, jobName, failure, metav1.Now())
+	if _, err := controllerutil.CreateOrUpdate(ctx, a.client, configMap, func() error {
+		return ctrlutils.AddOrphanedResource(configMap, ctrlutils.OrphanedResourceKey(a.appDeployment), resource)
+	}); err != nil {
+		a.recorder.Event(a.appDeployment, "Error", "FailedRecordOrphanedResource", err.Error())
+		return fmt.Errorf("failed to record orphaned resources of job %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// retryTeardownIfRequested runs the teardown job of a blocked appdeployment again if it is annotated to be retried
+func (a *AppDeploymentHandler) retryTeardownIfRequested(ctx context.Context) (reconciler.OperationResult, error) {
+	if _, ok := a.appDeployment.Annotations[ctrlutils.AnnotationNameRetry]; !ok {
+		return reconciler.StopProcessing()
+	}
+	// remove the annotation first, so a retry is not repeated if the status update fails
+	delete(a.appDeployment.Annotations, ctrlutils.AnnotationNameRetry)
+	if err := a.client.Update(ctx, a.appDeployment); err != nil {
 		return reconciler.RequeueWithError(err)
 	}
+	a.logger.Info("retrying blocked teardown")
+	a.recorder.Event(a.appDeployment, "Normal", "Retrying", "Retrying failed teardown job")
+	meta.RemoveStatusCondition(&a.appDeployment.Status.Conditions, v1beta1.AppDeploymentConditionTeardownFailed)
+	return reconciler.RequeueOnErrorOrContinue(a.client.Status().Update(ctx, a.appDeployment))
 }
@@ -7,17 +7,22 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	mockpkg "github.com/Azure/operation-cache-controller/internal/utils/mocks"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
@@ -46,11 +51,11 @@ func newTestJobSpec() batchv1.JobSpec {
 	}
 }
 
-var validAppDeployment = &v1alpha1.AppDeployment{
-	Spec: v1alpha1.AppDeploymentSpec{
-		Provision: newTestJobSpec(),
-		Teardown:  newTestJobSpec(),
-		OpId:      testOpId,
+var validAppDeployment = &v1beta1.AppDeployment{
+	Spec: v1beta1.AppDeploymentSpec{
+		Provision:   newTestJobSpec(),
+		Teardown:    newTestJobSpec(),
+		OperationID: testOpId,
 	},
 }
 
@@ -100,7 +105,7 @@ func TestAppDeploymentAdapter_EnsureApplicationValid(t *testing.T) {
 
 	t.Run("Happy path: application invalid and not in empty phase", func(t *testing.T) {
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		assert.NotNil(t, adapter)
 		res, err := adapter.EnsureApplicationValid(ctx)
@@ -109,7 +114,7 @@ func TestAppDeploymentAdapter_EnsureApplicationValid(t *testing.T) {
 	})
 
 	t.Run("Sad path: application return error", func(t *testing.T) {
-		appDeployment := &v1alpha1.AppDeployment{}
+		appDeployment := &v1beta1.AppDeployment{}
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		assert.NotNil(t, adapter)
 		res, err := adapter.EnsureApplicationValid(ctx)
@@ -188,9 +193,9 @@ func TestAppDeploymentAdapter_EnsureFinalizerDeleted(t *testing.T) {
 		mockRecorder = mockpkg.NewMockEventRecorder(mockCtrl)
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Finalizers = []string{v1alpha1.AppDeploymentFinalizerName}
+		appDeployment.Finalizers = []string{v1beta1.AppDeploymentFinalizerName}
 		appDeployment.DeletionTimestamp = &metav1.Time{Time: time.Now()}
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleted
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleted
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		mockClient.EXPECT().Update(ctx, gomock.Any()).Return(nil)
@@ -210,9 +215,9 @@ func TestAppDeploymentAdapter_EnsureFinalizerDeleted(t *testing.T) {
 		mockRecorder = mockpkg.NewMockEventRecorder(mockCtrl)
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Finalizers = []string{v1alpha1.AppDeploymentFinalizerName}
+		appDeployment.Finalizers = []string{v1beta1.AppDeploymentFinalizerName}
 		appDeployment.DeletionTimestamp = &metav1.Time{Time: time.Now()}
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleted
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleted
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		mockClient.EXPECT().Update(ctx, gomock.Any()).Return(assert.AnError)
@@ -236,9 +241,9 @@ func TestAppDeploymentAdapter_EnsureFinalizerDeleted(t *testing.T) {
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Finalizers = []string{v1alpha1.AppDeploymentFinalizerName}
+		appDeployment.Finalizers = []string{v1beta1.AppDeploymentFinalizerName}
 		appDeployment.DeletionTimestamp = &metav1.Time{Time: time.Now()}
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhasePending
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhasePending
 
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
@@ -273,22 +278,22 @@ func TestAppDeploymentAdapter_EnsureDependenciesReady(t *testing.T) {
 		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhasePending
-		appDeployment.Spec.OpId = testOpId
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhasePending
+		appDeployment.Spec.OperationID = testOpId
 		appDeployment.Spec.Dependencies = []string{
 			"test-app-1",
 		}
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
-		dependendApp := &v1alpha1.AppDeployment{
-			Status: v1alpha1.AppDeploymentStatus{
-				Phase: v1alpha1.AppDeploymentPhaseReady,
+		dependendApp := &v1beta1.AppDeployment{
+			Status: v1beta1.AppDeploymentStatus{
+				Phase: v1beta1.AppDeploymentPhaseReady,
 			},
 		}
 
-		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.AppDeployment{}), gomock.Any()).DoAndReturn(
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AppDeployment{}), gomock.Any()).DoAndReturn(
 			func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
-				*obj.(*v1alpha1.AppDeployment) = *dependendApp
+				*obj.(*v1beta1.AppDeployment) = *dependendApp
 				assert.Equal(t, "test-op-id-test-app-1", key.Name)
 				return nil
 			}).Times(1)
@@ -303,47 +308,48 @@ func TestAppDeploymentAdapter_EnsureDependenciesReady(t *testing.T) {
 		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhasePending
-		appDeployment.Spec.OpId = testOpId
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhasePending
+		appDeployment.Spec.OperationID = testOpId
 		appDeployment.Spec.Dependencies = []string{
 			"test-app-1",
 		}
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
-		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.AppDeployment{}), gomock.Any()).Return(assert.AnError).Times(1)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AppDeployment{}), gomock.Any()).Return(assert.AnError).Times(1)
 
 		res, err := adapter.EnsureDependenciesReady(ctx)
 		assert.ErrorContains(t, err, "dependency not found: test-op-id-test-app-1")
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
 	})
 
-	t.Run("Sad path: dependency not ready", func(t *testing.T) {
+	t.Run("Happy path: wait for dependency to be ready", func(t *testing.T) {
 		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhasePending
-		appDeployment.Spec.OpId = testOpId
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhasePending
+		appDeployment.Spec.OperationID = testOpId
 		appDeployment.Spec.Dependencies = []string{
 			"test-app-1",
 		}
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
-		dependendApp := &v1alpha1.AppDeployment{
-			Status: v1alpha1.AppDeploymentStatus{
-				Phase: v1alpha1.AppDeploymentPhasePending,
+		dependendApp := &v1beta1.AppDeployment{
+			Status: v1beta1.AppDeploymentStatus{
+				Phase: v1beta1.AppDeploymentPhasePending,
 			},
 		}
 
-		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.AppDeployment{}), gomock.Any()).DoAndReturn(
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AppDeployment{}), gomock.Any()).DoAndReturn(
 			func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
-				*obj.(*v1alpha1.AppDeployment) = *dependendApp
+				*obj.(*v1beta1.AppDeployment) = *dependendApp
 				assert.Equal(t, "test-op-id-test-app-1", key.Name)
 				return nil
 			}).Times(1)
 
 		res, err := adapter.EnsureDependenciesReady(ctx)
-		assert.ErrorContains(t, err, "dependency is not ready: test-op-id-test-app-1")
-		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.StopOperationResult(), res)
+		assert.Equal(t, v1beta1.AppDeploymentPhasePending, appDeployment.Status.Phase)
 	})
 }
 
@@ -360,8 +366,8 @@ func TestAppDeploymentAdapter_EnsureDependenciesReady_MultipleDepencies(t *testi
 	mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 
 	appDeployment := validAppDeployment.DeepCopy()
-	appDeployment.Status.Phase = v1alpha1.AppDeploymentPhasePending
-	appDeployment.Spec.OpId = testOpId
+	appDeployment.Status.Phase = v1beta1.AppDeploymentPhasePending
+	appDeployment.Spec.OperationID = testOpId
 	appDeployment.Spec.Dependencies = []string{
 		"test-app-1",
 		"test-app-2",
@@ -369,15 +375,15 @@ func TestAppDeploymentAdapter_EnsureDependenciesReady_MultipleDepencies(t *testi
 
 	adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
-	readyApp := &v1alpha1.AppDeployment{
-		Status: v1alpha1.AppDeploymentStatus{
-			Phase: v1alpha1.AppDeploymentPhaseReady,
+	readyApp := &v1beta1.AppDeployment{
+		Status: v1beta1.AppDeploymentStatus{
+			Phase: v1beta1.AppDeploymentPhaseReady,
 		},
 	}
 
-	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.AppDeployment{}), gomock.Any()).
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AppDeployment{}), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.AppDeployment) = *readyApp
+			*obj.(*v1beta1.AppDeployment) = *readyApp
 			return nil
 		}).Times(2)
 
@@ -424,7 +430,7 @@ func TestAppDeploymentAdapter_CreateJob_Errors(t *testing.T) {
 		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
 
 		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
+		_ = v1beta1.AddToScheme(scheme)
 		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
@@ -453,6 +459,16 @@ func TestAppDeploymentAdapter_CreateJob_Errors(t *testing.T) {
 func TestAppDeploymentAdapter_EnsureDeployingFinished(t *testing.T) {
 	ctx := context.Background()
 	logger := log.FromContext(ctx)
+	// expectOutputsSecret expects the write access of the job to be revoked and its outputs secret to be read
+	expectOutputsSecret := func(mockClient *mockpkg.MockClient, data map[string][]byte) {
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&rbacv1.RoleBinding{})).Return(nil)
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&rbacv1.Role{})).Return(nil)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				obj.(*corev1.Secret).Data = data
+				return nil
+			})
+	}
 
 	t.Run("Happy path: skip when not in deploying phase", func(t *testing.T) {
 		appDeployment := validAppDeployment.DeepCopy()
@@ -479,7 +495,7 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished(t *testing.T) {
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
@@ -502,11 +518,155 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished(t *testing.T) {
 				}
 				return nil
 			})
-		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(nil)
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any(), gomock.Any()).Return(nil)
+		expectOutputsSecret(mockClient, nil)
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).Return(nil)
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&batchv1.Job{}), gomock.Any()).Return(nil)
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		res, err := adapter.EnsureDeployingFinished(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+		assert.Empty(t, appDeployment.Status.Outputs)
+		assert.Empty(t, appDeployment.Status.OutputsSecretName)
+	})
+
+	t.Run("Happy path: deploying finished with outputs", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+		scheme := runtime.NewScheme()
+		_ = v1beta1.AddToScheme(scheme)
+		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
+
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
+				*obj.(*batchv1.Job) = batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "default"},
+					Spec:       newTestJobSpec(),
+					Status:     batchv1.JobStatus{Succeeded: 1},
+				}
+				return nil
+			})
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				list.(*corev1.PodList).Items = []corev1.Pod{{
+					Status: corev1.PodStatus{
+						Phase: corev1.PodSucceeded,
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: "sidecar",
+								State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+									Message: `{"outputs":{"proxy":"https://proxy.example.com"}}`,
+								}},
+							},
+							{
+								Name: "test-container",
+								State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+									Message: `{"outputs":{"endpoint":"https://example.com"}}`,
+								}},
+							},
+						},
+					},
+				}}
+				return nil
+			})
+		expectOutputsSecret(mockClient, map[string][]byte{"password": []byte("p@ss")})
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&batchv1.Job{}), gomock.Any()).Return(nil)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		res, err := adapter.EnsureDeployingFinished(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+		assert.Equal(t, v1beta1.AppDeploymentPhaseReady, appDeployment.Status.Phase)
+		assert.Equal(t, map[string]string{"endpoint": "https://example.com"}, appDeployment.Status.Outputs)
+		assert.Equal(t, ctrlutils.OutputsSecretName(appDeployment), appDeployment.Status.OutputsSecretName)
+	})
+
+	t.Run("Happy path: empty outputs secret is deleted", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
+		appDeployment.Status.OutputsSecretName = ctrlutils.OutputsSecretName(appDeployment)
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
+				*obj.(*batchv1.Job) = batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "default"},
+					Status:     batchv1.JobStatus{Succeeded: 1},
+				}
+				return nil
+			})
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any(), gomock.Any()).Return(nil)
+		expectOutputsSecret(mockClient, map[string][]byte{})
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+				assert.Equal(t, ctrlutils.OutputsSecretName(appDeployment), obj.GetName())
+				return nil
+			})
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&batchv1.Job{}), gomock.Any()).Return(nil)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		res, err := adapter.EnsureDeployingFinished(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+		assert.Equal(t, v1beta1.AppDeploymentPhaseReady, appDeployment.Status.Phase)
+		assert.Empty(t, appDeployment.Status.OutputsSecretName)
+	})
+
+	t.Run("Happy path: invalid outputs are ignored", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
+				*obj.(*batchv1.Job) = batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "default"},
+					Status:     batchv1.JobStatus{Succeeded: 1},
+				}
+				return nil
+			})
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				list.(*corev1.PodList).Items = []corev1.Pod{{
+					Status: corev1.PodStatus{
+						Phase: corev1.PodSucceeded,
+						ContainerStatuses: []corev1.ContainerStatus{{
+							State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "done"}},
+						}},
+					},
+				}}
+				return nil
+			})
+		mockRecorder.EXPECT().Event(appDeployment, "Warning", "InvalidJobOutputs", gomock.Any())
+		expectOutputsSecret(mockClient, nil)
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).Return(nil)
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&batchv1.Job{}), gomock.Any()).Return(nil)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		res, err := adapter.EnsureDeployingFinished(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+		assert.Equal(t, v1beta1.AppDeploymentPhaseReady, appDeployment.Status.Phase)
+		assert.Empty(t, appDeployment.Status.Outputs)
 	})
 
 	t.Run("Happy path: deploying create new job", func(t *testing.T) {
@@ -518,30 +678,85 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished(t *testing.T) {
 		mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
+		_ = v1beta1.AddToScheme(scheme)
 		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
 			Return(k8serr.NewNotFound(batchv1.Resource("job"), "test-job"))
-
-		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		secretName := ctrlutils.OutputsSecretName(appDeployment)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				// the secret outputs of a previous run
+				obj.(*corev1.Secret).Data = map[string][]byte{"password": []byte("old")}
+				return nil
+			})
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+				assert.Empty(t, obj.(*corev1.Secret).Data)
+				assert.Len(t, obj.GetOwnerReferences(), 1)
+				return nil
+			})
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&rbacv1.Role{})).
+			Return(k8serr.NewNotFound(rbacv1.Resource("role"), secretName))
+		mockClient.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&rbacv1.Role{})).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+				assert.Equal(t, []string{secretName}, obj.(*rbacv1.Role).Rules[0].ResourceNames)
+				return nil
+			})
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&rbacv1.RoleBinding{})).
+			Return(k8serr.NewNotFound(rbacv1.Resource("rolebinding"), secretName))
+		mockClient.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&rbacv1.RoleBinding{})).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+				roleBinding := obj.(*rbacv1.RoleBinding)
+				assert.Equal(t, secretName, roleBinding.RoleRef.Name)
+				assert.Equal(t, "default", roleBinding.Subjects[0].Name)
+				return nil
+			})
+		mockClient.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&batchv1.Job{})).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+				assert.Contains(t, obj.(*batchv1.Job).Spec.Template.Spec.Containers[0].Env,
+					corev1.EnvVar{Name: ctrlutils.OutputsSecretEnvKey, Value: secretName})
+				return nil
+			})
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		res, err := adapter.EnsureDeployingFinished(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
 	})
 
-	t.Run("Happy path: deploying job failed, create new job", func(t *testing.T) {
+	t.Run("Error preparing outputs secret", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
+			Return(k8serr.NewNotFound(batchv1.Resource("job"), "test-job"))
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&corev1.Secret{})).
+			Return(errors.New("get secret error"))
+		mockRecorder.EXPECT().Event(appDeployment, "Error", "FailedPrepareJobOutputs", gomock.Any())
+
+		res, err := adapter.EnsureDeployingFinished(ctx)
+		assert.ErrorContains(t, err, "get secret error")
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+	})
+
+	t.Run("Happy path: deploying job failed, retry after backoff", func(t *testing.T) {
 		failedJob := batchv1.Job{
 			Status: batchv1.JobStatus{
 				Conditions: []batchv1.JobCondition{
 					{
-						Type:   batchv1.JobFailed,
-						Status: "True",
+						Type:    batchv1.JobFailed,
+						Status:  "True",
+						Reason:  "BackoffLimitExceeded",
+						Message: "Job has reached the specified backoff limit",
 					},
 				},
 				Failed: 1,
@@ -559,12 +774,9 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished(t *testing.T) {
 		mockStatusCtrl := gomock.NewController(t)
 		mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
-		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
-		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).DoAndReturn(
@@ -572,12 +784,52 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished(t *testing.T) {
 				*obj.(*batchv1.Job) = failedJob
 				return nil
 			})
-		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(nil)
-		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(nil)
-		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, list *corev1.PodList, opts ...client.ListOption) error {
+				list.Items = []corev1.Pod{{
+					Status: corev1.PodStatus{
+						Phase: corev1.PodFailed,
+						ContainerStatuses: []corev1.ContainerStatus{{
+							State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "connection refused"}},
+						}},
+					},
+				}}
+				return nil
+			})
+		mockRecorder.EXPECT().Event(appDeployment, "Warning", "ProvisionJobFailed", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 		res, err := adapter.EnsureDeployingFinished(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: 10 * time.Second, RequeueRequest: true}, res)
+		assert.Equal(t, v1beta1.AppDeploymentPhaseDeploying, appDeployment.Status.Phase)
+		assert.Equal(t, int32(1), appDeployment.Status.ProvisionFailures)
+		// the failed job is kept and the retry gets a job of its own
+		assert.Equal(t, []string{"test-job"}, appDeployment.Status.FailedJobs)
+		assert.Equal(t, int32(1), appDeployment.Status.ProvisionAttempt)
+		assert.NotEqual(t, ctrlutils.GetProvisionJobName(validAppDeployment), ctrlutils.GetProvisionJobName(appDeployment))
+		assert.NotNil(t, appDeployment.Status.LastFailureTime)
+		condition := meta.FindStatusCondition(appDeployment.Status.Conditions, v1beta1.AppDeploymentConditionProvisionFailed)
+		require.NotNil(t, condition)
+		assert.Equal(t, "BackoffLimitExceeded", condition.Reason)
+		assert.Contains(t, condition.Message, "connection refused")
+	})
+
+	t.Run("Happy path: deploying backs off after failure", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
+		appDeployment.Status.ProvisionFailures = 2
+		appDeployment.Status.LastFailureTime = ptr.Of(metav1.Now())
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureDeployingFinished(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.RequeueRequest)
+		assert.Greater(t, res.RequeueDelay, 10*time.Second)
+		assert.LessOrEqual(t, res.RequeueDelay, 20*time.Second)
 	})
 }
 
@@ -591,7 +843,7 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished_JobErrors(t *testing.T) {
 		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
@@ -605,11 +857,11 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished_JobErrors(t *testing.T) {
 	})
 }
 
-func TestAppDeploymentAdapter_EnsureDeployingFinished_FailedJobRecreateErrors(t *testing.T) {
+func TestAppDeploymentAdapter_EnsureDeployingFinished_RetriesExhausted(t *testing.T) {
 	ctx := context.Background()
 	logger := log.FromContext(ctx)
 
-	t.Run("Error setting controller reference when recreating failed job", func(t *testing.T) {
+	t.Run("Failed job without retries left fails the app deployment", func(t *testing.T) {
 		failedJob := batchv1.Job{
 			Status: batchv1.JobStatus{
 				Conditions: []batchv1.JobCondition{
@@ -628,15 +880,17 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished_FailedJobRecreateErrors(t
 
 		mockCtrl := gomock.NewController(t)
 		mockClient := mockpkg.NewMockClient(mockCtrl)
-		mockRecorderCtrl := gomock.NewController(t)
-		mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
-
-		scheme := runtime.NewScheme()
-		// Not adding v1alpha1 to scheme to force controller reference error
-		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+		appDeployment.Spec.MaxRetries = ptr.Of(int32(1))
+		appDeployment.Spec.FailedJobsHistoryLimit = ptr.Of(int32(1))
+		appDeployment.Status.FailedJobs = []string{"old-job"}
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeploying
+		appDeployment.Status.ProvisionFailures = 1
+		appDeployment.Status.LastFailureTime = ptr.Of(metav1.NewTime(time.Now().Add(-time.Hour)))
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).DoAndReturn(
@@ -644,60 +898,69 @@ func TestAppDeploymentAdapter_EnsureDeployingFinished_FailedJobRecreateErrors(t
 				*obj.(*batchv1.Job) = failedJob
 				return nil
 			})
-		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(nil)
-
-		// No need to expect Create since it should fail at SetControllerReference
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any()).Return(nil)
+		// only the most recent failed job is kept
+		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, obj *batchv1.Job, opts ...client.DeleteOption) error {
+			assert.Equal(t, "old-job", obj.Name)
+			return nil
+		})
+		mockRecorder.EXPECT().Event(appDeployment, "Warning", "ProvisionFailed", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 		result, err := adapter.EnsureDeployingFinished(ctx)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to set controller reference for job")
-		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, result)
+		assert.NoError(t, err)
+		assert.True(t, result.CancelRequest)
+		assert.Equal(t, v1beta1.AppDeploymentPhaseFailed, appDeployment.Status.Phase)
+		assert.Equal(t, int32(2), appDeployment.Status.ProvisionFailures)
+		assert.Equal(t, []string{"test-job"}, appDeployment.Status.FailedJobs)
+		condition := meta.FindStatusCondition(appDeployment.Status.Conditions, v1beta1.AppDeploymentConditionProvisionFailed)
+		require.NotNil(t, condition)
+		assert.Equal(t, ctrlutils.JobFailureReasonUnknown, condition.Reason)
 	})
 
-	t.Run("Error creating job when recreating failed job", func(t *testing.T) {
-		failedJob := batchv1.Job{
-			Status: batchv1.JobStatus{
-				Conditions: []batchv1.JobCondition{
-					{
-						Type:   batchv1.JobFailed,
-						Status: "True",
-					},
-				},
-				Failed: 1,
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-job",
-				Namespace: "default",
-			},
-		}
-
+	t.Run("Failed app deployment waits for a retry", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		mockClient := mockpkg.NewMockClient(mockCtrl)
-		mockRecorderCtrl := gomock.NewController(t)
-		mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
-
-		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
-		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeploying
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseFailed
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).DoAndReturn(
-			func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
-				*obj.(*batchv1.Job) = failedJob
-				return nil
-			})
-		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(nil)
+		result, err := adapter.EnsureDeployingFinished(ctx)
+		assert.NoError(t, err)
+		assert.True(t, result.CancelRequest)
+	})
 
-		expectedErr := errors.New("create job error")
-		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(expectedErr)
+	t.Run("Failed app deployment annotated to retry", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Annotations = map[string]string{ctrlutils.AnnotationNameRetry: "true"}
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseFailed
+		appDeployment.Status.ProvisionFailures = 4
+		appDeployment.Status.LastFailureTime = ptr.Of(metav1.Now())
+		appDeployment.Status.Conditions = []metav1.Condition{{Type: v1beta1.AppDeploymentConditionProvisionFailed, Status: metav1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+		mockClient.EXPECT().Update(ctx, appDeployment).DoAndReturn(func(ctx context.Context, obj *v1beta1.AppDeployment, opts ...client.UpdateOption) error {
+			assert.NotContains(t, obj.Annotations, ctrlutils.AnnotationNameRetry)
+			return nil
+		})
+		mockRecorder.EXPECT().Event(appDeployment, "Normal", "Retrying", gomock.Any())
+		mockStatusWriter.EXPECT().Update(ctx, appDeployment).Return(nil)
 
 		result, err := adapter.EnsureDeployingFinished(ctx)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to create job")
-		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, result)
+		assert.NoError(t, err)
+		assert.False(t, result.RequeueOrCancel())
+		assert.Equal(t, v1beta1.AppDeploymentPhaseDeploying, appDeployment.Status.Phase)
+		assert.Zero(t, appDeployment.Status.ProvisionFailures)
+		assert.Nil(t, appDeployment.Status.LastFailureTime)
+		assert.Empty(t, appDeployment.Status.Conditions)
 	})
 }
 
@@ -761,7 +1024,7 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished(t *testing.T) {
 
 	t.Run("Happy path: teardown finished", func(t *testing.T) {
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleting
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
 		logger := log.FromContext(ctx)
 
 		mockCtrl := gomock.NewController(t)
@@ -789,7 +1052,7 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished(t *testing.T) {
 	})
 	t.Run("Happy path: teardown create new job", func(t *testing.T) {
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleting
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
 		logger := log.FromContext(ctx)
 
 		mockCtrl := gomock.NewController(t)
@@ -800,7 +1063,7 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished(t *testing.T) {
 		mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
+		_ = v1beta1.AddToScheme(scheme)
 		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
@@ -816,21 +1079,16 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
 	})
-	t.Run("Happy path: teardown job failed, create new job", func(t *testing.T) {
+	t.Run("Happy path: teardown job failed, retry after backoff", func(t *testing.T) {
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleting
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
 		logger := log.FromContext(ctx)
 
 		mockCtrl := gomock.NewController(t)
 		mockClient := mockpkg.NewMockClient(mockCtrl)
-		mockRecorderCtrl := gomock.NewController(t)
-		mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
-		mockStatusCtrl := gomock.NewController(t)
-		mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
-		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
-		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 		assert.NotNil(t, adapter)
@@ -840,14 +1098,142 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished(t *testing.T) {
 				*obj.(*batchv1.Job) = failedJob
 				return nil
 			})
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any()).Return(nil)
 		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(nil)
-		mockRecorder.EXPECT().Event(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
-		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockRecorder.EXPECT().Event(appDeployment, "Warning", "TeardownJobFailed", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 		res, err := adapter.EnsureTeardownFinished(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: false}, res)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: 10 * time.Second, RequeueRequest: true}, res)
+		// the app deployment is not deleted until the teardown job succeeds
+		assert.Equal(t, v1beta1.AppDeploymentPhaseDeleting, appDeployment.Status.Phase)
+		assert.Equal(t, int32(1), appDeployment.Status.TeardownFailures)
+		assert.True(t, meta.IsStatusConditionTrue(appDeployment.Status.Conditions, v1beta1.AppDeploymentConditionTeardownFailed))
+	})
+
+	t.Run("Happy path: teardown backs off after failure", func(t *testing.T) {
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
+		appDeployment.Status.TeardownFailures = 1
+		appDeployment.Status.LastFailureTime = ptr.Of(metav1.Now())
+		logger := log.FromContext(ctx)
+
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureTeardownFinished(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.RequeueRequest)
+		assert.LessOrEqual(t, res.RequeueDelay, 10*time.Second)
 	})
 
+	t.Run("Happy path: teardown job failed, orphan resources", func(t *testing.T) {
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.UID = "uid"
+		appDeployment.Spec.TeardownPolicy = v1beta1.TeardownPolicyOrphan
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
+		appDeployment.Status.Outputs = map[string]string{"endpoint": "db.example.com"}
+		logger := log.FromContext(ctx)
+
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
+				*obj.(*batchv1.Job) = failedJob
+				return nil
+			})
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+			Return(k8serr.NewNotFound(corev1.Resource("configmap"), ctrlutils.OrphanedResourcesConfigMapName))
+		mockClient.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+			configMap := obj.(*corev1.ConfigMap)
+			assert.Equal(t, ctrlutils.OrphanedResourcesConfigMapName, configMap.Name)
+			// the record outlives the app deployment
+			assert.Empty(t, configMap.OwnerReferences)
+			assert.Contains(t, configMap.Data[appDeployment.Name+".uid"], "db.example.com")
+			return nil
+		})
+		mockRecorder.EXPECT().Event(appDeployment, "Warning", "TeardownOrphaned", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		res, err := adapter.EnsureTeardownFinished(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+		assert.Equal(t, v1beta1.AppDeploymentPhaseDeleted, appDeployment.Status.Phase)
+	})
+
+	t.Run("Happy path: teardown job failed, block deletion", func(t *testing.T) {
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Spec.TeardownPolicy = v1beta1.TeardownPolicyBlock
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
+		logger := log.FromContext(ctx)
+
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&batchv1.Job{})).
+			DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opts ...client.GetOption) error {
+				*obj.(*batchv1.Job) = failedJob
+				return nil
+			})
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(nil)
+		mockRecorder.EXPECT().Event(appDeployment, "Warning", "TeardownBlocked", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		res, err := adapter.EnsureTeardownFinished(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, CancelRequest: true}, res)
+		assert.Equal(t, v1beta1.AppDeploymentPhaseDeleting, appDeployment.Status.Phase)
+
+		// a blocked teardown waits for the retry annotation
+		res, err = adapter.EnsureTeardownFinished(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{CancelRequest: true}, res)
+	})
+
+	t.Run("Happy path: blocked teardown annotated to retry", func(t *testing.T) {
+		appDeployment := validAppDeployment.DeepCopy()
+		appDeployment.Annotations = map[string]string{ctrlutils.AnnotationNameRetry: ctrlutils.AnnotationValueTrue}
+		appDeployment.Spec.TeardownPolicy = v1beta1.TeardownPolicyBlock
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
+		appDeployment.Status.TeardownFailures = 1
+		meta.SetStatusCondition(&appDeployment.Status.Conditions, metav1.Condition{
+			Type:   v1beta1.AppDeploymentConditionTeardownFailed,
+			Status: metav1.ConditionTrue,
+			Reason: "BackoffLimitExceeded",
+		})
+		logger := log.FromContext(ctx)
+
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+
+		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
+
+		mockClient.EXPECT().Update(ctx, appDeployment).Return(nil)
+		mockRecorder.EXPECT().Event(appDeployment, "Normal", "Retrying", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		res, err := adapter.EnsureTeardownFinished(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+		assert.NotContains(t, appDeployment.Annotations, ctrlutils.AnnotationNameRetry)
+		assert.Nil(t, meta.FindStatusCondition(appDeployment.Status.Conditions, v1beta1.AppDeploymentConditionTeardownFailed))
+	})
 }
 
 func TestAppDeploymentAdapter_EnsureTeardownFinished_JobErrors(t *testing.T) {
@@ -862,7 +1248,7 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished_JobErrors(t *testing.T) {
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleting
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
@@ -905,7 +1291,7 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished_JobErrors(t *testing.T) {
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleting
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
@@ -925,11 +1311,11 @@ func TestAppDeploymentAdapter_EnsureTeardownFinished_JobErrors(t *testing.T) {
 		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
 		mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
+		_ = v1beta1.AddToScheme(scheme)
 		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
 
 		appDeployment := validAppDeployment.DeepCopy()
-		appDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseDeleting
+		appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseDeleting
 
 		adapter := NewAppDeploymentHandler(ctx, appDeployment, logger, mockClient, mockRecorder)
 
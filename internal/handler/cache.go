@@ -9,13 +9,19 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/advisor"
+	"github.com/Azure/operation-cache-controller/internal/metrics"
 	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	randutils "github.com/Azure/operation-cache-controller/internal/utils/rand"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
@@ -24,13 +30,14 @@ import (
 //go:generate mockgen -destination=./mocks/mock_cache.go -package=mocks github.com/Azure/operation-cache-controller/internal/handler CacheHandlerInterface
 type CacheHandlerInterface interface {
 	CheckCacheExpiry(ctx context.Context) (reconciler.OperationResult, error)
+	EnsureCacheKeyMigrated(ctx context.Context) (reconciler.OperationResult, error)
 	EnsureCacheInitialized(ctx context.Context) (reconciler.OperationResult, error)
 	CalculateKeepAliveCount(ctx context.Context) (reconciler.OperationResult, error)
 	AdjustCache(ctx context.Context) (reconciler.OperationResult, error)
 }
 
 type CacheHandler struct {
-	cache                      *v1alpha1.Cache
+	cache                      *v1beta1.Cache
 	logger                     logr.Logger
 	client                     client.Client
 	scheme                     *runtime.Scheme
@@ -38,11 +45,14 @@ type CacheHandler struct {
 	cacheUtils                 ctrlutils.CacheHelper
 	oputils                    ctrlutils.OperationHelper
 	setControllerReferenceFunc func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error
+	// advisor is optional, the keepAliveCount is calculated locally when it is nil or unreachable
+	advisor advisor.KeepAliveAdvisor
 }
 
 func NewCacheHandler(ctx context.Context,
-	cache *v1alpha1.Cache, logger logr.Logger, client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder,
-	fn func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error) CacheHandlerInterface {
+	cache *v1beta1.Cache, logger logr.Logger, client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder,
+	fn func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error,
+	keepAliveAdvisor advisor.KeepAliveAdvisor) CacheHandlerInterface {
 	return &CacheHandler{
 		cache:                      cache,
 		logger:                     logger,
@@ -50,6 +60,7 @@ func NewCacheHandler(ctx context.Context,
 		scheme:                     scheme,
 		recorder:                   recorder,
 		setControllerReferenceFunc: fn,
+		advisor:                    keepAliveAdvisor,
 	}
 }
 
@@ -61,25 +72,139 @@ func (c *CacheHandler) updateStatus(ctx context.Context) error {
 	return nil
 }
 
-// CheckCacheExpiry checks if the cache cr is expired. If it is, the cr is deleted.
+// CheckCacheExpiry checks if the cache cr is expired. If it is, the cr is deleted, otherwise it is reconciled again by
+// the time it is expiring soon or expires.
 func (c *CacheHandler) CheckCacheExpiry(ctx context.Context) (reconciler.OperationResult, error) {
-	if c.cache.Spec.ExpireTime == "" {
-		return reconciler.ContinueProcessing()
+	expiry := ctrlutils.Expiry{ExpireAt: c.cache.Spec.ExpireTime, TTLAfterLastAccess: c.cache.Spec.TTLAfterLastAccess}
+	// a cache is usable once it is created, the ttl after last access counts from then if it was never accessed
+	expireTime := expiry.Deadline(&c.cache.CreationTimestamp, c.cache.Status.LastAccessTime)
+	now := time.Now()
+	changed := !ctrlutils.TimeEqual(expireTime, c.cache.Status.ExpireTime)
+	c.cache.Status.ExpireTime = expireTime
+	conditionChanged, expiringSoon := ctrlutils.SetExpiringSoonCondition(&c.cache.Status.Conditions, expireTime, now)
+	if changed || conditionChanged {
+		if err := c.updateStatus(ctx); err != nil {
+			return reconciler.RequeueWithError(err)
+		}
 	}
-	ce, err := time.Parse(time.RFC3339, c.cache.Spec.ExpireTime)
-	if err != nil {
-		c.logger.Error(err, "failed to parse expire time")
-		// TODO: set cache expiry condition if needed
+	if expireTime == nil {
 		return reconciler.ContinueProcessing()
 	}
-	if time.Now().After(ce) {
+	if now.After(expireTime.Time) {
 		c.logger.Info("cache is expired, deleting cache cr")
 		if err := c.client.Delete(ctx, c.cache); err != nil {
 			return reconciler.RequeueWithError(err)
 		}
+		metrics.DeleteCachePool(c.cache.Namespace, c.cache.Name)
 		return reconciler.StopProcessing()
 	}
-	return reconciler.ContinueProcessing()
+	if expiringSoon {
+		c.recorder.Event(c.cache, "Warning", "ExpiringSoon", fmt.Sprintf("Cache expires at %s, extend it to keep it", expireTime.UTC().Format(time.RFC3339)))
+	}
+	return reconciler.ContinueProcessingWithin(ctrlutils.ExpiryRequeueDelay(expireTime, now))
+}
+
+// EnsureCacheKeyMigrated re-keys a cache whose key was computed by an older version of the cache key algorithm.
+// Requirements look up the cache named after the current key, so the operations of a cache named after its old key
+// are handed over to a cache named after the new one and the outdated cache is deleted. Caches with other names are
+// re-keyed in place.
+func (c *CacheHandler) EnsureCacheKeyMigrated(ctx context.Context) (reconciler.OperationResult, error) {
+	oldKey := c.cache.Status.CacheKey
+	if oldKey == "" || c.cacheUtils.IsCurrentCacheKey(oldKey) {
+		return reconciler.ContinueProcessing()
+	}
+	newKey := c.cacheUtils.NewCacheKeyFromApplications(c.cache.Spec.OperationTemplate.Applications)
+	logger := c.logger.WithValues("oldCacheKey", oldKey, "newCacheKey", newKey)
+
+	var ownedOps v1beta1.OperationList
+	if err := c.client.List(ctx, &ownedOps, client.InNamespace(c.cache.Namespace), client.MatchingFields{v1beta1.CacheOwnerKey: c.cache.Name}); err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+
+	if c.cache.Name != c.cacheUtils.CacheName(oldKey) {
+		logger.Info("re-keying cache in place")
+		for i := range ownedOps.Items {
+			if err := c.rekeyOperation(ctx, &ownedOps.Items[i], nil, newKey); err != nil {
+				return reconciler.RequeueWithError(err)
+			}
+		}
+		c.cache.Status.CacheKey = newKey
+		return reconciler.RequeueOnErrorOrContinue(c.updateStatus(ctx))
+	}
+
+	target, err := c.ensureMigrationTarget(ctx, newKey)
+	if err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	for i := range ownedOps.Items {
+		if err := c.rekeyOperation(ctx, &ownedOps.Items[i], target, newKey); err != nil {
+			return reconciler.RequeueWithError(err)
+		}
+	}
+	logger.Info("operations handed over to the re-keyed cache, deleting outdated cache", "cache", target.Name)
+	c.recorder.Event(c.cache, "Normal", "CacheKeyMigrated", fmt.Sprintf("Operations handed over to cache %s", target.Name))
+	if err := c.client.Delete(ctx, c.cache); client.IgnoreNotFound(err) != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	metrics.DeleteCachePool(c.cache.Namespace, c.cache.Name)
+	return reconciler.StopProcessing()
+}
+
+// ensureMigrationTarget returns the cache named after the new key, creating it with the spec and the observed demand of
+// the outdated cache when it does not exist yet
+func (c *CacheHandler) ensureMigrationTarget(ctx context.Context, newKey string) (*v1beta1.Cache, error) {
+	target := &v1beta1.Cache{}
+	err := c.client.Get(ctx, client.ObjectKey{Namespace: c.cache.Namespace, Name: c.cacheUtils.CacheName(newKey)}, target)
+	if err == nil {
+		return target, nil
+	}
+	if client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get cache %s: %w", c.cacheUtils.CacheName(newKey), err)
+	}
+	target = &v1beta1.Cache{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        c.cacheUtils.CacheName(newKey),
+			Namespace:   c.cache.Namespace,
+			Labels:      c.cache.Labels,
+			Annotations: c.cache.Annotations,
+		},
+		Spec: *c.cache.Spec.DeepCopy(),
+	}
+	if err := c.client.Create(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to create cache %s: %w", target.Name, err)
+	}
+	target.Status = v1beta1.CacheStatus{
+		CacheKey:        newKey,
+		KeepAliveCount:  c.cache.Status.KeepAliveCount,
+		AvailableCaches: []string{},
+		Demand:          *c.cache.Status.Demand.DeepCopy(),
+	}
+	if err := c.client.Status().Update(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to initialize status of cache %s: %w", target.Name, err)
+	}
+	return target, nil
+}
+
+// rekeyOperation relabels an operation of the cache with the new key and hands it over to the target cache if set.
+// The patch fails when a requirement acquired the operation in the meantime, which then belongs to the requirement.
+func (c *CacheHandler) rekeyOperation(ctx context.Context, op *v1beta1.Operation, target *v1beta1.Cache, newKey string) error {
+	original := op.DeepCopy()
+	if op.Labels == nil {
+		op.Labels = map[string]string{}
+	}
+	op.Labels[ctrlutils.LabelNameCacheKey] = c.cacheUtils.CacheKeyLabelValue(newKey)
+	if target != nil {
+		op.OwnerReferences = lo.Filter(op.OwnerReferences, func(ref metav1.OwnerReference, _ int) bool {
+			return ref.UID != c.cache.UID
+		})
+		if err := c.setControllerReferenceFunc(target, op, c.scheme); err != nil {
+			return fmt.Errorf("failed to hand over operation %s: %w", op.Name, err)
+		}
+	}
+	if err := c.client.Patch(ctx, op, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to re-key operation %s: %w", op.Name, err)
+	}
+	return nil
 }
 
 // EnsureCacheInitialized ensures the cache cr is initialized
@@ -95,14 +220,42 @@ func (c *CacheHandler) EnsureCacheInitialized(ctx context.Context) (reconciler.O
 	return reconciler.RequeueOnErrorOrContinue(c.updateStatus(ctx))
 }
 
-// CalculateKeepAliveCount calculates the keepAliveCount for the cache cr
+// CalculateKeepAliveCount calculates the keepAliveCount for the cache cr from the demand observed over the demand window,
+// or asks the keep-alive advisor for it when one is configured
 func (c *CacheHandler) CalculateKeepAliveCount(ctx context.Context) (reconciler.OperationResult, error) {
-	// before we have cache service to provide the keepAliveCount, we use fixed value
-	c.cache.Status.KeepAliveCount = 5
+	c.cacheUtils.ObserveDemand(c.cache, time.Now())
+	keepAliveCount := c.cacheUtils.KeepAliveCountFromDemand(c.cache)
+	if c.advisor != nil {
+		advised, err := c.advisor.KeepAliveCount(ctx, c.advisorRequest(keepAliveCount))
+		if err != nil {
+			c.logger.Error(err, "keep-alive advisor unavailable, falling back to local keepAliveCount", "keepAliveCount", keepAliveCount)
+		} else {
+			minCount, maxCount := c.cacheUtils.KeepAliveCountBounds(c.cache)
+			keepAliveCount = min(max(advised, minCount), maxCount)
+		}
+	}
+	c.cache.Status.KeepAliveCount = keepAliveCount
 	return reconciler.RequeueOnErrorOrContinue(c.updateStatus(ctx))
 }
 
-func (c *CacheHandler) createOperationsAsync(ctx context.Context, ops []*v1alpha1.Operation) error {
+func (c *CacheHandler) advisorRequest(localKeepAliveCount int32) advisor.Request {
+	minCount, maxCount := c.cacheUtils.KeepAliveCountBounds(c.cache)
+	return advisor.Request{
+		Namespace:           c.cache.Namespace,
+		Name:                c.cache.Name,
+		CacheKey:            c.cache.Status.CacheKey,
+		Acquisitions:        c.cache.Status.Demand.Acquisitions,
+		Misses:              c.cache.Status.Demand.Misses,
+		WindowSeconds:       int64(ctrlutils.DemandWindow.Seconds()),
+		KeepAliveCount:      c.cache.Status.KeepAliveCount,
+		AvailableCount:      int32(len(c.cache.Status.AvailableCaches)),
+		LocalKeepAliveCount: localKeepAliveCount,
+		MinKeepAliveCount:   minCount,
+		MaxKeepAliveCount:   maxCount,
+	}
+}
+
+func (c *CacheHandler) createOperationsAsync(ctx context.Context, ops []*v1beta1.Operation) error {
 	wg := sync.WaitGroup{}
 	errChan := make(chan error, len(ops))
 	for _, op := range ops {
@@ -121,7 +274,131 @@ func (c *CacheHandler) createOperationsAsync(ctx context.Context, ops []*v1alpha
 	return errs
 }
 
-func (c *CacheHandler) deleteOperationsAsync(ctx context.Context, ops []*v1alpha1.Operation) error {
+// recycleStuckOperations deletes the operations which are not ready within the provision timeout of the cache and
+// returns the remaining ones
+func (c *CacheHandler) recycleStuckOperations(ctx context.Context, ops []v1beta1.Operation) ([]v1beta1.Operation, error) {
+	if c.cache.Spec.ProvisionTimeout == nil {
+		return ops, nil
+	}
+	now := time.Now()
+	stuckOps := []*v1beta1.Operation{}
+	remainingOps := []v1beta1.Operation{}
+	for i := range ops {
+		if c.oputils.IsOperationStuck(&ops[i], c.cache.Spec.ProvisionTimeout.Duration, now) {
+			stuckOps = append(stuckOps, &ops[i])
+		} else {
+			remainingOps = append(remainingOps, ops[i])
+		}
+	}
+	if len(stuckOps) == 0 {
+		return ops, nil
+	}
+	stuckOpNames := lo.Map(stuckOps, func(op *v1beta1.Operation, _ int) string { return op.Name })
+	c.logger.Info("recycling stuck operations", "operations", stuckOpNames, "provisionTimeout", c.cache.Spec.ProvisionTimeout.Duration)
+	if err := c.deleteOperationsAsync(ctx, stuckOps); err != nil {
+		return nil, err
+	}
+	c.cache.Status.RecycledOperations += int32(len(stuckOps))
+	metrics.RecordRecycledOperations(c.cache.Namespace, c.cache.Name, len(stuckOps))
+	c.recorder.Event(c.cache, "Warning", "OperationsRecycled",
+		fmt.Sprintf("Recycled operations not ready within %s: %s", c.cache.Spec.ProvisionTimeout.Duration, strings.Join(stuckOpNames, ", ")))
+	return remainingOps, nil
+}
+
+// resetReleasedOperations runs the reset job of the cache for the operations released by their requirements. A reset
+// operation rejoins the pool with its reuse counted. Operations failing their reset, or released to a cache which does
+// not recycle operations anymore, are deleted and left out of the pool.
+func (c *CacheHandler) resetReleasedOperations(ctx context.Context, ops []v1beta1.Operation) ([]v1beta1.Operation, error) {
+	opsToDelete := []*v1beta1.Operation{}
+	remainingOps := []v1beta1.Operation{}
+	for i := range ops {
+		op := &ops[i]
+		if !c.oputils.IsOperationReleased(op) || !op.DeletionTimestamp.IsZero() {
+			remainingOps = append(remainingOps, *op)
+			continue
+		}
+		if c.cache.Spec.Recycle == nil {
+			opsToDelete = append(opsToDelete, op)
+			continue
+		}
+		jobStatus, err := c.ensureResetJob(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		switch jobStatus {
+		case ctrlutils.JobStatusFailed:
+			opsToDelete = append(opsToDelete, op)
+			continue
+		case ctrlutils.JobStatusSucceeded:
+			if err := c.completeReset(ctx, op); err != nil {
+				return nil, err
+			}
+		}
+		remainingOps = append(remainingOps, *op)
+	}
+	if len(opsToDelete) == 0 {
+		return remainingOps, nil
+	}
+	opNames := lo.Map(opsToDelete, func(op *v1beta1.Operation, _ int) string { return op.Name })
+	c.logger.Info("deleting released operations which cannot be reset", "operations", opNames)
+	if err := c.deleteOperationsAsync(ctx, opsToDelete); err != nil {
+		return nil, err
+	}
+	c.recorder.Event(c.cache, "Warning", "ResetFailed", fmt.Sprintf("Deleted released operations which cannot be reset: %s", strings.Join(opNames, ", ")))
+	return remainingOps, nil
+}
+
+// ensureResetJob creates the reset job of the released operation if it does not exist yet and returns its status
+func (c *CacheHandler) ensureResetJob(ctx context.Context, op *v1beta1.Operation) (ctrlutils.JobStatus, error) {
+	job := &batchv1.Job{}
+	key := types.NamespacedName{Name: ctrlutils.GetResetJobName(op), Namespace: op.Namespace}
+	if err := c.client.Get(ctx, key, job); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("failed to get reset job %s: %w", key.Name, err)
+		}
+		job = ctrlutils.ResetJobFromCache(c.cache, op)
+		// the job is deleted with the operation
+		if err := c.setControllerReferenceFunc(op, job, c.scheme); err != nil {
+			return "", fmt.Errorf("failed to set controller reference of reset job %s: %w", job.Name, err)
+		}
+		c.logger.Info("resetting released operation", "operation", op.Name, "job", job.Name)
+		if err := c.client.Create(ctx, job); err != nil {
+			return "", fmt.Errorf("failed to create reset job %s: %w", job.Name, err)
+		}
+		return ctrlutils.JobStatusRunning, nil
+	}
+	if !job.DeletionTimestamp.IsZero() {
+		return ctrlutils.JobStatusRunning, nil
+	}
+	// a reuse which was not counted leaves the job of the previous reset behind under the same name
+	if releasedAt, err := time.Parse(time.RFC3339, op.Annotations[v1beta1.OperationReleasedAnnotationKey]); err == nil && job.CreationTimestamp.Time.Before(releasedAt) {
+		c.logger.Info("deleting reset job of a previous release", "operation", op.Name, "job", job.Name)
+		if err := c.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("failed to delete reset job %s: %w", job.Name, err)
+		}
+		return ctrlutils.JobStatusRunning, nil
+	}
+	return ctrlutils.CheckJobStatus(ctx, job), nil
+}
+
+// completeReset returns the reset operation to the pool and counts its reuse. The operation leaves the released state
+// first, so a failure in between never resets and counts it twice; at worst one reuse is not counted.
+func (c *CacheHandler) completeReset(ctx context.Context, op *v1beta1.Operation) error {
+	original := op.DeepCopy()
+	delete(op.Annotations, v1beta1.OperationReleasedAnnotationKey)
+	if err := c.client.Patch(ctx, op, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to return operation %s to the pool: %w", op.Name, err)
+	}
+	op.Status.ReuseCount++
+	if err := c.client.Status().Update(ctx, op); err != nil {
+		return fmt.Errorf("failed to count reuse of operation %s: %w", op.Name, err)
+	}
+	c.logger.Info("released operation reset", "operation", op.Name, "reuseCount", op.Status.ReuseCount)
+	c.recorder.Event(c.cache, "Normal", "OperationReset", fmt.Sprintf("Operation %s reset and returned to the pool, reused %d times", op.Name, op.Status.ReuseCount))
+	return nil
+}
+
+func (c *CacheHandler) deleteOperationsAsync(ctx context.Context, ops []*v1beta1.Operation) error {
 	wg := sync.WaitGroup{}
 	errChan := make(chan error, len(ops))
 	for _, op := range ops {
@@ -140,8 +417,8 @@ func (c *CacheHandler) deleteOperationsAsync(ctx context.Context, ops []*v1alpha
 	return errs
 }
 
-func (c *CacheHandler) initOperationFromCache(operationName string) *v1alpha1.Operation {
-	op := &v1alpha1.Operation{}
+func (c *CacheHandler) initOperationFromCache(operationName string) *v1beta1.Operation {
+	op := &v1beta1.Operation{}
 
 	annotations := op.GetAnnotations()
 	if annotations == nil {
@@ -154,11 +431,7 @@ func (c *CacheHandler) initOperationFromCache(operationName string) *v1alpha1.Op
 		labels = map[string]string{}
 	}
 	// TODO: set up requirement label instead
-	cacheKeyLabelValue := c.cache.Status.CacheKey
-	if len(c.cache.Status.CacheKey) > 63 {
-		cacheKeyLabelValue = cacheKeyLabelValue[:63]
-	}
-	labels[ctrlutils.LabelNameCacheKey] = cacheKeyLabelValue
+	labels[ctrlutils.LabelNameCacheKey] = c.cacheUtils.CacheKeyLabelValue(c.cache.Status.CacheKey)
 
 	op.SetAnnotations(annotations)
 	op.SetNamespace(c.cache.Namespace)
@@ -168,30 +441,75 @@ func (c *CacheHandler) initOperationFromCache(operationName string) *v1alpha1.Op
 	return op
 }
 
+// setStrategyCondition records whether the strategy in the cache spec is accepted
+func (c *CacheHandler) setStrategyCondition(err error) {
+	condition := metav1.Condition{
+		Type:    v1beta1.CacheConditionStrategyAccepted,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1beta1.CacheConditionReasonStrategyAccepted,
+		Message: "Cache strategy accepted",
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1beta1.CacheConditionReasonUnknownStrategy
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&c.cache.Status.Conditions, condition)
+}
+
+// AdjustCache keeps the number of available operations owned by the cache cr at the pool size decided by the cache
+// strategy
 func (c *CacheHandler) AdjustCache(ctx context.Context) (reconciler.OperationResult, error) {
-	var ownedOps v1alpha1.OperationList
-	if err := c.client.List(ctx, &ownedOps, client.InNamespace(c.cache.Namespace), client.MatchingFields{v1alpha1.CacheOwnerKey: c.cache.Name}); err != nil {
+	strategy, err := ctrlutils.NewCacheStrategy(c.cache.Spec.Strategy)
+	c.setStrategyCondition(err)
+	if err != nil {
+		c.logger.Error(err, "cache strategy rejected", "strategy", c.cache.Spec.Strategy)
+		c.recorder.Event(c.cache, "Warning", "UnknownStrategy", err.Error())
+		return reconciler.RequeueOnErrorOrStop(c.updateStatus(ctx))
+	}
+
+	var ownedOps v1beta1.OperationList
+	if err := c.client.List(ctx, &ownedOps, client.InNamespace(c.cache.Namespace), client.MatchingFields{v1beta1.CacheOwnerKey: c.cache.Name}); err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	// stuck operations are deleted and left out of the pool, so they are replaced below like any missing operation
+	ownedOps.Items, err = c.recycleStuckOperations(ctx, ownedOps.Items)
+	if err != nil {
 		return reconciler.RequeueWithError(err)
 	}
+	// released operations join the pool once they are reset, operations failing their reset are replaced like stuck ones
+	ownedOps.Items, err = c.resetReleasedOperations(ctx, ownedOps.Items)
+	if err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	// the strategy looks at the status of the previous reconcile, so it has to run before it is updated
+	newPoolSize := strategy.PoolSize(c.cache, ownedOps.Items)
+	if newPoolSize != c.cache.Status.PoolSize {
+		now := metav1.Now()
+		c.cache.Status.PoolResizeTime = &now
+	}
+	c.cache.Status.PoolSize = newPoolSize
+
 	availableCaches := []string{}
 	for _, op := range ownedOps.Items {
-		if c.oputils.IsOperationReady(&op) {
+		if c.oputils.IsOperationAvailable(&op) {
 			availableCaches = append(availableCaches, op.Name)
 		}
 	}
 	c.cache.Status.AvailableCaches = availableCaches
+	metrics.SetCachePool(c.cache.Namespace, c.cache.Name, int(c.cache.Status.KeepAliveCount), len(availableCaches))
 
-	keepAliveCount := int(c.cache.Status.KeepAliveCount)
-	cacheBalance := len(availableCaches) - keepAliveCount
+	poolSize := int(c.cache.Status.PoolSize)
+	cacheBalance := len(availableCaches) - poolSize
 	switch {
 	case cacheBalance == 0:
 		// do nothing: should we remove the not available operations?
 	case cacheBalance > 0:
-		// remove all the not available operations and cut available operations down to keepAliveCount
+		// remove all the not available operations and cut available operations down to poolSize
 		availableCacheNumToRemove := cacheBalance
-		opsToRemove := []*v1alpha1.Operation{}
+		opsToRemove := []*v1beta1.Operation{}
 		for _, op := range ownedOps.Items {
-			if !c.oputils.IsOperationReady(&op) {
+			if !c.oputils.IsOperationAvailable(&op) {
 				opsToRemove = append(opsToRemove, &op)
 			} else {
 				if availableCacheNumToRemove > 0 {
@@ -205,12 +523,12 @@ func (c *CacheHandler) AdjustCache(ctx context.Context) (reconciler.OperationRes
 			return reconciler.RequeueWithError(err)
 		}
 	case cacheBalance < 0:
-		if len(ownedOps.Items) < keepAliveCount {
-			// also count not available operations, create new operations to meet the keepAliveCount
-			opsToCreate := []*v1alpha1.Operation{}
-			opsNumToCreate := keepAliveCount - len(ownedOps.Items)
+		if len(ownedOps.Items) < poolSize {
+			// also count not available operations, create new operations to meet the poolSize
+			opsToCreate := []*v1beta1.Operation{}
+			opsNumToCreate := poolSize - len(ownedOps.Items)
 			for range opsNumToCreate {
-				opName := fmt.Sprintf("cached-operation-%s-%s", c.cache.Status.CacheKey[:8], strings.ToLower(randutils.GenerateRandomString(5)))
+				opName := c.cacheUtils.CachedOperationName(c.cache.Status.CacheKey, strings.ToLower(randutils.GenerateRandomString(5)))
 				opToCreate := c.initOperationFromCache(opName)
 				if err := c.setControllerReferenceFunc(c.cache, opToCreate, c.scheme); err != nil {
 					return reconciler.RequeueWithError(err)
@@ -222,8 +540,7 @@ func (c *CacheHandler) AdjustCache(ctx context.Context) (reconciler.OperationRes
 				return reconciler.RequeueWithError(err)
 			}
 		}
-		// else do nothing: we assume that any not ready operations are in progress and will be ready
-		// we can bring in stuck operations handling if we consider that's one case for cache controller to solve
+		// else do nothing: not ready operations are in progress, the stuck ones were recycled above
 	}
 	return reconciler.RequeueOnErrorOrContinue(c.updateStatus(ctx))
 }
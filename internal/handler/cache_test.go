@@ -10,22 +10,29 @@ import (
 	"go.uber.org/mock/gomock"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/advisor"
+	advisormocks "github.com/Azure/operation-cache-controller/internal/advisor/mocks"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	mockpkg "github.com/Azure/operation-cache-controller/internal/utils/mocks"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 )
 
 var cacheHelper = ctrlutils.NewCacheHelper()
 
 func TestNewCacheAdapter(t *testing.T) {
 	t.Run("NewCacheAdapter", func(t *testing.T) {
-		testCache := &v1alpha1.Cache{}
+		testCache := &v1beta1.Cache{}
 		testlogger := logr.Logger{}
 		scheme := runtime.NewScheme()
 		var (
@@ -36,13 +43,13 @@ func TestNewCacheAdapter(t *testing.T) {
 		)
 		mockClient = mockpkg.NewMockClient(mockClientCtrl)
 		mockRecorder = mockpkg.NewMockEventRecorder(mockRecorderCtrl)
-		adapter := NewCacheHandler(context.Background(), testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
+		adapter := NewCacheHandler(context.Background(), testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
 		assert.NotNil(t, adapter)
 	})
 }
 
-func getTestApps() []v1alpha1.ApplicationSpec {
-	return []v1alpha1.ApplicationSpec{
+func getTestApps() []v1beta1.ApplicationSpec {
+	return []v1beta1.ApplicationSpec{
 		{
 			Name: "test-app-available",
 			Provision: batchv1.JobSpec{
@@ -70,39 +77,67 @@ func TestCacheCheckCacheExpiry(t *testing.T) {
 	mockRecorderCtrl = gomock.NewController(t)
 	mockClient = mockpkg.NewMockClient(mockClientCtrl)
 	mockRecorder = mockpkg.NewMockEventRecorder(mockRecorderCtrl)
+	mockStatusWriterCtrl := gomock.NewController(t)
+	mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusWriterCtrl)
 
 	t.Run("happy path", func(t *testing.T) {
 		t.Run("cache not expired", func(t *testing.T) {
-			testCache := &v1alpha1.Cache{
+			testCache := &v1beta1.Cache{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-cache",
 					Namespace: "test-ns",
 				},
-				Spec: v1alpha1.CacheSpec{
-					ExpireTime: time.Now().Add(1 * time.Hour).Format(time.RFC3339),
+				Spec: v1beta1.CacheSpec{
+					ExpireTime: &metav1.Time{Time: time.Now().Add(1 * time.Hour)},
 				},
-				Status: v1alpha1.CacheStatus{},
+				Status: v1beta1.CacheStatus{},
 			}
-			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
 			assert.NotNil(t, adapter)
+			mockClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(ctx, testCache).Return(nil)
 
 			res, err := adapter.CheckCacheExpiry(ctx)
 			assert.Nil(t, err)
 			assert.Equal(t, false, res.RequeueRequest)
 			assert.Equal(t, false, res.CancelRequest)
+			assert.True(t, ctrlutils.TimeEqual(testCache.Spec.ExpireTime, testCache.Status.ExpireTime))
+			assert.InDelta(t, time.Hour-config.DefaultExpiringSoonWindow, res.RequeueWithin, float64(time.Second))
+		})
+		t.Run("cache expiring soon", func(t *testing.T) {
+			testCache := &v1beta1.Cache{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cache",
+					Namespace: "test-ns",
+				},
+				Spec: v1beta1.CacheSpec{
+					ExpireTime: &metav1.Time{Time: time.Now().Add(time.Minute)},
+				},
+			}
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+			mockClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(ctx, testCache).Return(nil)
+			mockRecorder.EXPECT().Event(testCache, "Warning", "ExpiringSoon", gomock.Any())
+
+			res, err := adapter.CheckCacheExpiry(ctx)
+			assert.Nil(t, err)
+			assert.False(t, res.RequeueOrCancel())
+			assert.InDelta(t, time.Minute, res.RequeueWithin, float64(time.Second))
+			assert.True(t, meta.IsStatusConditionTrue(testCache.Status.Conditions, v1beta1.ConditionExpiringSoon))
 		})
 		t.Run("cache expired", func(t *testing.T) {
-			testCache := &v1alpha1.Cache{
+			testCache := &v1beta1.Cache{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-cache",
 					Namespace: "test-ns",
 				},
-				Spec: v1alpha1.CacheSpec{
-					ExpireTime: time.Now().Add(-1 * time.Hour).Format(time.RFC3339),
+				Spec: v1beta1.CacheSpec{
+					ExpireTime: &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
 				},
-				Status: v1alpha1.CacheStatus{},
+				Status: v1beta1.CacheStatus{},
 			}
-			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
+			testCache.Status.ExpireTime = testCache.Spec.ExpireTime.DeepCopy()
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
 			assert.NotNil(t, adapter)
 			mockClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
@@ -112,15 +147,15 @@ func TestCacheCheckCacheExpiry(t *testing.T) {
 			assert.Equal(t, true, res.CancelRequest)
 		})
 		t.Run("cache expireTime not set", func(t *testing.T) {
-			testCache := &v1alpha1.Cache{
+			testCache := &v1beta1.Cache{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-cache",
 					Namespace: "test-ns",
 				},
-				Spec:   v1alpha1.CacheSpec{},
-				Status: v1alpha1.CacheStatus{},
+				Spec:   v1beta1.CacheSpec{},
+				Status: v1beta1.CacheStatus{},
 			}
-			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
 			assert.NotNil(t, adapter)
 
 			res, err := adapter.CheckCacheExpiry(ctx)
@@ -128,27 +163,49 @@ func TestCacheCheckCacheExpiry(t *testing.T) {
 			assert.Equal(t, false, res.RequeueRequest)
 			assert.Equal(t, false, res.CancelRequest)
 		})
-	})
+		t.Run("cache not accessed within the ttl", func(t *testing.T) {
+			testCache := &v1beta1.Cache{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cache",
+					Namespace:         "test-ns",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-3 * time.Hour)),
+				},
+				Spec: v1beta1.CacheSpec{
+					TTLAfterLastAccess: &metav1.Duration{Duration: time.Hour},
+				},
+				Status: v1beta1.CacheStatus{
+					LastAccessTime: &metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+				},
+			}
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+			mockClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(ctx, testCache).Return(nil)
+			mockClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
 
-	t.Run("negative cases", func(t *testing.T) {
-		t.Run("invalid expire time", func(t *testing.T) {
-			testCache := &v1alpha1.Cache{
+			res, err := adapter.CheckCacheExpiry(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, true, res.CancelRequest)
+			assert.True(t, ctrlutils.TimeEqual(&metav1.Time{Time: testCache.Status.LastAccessTime.Add(time.Hour)}, testCache.Status.ExpireTime))
+		})
+	})
+	t.Run("sad path", func(t *testing.T) {
+		t.Run("publishing the expire time failed", func(t *testing.T) {
+			testCache := &v1beta1.Cache{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-cache",
 					Namespace: "test-ns",
 				},
-				Spec: v1alpha1.CacheSpec{
-					ExpireTime: "invalid-time",
+				Spec: v1beta1.CacheSpec{
+					ExpireTime: &metav1.Time{Time: time.Now().Add(1 * time.Hour)},
 				},
-				Status: v1alpha1.CacheStatus{},
 			}
-			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
-			assert.NotNil(t, adapter)
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+			mockClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(ctx, testCache).Return(assert.AnError)
 
 			res, err := adapter.CheckCacheExpiry(ctx)
-			assert.Nil(t, err)
-			assert.Equal(t, false, res.RequeueRequest)
-			assert.Equal(t, false, res.CancelRequest)
+			assert.ErrorIs(t, err, assert.AnError)
+			assert.Equal(t, true, res.RequeueRequest)
 		})
 	})
 }
@@ -176,19 +233,19 @@ func TestCacheEnsureCacheInitialized(t *testing.T) {
 	testCacheKey := cacheHelper.NewCacheKeyFromApplications(testApps)
 
 	t.Run("happy path", func(t *testing.T) {
-		testCache := &v1alpha1.Cache{
+		testCache := &v1beta1.Cache{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "test-cache",
 				Namespace: "test-ns",
 			},
-			Spec: v1alpha1.CacheSpec{
-				OperationTemplate: v1alpha1.OperationSpec{
+			Spec: v1beta1.CacheSpec{
+				OperationTemplate: v1beta1.OperationSpec{
 					Applications: testApps,
 				},
 			},
-			Status: v1alpha1.CacheStatus{},
+			Status: v1beta1.CacheStatus{},
 		}
-		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
 		assert.NotNil(t, adapter)
 		mockClient.EXPECT().Status().Return(mockStatusWriter)
 		mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
@@ -220,28 +277,146 @@ func TestCacheCalculateKeepAliveCount(t *testing.T) {
 	mockStatusWriter = mockpkg.NewMockStatusWriter(mockStatusWriterCtrl)
 	testApps := getTestApps()
 
-	t.Run("happy path", func(t *testing.T) {
-		testCache := &v1alpha1.Cache{
+	now := time.Now()
+	recentBucket := v1beta1.CacheDemandBucket{Start: metav1.NewTime(now.Truncate(ctrlutils.DemandBucketSize)), Acquisitions: 2, Misses: 1}
+	staleBucket := v1beta1.CacheDemandBucket{Start: metav1.NewTime(now.Add(-2 * ctrlutils.DemandWindow)), Acquisitions: 10, Misses: 10}
+
+	tests := []struct {
+		name          string
+		spec          v1beta1.CacheSpec
+		buckets       []v1beta1.CacheDemandBucket
+		expectedCount int32
+	}{
+		{
+			name:          "no demand",
+			expectedCount: 0,
+		},
+		{
+			name:          "demand within the window",
+			buckets:       []v1beta1.CacheDemandBucket{staleBucket, recentBucket},
+			expectedCount: 3,
+		},
+		{
+			name:          "demand below the lower bound",
+			spec:          v1beta1.CacheSpec{MinKeepAliveCount: ptr.Of(int32(4))},
+			buckets:       []v1beta1.CacheDemandBucket{recentBucket},
+			expectedCount: 4,
+		},
+		{
+			name:          "demand above the upper bound",
+			spec:          v1beta1.CacheSpec{MaxKeepAliveCount: ptr.Of(int32(2))},
+			buckets:       []v1beta1.CacheDemandBucket{recentBucket},
+			expectedCount: 2,
+		},
+		{
+			name: "demand above the default upper bound",
+			buckets: []v1beta1.CacheDemandBucket{
+				{Start: recentBucket.Start, Acquisitions: 30, Misses: 20},
+			},
+			expectedCount: ctrlutils.DefaultMaxKeepAliveCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCache := &v1beta1.Cache{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cache",
+					Namespace: "test-ns",
+				},
+				Spec: tt.spec,
+				Status: v1beta1.CacheStatus{
+					Demand: v1beta1.CacheDemand{Buckets: tt.buckets},
+				},
+			}
+			testCache.Spec.OperationTemplate = v1beta1.OperationSpec{Applications: testApps}
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+			assert.NotNil(t, adapter)
+			mockClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+			res, err := adapter.CalculateKeepAliveCount(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, false, res.RequeueRequest)
+			assert.Equal(t, tt.expectedCount, testCache.Status.KeepAliveCount)
+			assert.NotContains(t, testCache.Status.Demand.Buckets, staleBucket)
+		})
+	}
+}
+
+func TestCacheCalculateKeepAliveCountWithAdvisor(t *testing.T) {
+	ctx := context.Background()
+	testlogger := log.FromContext(ctx)
+	scheme := runtime.NewScheme()
+	mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+	mockRecorder := mockpkg.NewMockEventRecorder(gomock.NewController(t))
+	mockStatusWriter := mockpkg.NewMockStatusWriter(gomock.NewController(t))
+	mockAdvisor := advisormocks.NewMockKeepAliveAdvisor(gomock.NewController(t))
+	mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+	mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil).AnyTimes()
+
+	newTestCache := func() *v1beta1.Cache {
+		return &v1beta1.Cache{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "test-cache",
 				Namespace: "test-ns",
 			},
-			Spec: v1alpha1.CacheSpec{
-				OperationTemplate: v1alpha1.OperationSpec{
-					Applications: testApps,
+			Spec: v1beta1.CacheSpec{
+				OperationTemplate: v1beta1.OperationSpec{Applications: getTestApps()},
+				MaxKeepAliveCount: ptr.Of(int32(10)),
+			},
+			Status: v1beta1.CacheStatus{
+				CacheKey:        "test-key",
+				KeepAliveCount:  1,
+				AvailableCaches: []string{"op1"},
+				Demand: v1beta1.CacheDemand{
+					Buckets: []v1beta1.CacheDemandBucket{{Start: metav1.Now(), Acquisitions: 2, Misses: 1}},
 				},
 			},
-			Status: v1alpha1.CacheStatus{},
 		}
-		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
-		assert.NotNil(t, adapter)
-		mockClient.EXPECT().Status().Return(mockStatusWriter)
-		mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	}
+
+	t.Run("happy path: advised keepAliveCount", func(t *testing.T) {
+		testCache := newTestCache()
+		mockAdvisor.EXPECT().KeepAliveCount(ctx, advisor.Request{
+			Namespace:           "test-ns",
+			Name:                "test-cache",
+			CacheKey:            "test-key",
+			Acquisitions:        2,
+			Misses:              1,
+			WindowSeconds:       int64(ctrlutils.DemandWindow.Seconds()),
+			KeepAliveCount:      1,
+			AvailableCount:      1,
+			LocalKeepAliveCount: 3,
+			MinKeepAliveCount:   ctrlutils.DefaultMinKeepAliveCount,
+			MaxKeepAliveCount:   10,
+		}).Return(int32(8), nil)
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, mockAdvisor)
 
 		res, err := adapter.CalculateKeepAliveCount(ctx)
 		assert.Nil(t, err)
 		assert.Equal(t, false, res.RequeueRequest)
-		assert.Equal(t, testCache.Status.KeepAliveCount, int32(5))
+		assert.Equal(t, int32(8), testCache.Status.KeepAliveCount)
+	})
+
+	t.Run("happy path: advised keepAliveCount is bounded", func(t *testing.T) {
+		testCache := newTestCache()
+		mockAdvisor.EXPECT().KeepAliveCount(ctx, gomock.Any()).Return(int32(50), nil)
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, mockAdvisor)
+
+		_, err := adapter.CalculateKeepAliveCount(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(10), testCache.Status.KeepAliveCount)
+	})
+
+	t.Run("sad path: fall back to local keepAliveCount", func(t *testing.T) {
+		testCache := newTestCache()
+		mockAdvisor.EXPECT().KeepAliveCount(ctx, gomock.Any()).Return(int32(0), assert.AnError)
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, mockAdvisor)
+
+		res, err := adapter.CalculateKeepAliveCount(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, false, res.RequeueRequest)
+		assert.Equal(t, int32(3), testCache.Status.KeepAliveCount)
 	})
 }
 
@@ -267,8 +442,8 @@ func TestCacheAdjustCache(t *testing.T) {
 	testApps := getTestApps()
 	testCacheKey := cacheHelper.NewCacheKeyFromApplications(testApps)
 
-	newOperation := &v1alpha1.Operation{
-		Spec: v1alpha1.OperationSpec{
+	newOperation := &v1beta1.Operation{
+		Spec: v1beta1.OperationSpec{
 			Applications: testApps,
 		},
 		ObjectMeta: metav1.ObjectMeta{
@@ -276,13 +451,13 @@ func TestCacheAdjustCache(t *testing.T) {
 			Namespace: "test-ns",
 			Labels:    map[string]string{ctrlutils.LabelNameCacheKey: testCacheKey},
 		},
-		Status: v1alpha1.OperationStatus{
-			Phase: v1alpha1.OperationPhaseEmpty,
+		Status: v1beta1.OperationStatus{
+			Phase: v1beta1.OperationPhaseEmpty,
 		},
 	}
 
-	availableOperation := &v1alpha1.Operation{
-		Spec: v1alpha1.OperationSpec{
+	availableOperation := &v1beta1.Operation{
+		Spec: v1beta1.OperationSpec{
 			Applications: testApps,
 		},
 		ObjectMeta: metav1.ObjectMeta{
@@ -290,8 +465,8 @@ func TestCacheAdjustCache(t *testing.T) {
 			Namespace: "test-ns",
 			Labels:    map[string]string{ctrlutils.LabelNameCacheKey: testCacheKey},
 		},
-		Status: v1alpha1.OperationStatus{
-			Phase: v1alpha1.OperationPhaseReconciled,
+		Status: v1beta1.OperationStatus{
+			Phase: v1beta1.OperationPhaseReconciled,
 		},
 	}
 	// TODO assert operation status
@@ -301,27 +476,27 @@ func TestCacheAdjustCache(t *testing.T) {
 
 	t.Run("cache balance = 0", func(t *testing.T) {
 		t.Run("happy path", func(t *testing.T) {
-			resOperations := v1alpha1.OperationList{Items: []v1alpha1.Operation{
+			resOperations := v1beta1.OperationList{Items: []v1beta1.Operation{
 				*newOperation.DeepCopy(),
 				*availableOperation.DeepCopy(),
 				*availableOperation.DeepCopy(),
 			}}
-			testCache := &v1alpha1.Cache{
+			testCache := &v1beta1.Cache{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-cache",
 					Namespace: "test-ns",
 				},
-				Spec: v1alpha1.CacheSpec{
-					OperationTemplate: v1alpha1.OperationSpec{
+				Spec: v1beta1.CacheSpec{
+					OperationTemplate: v1beta1.OperationSpec{
 						Applications: testApps,
 					},
 				},
-				Status: v1alpha1.CacheStatus{
+				Status: v1beta1.CacheStatus{
 					CacheKey:       testCacheKey,
 					KeepAliveCount: 2,
 				},
 			}
-			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
 			assert.NotNil(t, adapter)
 			mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
 			mockClient.EXPECT().Status().Return(mockStatusWriter)
@@ -336,30 +511,30 @@ func TestCacheAdjustCache(t *testing.T) {
 
 	t.Run("cache balance > 0", func(t *testing.T) {
 		t.Run("happy path", func(t *testing.T) {
-			resOperationItems := []v1alpha1.Operation{
+			resOperationItems := []v1beta1.Operation{
 				*newOperation.DeepCopy(),
 				*availableOperation.DeepCopy(),
 				*availableOperation.DeepCopy(),
 				*availableOperation.DeepCopy(),
 				*availableOperation.DeepCopy(),
 			}
-			resOperations := v1alpha1.OperationList{Items: resOperationItems}
-			testCache := &v1alpha1.Cache{
+			resOperations := v1beta1.OperationList{Items: resOperationItems}
+			testCache := &v1beta1.Cache{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-cache",
 					Namespace: "test-ns",
 				},
-				Spec: v1alpha1.CacheSpec{
-					OperationTemplate: v1alpha1.OperationSpec{
+				Spec: v1beta1.CacheSpec{
+					OperationTemplate: v1beta1.OperationSpec{
 						Applications: testApps,
 					},
 				},
-				Status: v1alpha1.CacheStatus{
+				Status: v1beta1.CacheStatus{
 					CacheKey:       testCacheKey,
 					KeepAliveCount: 2,
 				},
 			}
-			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference)
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
 			assert.NotNil(t, adapter)
 			mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
 			mockClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil).Times(3)
@@ -375,28 +550,28 @@ func TestCacheAdjustCache(t *testing.T) {
 
 	t.Run("cache balance < 0", func(t *testing.T) {
 		t.Run("happy path", func(t *testing.T) {
-			resOperations := v1alpha1.OperationList{Items: []v1alpha1.Operation{
+			resOperations := v1beta1.OperationList{Items: []v1beta1.Operation{
 				*newOperation.DeepCopy(),
 				*availableOperation.DeepCopy(),
 			}}
-			testCache := &v1alpha1.Cache{
+			testCache := &v1beta1.Cache{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-cache",
 					Namespace: "test-ns",
 				},
-				Spec: v1alpha1.CacheSpec{
-					OperationTemplate: v1alpha1.OperationSpec{
+				Spec: v1beta1.CacheSpec{
+					OperationTemplate: v1beta1.OperationSpec{
 						Applications: testApps,
 					},
 				},
-				Status: v1alpha1.CacheStatus{
+				Status: v1beta1.CacheStatus{
 					CacheKey:       testCacheKey,
 					KeepAliveCount: 3,
 				},
 			}
 			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error {
 				return nil
-			})
+			}, nil)
 			assert.NotNil(t, adapter)
 			mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
 			mockClient.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
@@ -409,4 +584,422 @@ func TestCacheAdjustCache(t *testing.T) {
 			assert.Equal(t, testCache.Status.AvailableCaches, []string{"test-operation-available"})
 		})
 	})
+
+	t.Run("on-demand strategy", func(t *testing.T) {
+		t.Run("happy path", func(t *testing.T) {
+			resOperations := v1beta1.OperationList{Items: []v1beta1.Operation{
+				*newOperation.DeepCopy(),
+				*availableOperation.DeepCopy(),
+			}}
+			testCache := &v1beta1.Cache{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cache",
+					Namespace: "test-ns",
+				},
+				Spec: v1beta1.CacheSpec{
+					OperationTemplate: v1beta1.OperationSpec{
+						Applications: testApps,
+					},
+					Strategy: v1beta1.CacheStrategyOnDemand,
+				},
+				Status: v1beta1.CacheStatus{
+					CacheKey:       testCacheKey,
+					KeepAliveCount: 3,
+				},
+			}
+			adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+			assert.NotNil(t, adapter)
+			mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
+			mockClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil).Times(2)
+			mockClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+			res, err := adapter.AdjustCache(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, false, res.RequeueRequest)
+			assert.Equal(t, int32(0), testCache.Status.PoolSize)
+			assert.True(t, meta.IsStatusConditionTrue(testCache.Status.Conditions, v1beta1.CacheConditionStrategyAccepted))
+		})
+	})
+
+	t.Run("adaptive strategy shrinks once per idle period", func(t *testing.T) {
+		idleOps := []v1beta1.Operation{}
+		for _, name := range []string{"test-operation-1", "test-operation-2", "test-operation-3"} {
+			op := availableOperation.DeepCopy()
+			op.Name = name
+			idleOps = append(idleOps, *op)
+		}
+		testCache := &v1beta1.Cache{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-cache",
+				Namespace:         "test-ns",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * ctrlutils.AdaptiveIdlePeriod)),
+			},
+			Spec: v1beta1.CacheSpec{
+				OperationTemplate: v1beta1.OperationSpec{
+					Applications: testApps,
+				},
+				Strategy: v1beta1.CacheStrategyAdaptive,
+			},
+			Status: v1beta1.CacheStatus{
+				CacheKey:        testCacheKey,
+				KeepAliveCount:  5,
+				PoolSize:        3,
+				AvailableCaches: []string{"test-operation-1", "test-operation-2", "test-operation-3"},
+			},
+		}
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).Times(4)
+
+		// the idle pool shrinks by one
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, v1beta1.OperationList{Items: idleOps}).Return(nil)
+		mockClient.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
+		mockStatusWriter.EXPECT().Update(ctx, testCache).Return(nil)
+		_, err := adapter.AdjustCache(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), testCache.Status.PoolSize)
+		assert.NotNil(t, testCache.Status.PoolResizeTime)
+
+		// the following reconciles within the idle period keep the size, although the operations are still old
+		for range 3 {
+			mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, v1beta1.OperationList{Items: idleOps[:2]}).Return(nil)
+			mockStatusWriter.EXPECT().Update(ctx, testCache).Return(nil)
+			_, err := adapter.AdjustCache(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, int32(2), testCache.Status.PoolSize)
+		}
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		testCache := &v1beta1.Cache{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cache",
+				Namespace: "test-ns",
+			},
+			Spec: v1beta1.CacheSpec{
+				OperationTemplate: v1beta1.OperationSpec{
+					Applications: testApps,
+				},
+				Strategy: "unknown",
+			},
+			Status: v1beta1.CacheStatus{
+				CacheKey:       testCacheKey,
+				KeepAliveCount: 3,
+			},
+		}
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+		assert.NotNil(t, adapter)
+		mockRecorder.EXPECT().Event(testCache, "Warning", "UnknownStrategy", gomock.Any())
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		res, err := adapter.AdjustCache(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, true, res.CancelRequest)
+		condition := meta.FindStatusCondition(testCache.Status.Conditions, v1beta1.CacheConditionStrategyAccepted)
+		assert.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, v1beta1.CacheConditionReasonUnknownStrategy, condition.Reason)
+	})
+
+	t.Run("stuck operations are recycled", func(t *testing.T) {
+		stuckOperation := newOperation.DeepCopy()
+		stuckOperation.Name = "test-operation-stuck"
+		stuckOperation.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		stuckOperation.Status.Phase = v1beta1.OperationPhaseReconciling
+		resOperations := v1beta1.OperationList{Items: []v1beta1.Operation{
+			*stuckOperation,
+			*availableOperation.DeepCopy(),
+		}}
+		testCache := &v1beta1.Cache{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cache",
+				Namespace: "test-ns",
+			},
+			Spec: v1beta1.CacheSpec{
+				OperationTemplate: v1beta1.OperationSpec{
+					Applications: testApps,
+				},
+				ProvisionTimeout: &metav1.Duration{Duration: time.Hour},
+			},
+			Status: v1beta1.CacheStatus{
+				CacheKey:       testCacheKey,
+				KeepAliveCount: 2,
+			},
+		}
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error {
+			return nil
+		}, nil)
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
+		mockClient.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, op *v1beta1.Operation, opts ...any) error {
+			assert.Equal(t, "test-operation-stuck", op.Name)
+			return nil
+		})
+		mockRecorder.EXPECT().Event(testCache, "Warning", "OperationsRecycled", gomock.Any())
+		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		res, err := adapter.AdjustCache(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, false, res.RequeueRequest)
+		assert.Equal(t, int32(1), testCache.Status.RecycledOperations)
+		assert.Equal(t, []string{"test-operation-available"}, testCache.Status.AvailableCaches)
+	})
+
+	newReleasedOperation := func(name string) v1beta1.Operation {
+		op := availableOperation.DeepCopy()
+		op.Name = name
+		op.Annotations = map[string]string{v1beta1.OperationReleasedAnnotationKey: time.Now().Add(-time.Minute).Format(time.RFC3339)}
+		return *op
+	}
+	expectResetJobGet := func(name string, job *batchv1.Job) {
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: name, Namespace: "test-ns"}, gomock.AssignableToTypeOf(&batchv1.Job{}), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key types.NamespacedName, obj *batchv1.Job, opts ...any) error {
+				if job == nil {
+					return apierrors.NewNotFound(batchv1.Resource("jobs"), name)
+				}
+				*obj = *job
+				return nil
+			})
+	}
+	newRecyclingCache := func() *v1beta1.Cache {
+		return &v1beta1.Cache{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cache",
+				Namespace: "test-ns",
+			},
+			Spec: v1beta1.CacheSpec{
+				OperationTemplate: v1beta1.OperationSpec{
+					Applications: testApps,
+				},
+				Recycle: &v1beta1.CacheRecycle{Reset: newTestJobSpec()},
+			},
+			Status: v1beta1.CacheStatus{
+				CacheKey:       testCacheKey,
+				KeepAliveCount: 2,
+			},
+		}
+	}
+
+	t.Run("released operations are reset", func(t *testing.T) {
+		resOperations := v1beta1.OperationList{Items: []v1beta1.Operation{
+			*availableOperation.DeepCopy(),
+			newReleasedOperation("test-operation-resetting"),
+			newReleasedOperation("test-operation-reset"),
+			newReleasedOperation("test-operation-reset-failed"),
+		}}
+		testCache := newRecyclingCache()
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error {
+			return nil
+		}, nil)
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
+		// the reset job of the first released operation is created
+		expectResetJobGet("reset-test-operation-resetting-1", nil)
+		mockClient.EXPECT().Create(ctx, gomock.Cond(func(job *batchv1.Job) bool {
+			return job.Name == "reset-test-operation-resetting-1"
+		})).Return(nil)
+		// the second one is reset and returned to the pool
+		expectResetJobGet("reset-test-operation-reset-1", &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+			Status:     batchv1.JobStatus{Succeeded: 1},
+		})
+		mockClient.EXPECT().Patch(ctx, gomock.Cond(func(op *v1beta1.Operation) bool {
+			_, released := op.Annotations[v1beta1.OperationReleasedAnnotationKey]
+			return op.Name == "test-operation-reset" && !released && op.Status.ReuseCount == 0
+		}), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Status().Return(mockStatusWriter).Times(2)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.Cond(func(op *v1beta1.Operation) bool {
+			return op.Name == "test-operation-reset" && op.Status.ReuseCount == 1
+		})).Return(nil)
+		mockRecorder.EXPECT().Event(testCache, "Normal", "OperationReset", gomock.Any())
+		// the third one failed its reset and is deleted
+		expectResetJobGet("reset-test-operation-reset-failed-1", &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
+			},
+		})
+		mockClient.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, op *v1beta1.Operation, opts ...any) error {
+			assert.Equal(t, "test-operation-reset-failed", op.Name)
+			return nil
+		})
+		mockRecorder.EXPECT().Event(testCache, "Warning", "ResetFailed", gomock.Any())
+		mockStatusWriter.EXPECT().Update(ctx, testCache).Return(nil)
+
+		res, err := adapter.AdjustCache(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, false, res.RequeueRequest)
+		// the operation being reset is not available yet
+		assert.Equal(t, []string{"test-operation-available", "test-operation-reset"}, testCache.Status.AvailableCaches)
+	})
+
+	t.Run("a reset operation failing to return to the pool is not counted", func(t *testing.T) {
+		resOperations := v1beta1.OperationList{Items: []v1beta1.Operation{newReleasedOperation("test-operation-reset")}}
+		testCache := newRecyclingCache()
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error {
+			return nil
+		}, nil)
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
+		expectResetJobGet("reset-test-operation-reset-1", &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+			Status:     batchv1.JobStatus{Succeeded: 1},
+		})
+		mockClient.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).Return(assert.AnError)
+
+		_, err := adapter.AdjustCache(ctx)
+		assert.ErrorIs(t, err, assert.AnError)
+		// the next reconcile finds the same reset job and completes the reset again
+		assert.Equal(t, "reset-test-operation-reset-1", ctrlutils.GetResetJobName(&resOperations.Items[0]))
+	})
+
+	t.Run("the job of a previous reset is deleted before resetting again", func(t *testing.T) {
+		resOperations := v1beta1.OperationList{Items: []v1beta1.Operation{newReleasedOperation("test-operation-reset")}}
+		testCache := newRecyclingCache()
+		testCache.Status.KeepAliveCount = 1
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, func(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...controllerutil.OwnerReferenceOption) error {
+			return nil
+		}, nil)
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, resOperations).Return(nil)
+		expectResetJobGet("reset-test-operation-reset-1", &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "reset-test-operation-reset-1", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+			Status:     batchv1.JobStatus{Succeeded: 1},
+		})
+		mockClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&batchv1.Job{}), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, testCache).Return(nil)
+
+		_, err := adapter.AdjustCache(ctx)
+		assert.NoError(t, err)
+		// the operation is not available until it is reset again
+		assert.Empty(t, testCache.Status.AvailableCaches)
+	})
+}
+
+func TestCacheEnsureCacheKeyMigrated(t *testing.T) {
+	ctx := context.Background()
+	testlogger := log.FromContext(ctx)
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1beta1.AddToScheme(scheme))
+	legacyKey := "0123456789abcdef"
+	newKey := cacheHelper.NewCacheKeyFromApplications(getTestApps())
+
+	newTestCache := func(name, key string) *v1beta1.Cache {
+		return &v1beta1.Cache{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: "old-cache-uid"},
+			Spec: v1beta1.CacheSpec{
+				OperationTemplate: v1beta1.OperationSpec{Applications: getTestApps()},
+			},
+			Status: v1beta1.CacheStatus{CacheKey: key, KeepAliveCount: 2},
+		}
+	}
+	newOwnedOp := func() v1beta1.Operation {
+		return v1beta1.Operation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cached-op",
+				Namespace: "default",
+				Labels:    map[string]string{ctrlutils.LabelNameCacheKey: legacyKey},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: v1beta1.GroupVersion.String(), Kind: "Cache", Name: "cache-" + legacyKey,
+					UID: "old-cache-uid", Controller: ptr.Of(true),
+				}},
+			},
+		}
+	}
+
+	t.Run("current key", func(t *testing.T) {
+		mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+		mockRecorder := mockpkg.NewMockEventRecorder(gomock.NewController(t))
+		adapter := NewCacheHandler(ctx, newTestCache("cache-"+newKey, newKey), testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+		res, err := adapter.EnsureCacheKeyMigrated(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.CancelRequest)
+	})
+
+	t.Run("cache named after legacy key hands over operations", func(t *testing.T) {
+		mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+		mockStatusWriter := mockpkg.NewMockStatusWriter(gomock.NewController(t))
+		mockRecorder := mockpkg.NewMockEventRecorder(gomock.NewController(t))
+		testCache := newTestCache("cache-"+legacyKey, legacyKey)
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, list *v1beta1.OperationList, opts ...any) error {
+				list.Items = []v1beta1.Operation{newOwnedOp()}
+				return nil
+			})
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(v1beta1.GroupVersion.WithResource("caches").GroupResource(), "cache-"+newKey))
+		mockClient.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, obj *v1beta1.Cache, opts ...any) error {
+			assert.Equal(t, "cache-"+newKey, obj.Name)
+			obj.UID = "new-cache-uid"
+			return nil
+		})
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, obj *v1beta1.Cache, opts ...any) error {
+			assert.Equal(t, newKey, obj.Status.CacheKey)
+			assert.Equal(t, int32(2), obj.Status.KeepAliveCount)
+			return nil
+		})
+		mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, obj *v1beta1.Operation, patch any, opts ...any) error {
+			assert.Equal(t, cacheHelper.CacheKeyLabelValue(newKey), obj.Labels[ctrlutils.LabelNameCacheKey])
+			assert.Len(t, obj.OwnerReferences, 1)
+			assert.Equal(t, "cache-"+newKey, obj.OwnerReferences[0].Name)
+			return nil
+		})
+		mockRecorder.EXPECT().Event(testCache, "Normal", "CacheKeyMigrated", gomock.Any())
+		mockClient.EXPECT().Delete(ctx, testCache).Return(nil)
+
+		res, err := adapter.EnsureCacheKeyMigrated(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.CancelRequest)
+	})
+
+	t.Run("custom named cache is re-keyed in place", func(t *testing.T) {
+		mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+		mockStatusWriter := mockpkg.NewMockStatusWriter(gomock.NewController(t))
+		mockRecorder := mockpkg.NewMockEventRecorder(gomock.NewController(t))
+		testCache := newTestCache("my-cache", legacyKey)
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, list *v1beta1.OperationList, opts ...any) error {
+				list.Items = []v1beta1.Operation{newOwnedOp()}
+				return nil
+			})
+		mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, obj *v1beta1.Operation, patch any, opts ...any) error {
+			assert.Equal(t, cacheHelper.CacheKeyLabelValue(newKey), obj.Labels[ctrlutils.LabelNameCacheKey])
+			assert.Equal(t, types.UID("old-cache-uid"), obj.OwnerReferences[0].UID)
+			return nil
+		})
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		res, err := adapter.EnsureCacheKeyMigrated(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.CancelRequest)
+		assert.Equal(t, newKey, testCache.Status.CacheKey)
+	})
+
+	t.Run("operation acquired during hand over", func(t *testing.T) {
+		mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+		mockRecorder := mockpkg.NewMockEventRecorder(gomock.NewController(t))
+		testCache := newTestCache("cache-"+legacyKey, legacyKey)
+		adapter := NewCacheHandler(ctx, testCache, testlogger, mockClient, scheme, mockRecorder, ctrl.SetControllerReference, nil)
+
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, list *v1beta1.OperationList, opts ...any) error {
+				list.Items = []v1beta1.Operation{newOwnedOp()}
+				return nil
+			})
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key any, obj *v1beta1.Cache, opts ...any) error {
+			obj.Name = "cache-" + newKey
+			obj.UID = "new-cache-uid"
+			return nil
+		})
+		mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewConflict(v1beta1.GroupVersion.WithResource("operations").GroupResource(), "cached-op", nil))
+
+		res, err := adapter.EnsureCacheKeyMigrated(ctx)
+		assert.Error(t, err)
+		assert.True(t, res.RequeueRequest)
+	})
 }
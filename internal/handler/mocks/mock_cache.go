@@ -100,3 +100,18 @@ func (mr *MockCacheHandlerInterfaceMockRecorder) EnsureCacheInitialized(ctx any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureCacheInitialized", reflect.TypeOf((*MockCacheHandlerInterface)(nil).EnsureCacheInitialized), ctx)
 }
+
+// EnsureCacheKeyMigrated mocks base method.
+func (m *MockCacheHandlerInterface) EnsureCacheKeyMigrated(ctx context.Context) (reconciler.OperationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureCacheKeyMigrated", ctx)
+	ret0, _ := ret[0].(reconciler.OperationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureCacheKeyMigrated indicates an expected call of EnsureCacheKeyMigrated.
+func (mr *MockCacheHandlerInterfaceMockRecorder) EnsureCacheKeyMigrated(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureCacheKeyMigrated", reflect.TypeOf((*MockCacheHandlerInterface)(nil).EnsureCacheKeyMigrated), ctx)
+}
@@ -71,6 +71,21 @@ func (mr *MockRequirementHandlerInterfaceMockRecorder) EnsureCachedOperationAcqu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureCachedOperationAcquired", reflect.TypeOf((*MockRequirementHandlerInterface)(nil).EnsureCachedOperationAcquired), ctx)
 }
 
+// EnsureFinalizerDeleted mocks base method.
+func (m *MockRequirementHandlerInterface) EnsureFinalizerDeleted(ctx context.Context) (reconciler.OperationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureFinalizerDeleted", ctx)
+	ret0, _ := ret[0].(reconciler.OperationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureFinalizerDeleted indicates an expected call of EnsureFinalizerDeleted.
+func (mr *MockRequirementHandlerInterfaceMockRecorder) EnsureFinalizerDeleted(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureFinalizerDeleted", reflect.TypeOf((*MockRequirementHandlerInterface)(nil).EnsureFinalizerDeleted), ctx)
+}
+
 // EnsureInitialized mocks base method.
 func (m *MockRequirementHandlerInterface) EnsureInitialized(ctx context.Context) (reconciler.OperationResult, error) {
 	m.ctrl.T.Helper()
@@ -2,20 +2,24 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 	"github.com/go-logr/logr"
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/metrics"
 	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 )
 
@@ -31,7 +35,7 @@ type OperationHandlerInterface interface {
 }
 
 type OperationHandler struct {
-	operation *v1alpha1.Operation
+	operation *v1beta1.Operation
 	logger    logr.Logger
 	client    client.Client
 	recorder  record.EventRecorder
@@ -41,7 +45,7 @@ type OperationHandler struct {
 	cacheutils ctrlutils.CacheHelper
 }
 
-func NewOperationHandler(ctx context.Context, operation *v1alpha1.Operation, logger logr.Logger, client client.Client, recorder record.EventRecorder) OperationHandlerInterface {
+func NewOperationHandler(ctx context.Context, operation *v1beta1.Operation, logger logr.Logger, client client.Client, recorder record.EventRecorder) OperationHandlerInterface {
 	if operationHandler, ok := ctx.Value(OperationContextKey{}).(OperationHandlerInterface); ok {
 		return operationHandler
 	}
@@ -63,23 +67,37 @@ func (o *OperationHandler) phaseIn(phases ...string) bool {
 
 func (o *OperationHandler) EnsureNotExpired(ctx context.Context) (reconciler.OperationResult, error) {
 	o.logger.V(1).Info("Operation EnsureNotExpired")
-	if len(o.operation.Spec.ExpireAt) == 0 {
+	if o.phaseIn(v1beta1.OperationPhaseDeleted, v1beta1.OperationPhaseDeleting) {
 		return reconciler.ContinueProcessing()
 	}
-	if o.phaseIn(v1alpha1.OperationPhaseDeleted, v1alpha1.OperationPhaseDeleting) {
-		return reconciler.ContinueProcessing()
-	}
-	expireTime, err := time.Parse(time.RFC3339, o.operation.Spec.ExpireAt)
+	lastAccessTime, err := ctrlutils.LastAccessTime(o.operation)
 	if err != nil {
-		o.logger.Error(err, fmt.Sprintf("Failed to parse expire time: %s", o.operation.Spec.ExpireAt))
-		o.recorder.Event(o.operation, "Warning", "InvalidExpireTime", "Failed to parse expire time")
-		return reconciler.ContinueProcessing()
+		o.logger.Error(err, "ignoring the last access time")
+		o.recorder.Event(o.operation, "Warning", "InvalidLastAccessTime", err.Error())
+	}
+	spec := o.operation.Spec
+	expiry := ctrlutils.Expiry{ExpireAt: spec.ExpireAt, TTLAfterReady: spec.TTLAfterReady, TTLAfterLastAccess: spec.TTLAfterLastAccess}
+	expireAt := expiry.Deadline(o.operation.Status.ReadyTime, lastAccessTime)
+	now := time.Now()
+	changed := !ctrlutils.TimeEqual(expireAt, o.operation.Status.ExpireAt)
+	o.operation.Status.ExpireAt = expireAt
+	conditionChanged, expiringSoon := ctrlutils.SetExpiringSoonCondition(&o.operation.Status.Conditions, expireAt, now)
+	if changed || conditionChanged {
+		if err := o.client.Status().Update(ctx, o.operation); err != nil {
+			return reconciler.RequeueWithError(err)
+		}
 	}
-	if time.Now().Before(expireTime) {
+	if expireAt == nil {
 		return reconciler.ContinueProcessing()
 	}
+	if now.Before(expireAt.Time) {
+		if expiringSoon {
+			o.recorder.Event(o.operation, "Warning", "ExpiringSoon", fmt.Sprintf("Operation expires at %s, extend it to keep it", expireAt.UTC().Format(time.RFC3339)))
+		}
+		return reconciler.ContinueProcessingWithin(ctrlutils.ExpiryRequeueDelay(expireAt, now))
+	}
 	// Expired
-	o.logger.Info("deleting expired operation", "expireAt", o.operation.Spec.ExpireAt)
+	o.logger.Info("deleting expired operation", "expireAt", expireAt)
 	if err := o.client.Delete(ctx, o.operation, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
 		o.logger.Error(err, "Failed to delete expired operation")
 		o.recorder.Event(o.operation, "Warning", "DeleteFailed", "Failed to delete expired operation")
@@ -91,54 +109,92 @@ func (o *OperationHandler) EnsureNotExpired(ctx context.Context) (reconciler.Ope
 
 func (o *OperationHandler) EnsureAllAppsAreReady(ctx context.Context) (reconciler.OperationResult, error) {
 	o.logger.V(1).Info("Operation EnsureAllAppsAreReady")
-	if o.phaseIn(v1alpha1.OperationPhaseDeleted, v1alpha1.OperationPhaseDeleting) {
+	if o.phaseIn(v1beta1.OperationPhaseDeleted, v1beta1.OperationPhaseDeleting) {
 		return reconciler.ContinueProcessing()
 	}
-	if o.phaseIn(v1alpha1.OperationPhaseEmpty) {
+	if o.phaseIn(v1beta1.OperationPhaseFailed) {
+		if err := o.retryIfRequested(ctx); err != nil {
+			return reconciler.RequeueWithError(err)
+		}
+	}
+	if o.phaseIn(v1beta1.OperationPhaseEmpty) {
 		o.logger.V(1).Info("initializing operation status")
 		o.oputils.ClearConditions(o.operation)
 		o.operation.Status.OperationID = o.oputils.NewOperationId()
 	}
-	if o.phaseIn(v1alpha1.OperationPhaseReconciling) {
+	// reject the applications up front when their dependencies can never be satisfied
+	if err := ctrlutils.ValidateApplicationDependencies(o.operation.Spec.Applications); err != nil {
+		o.logger.Error(err, "invalid application dependencies")
+		o.recorder.Event(o.operation, "Warning", "InvalidDependencies", err.Error())
+		o.setDependenciesValidCondition(err)
+		return reconciler.RequeueOnErrorOrStop(o.client.Status().Update(ctx, o.operation))
+	}
+	o.setDependenciesValidCondition(nil)
+	if o.phaseIn(v1beta1.OperationPhaseReconciling, v1beta1.OperationPhaseFailed) {
 		err := o.reconcilingApplications(ctx)
+		var appsErr *appsFailedError
+		if errors.As(err, &appsErr) {
+			return o.failOperation(ctx, appsErr)
+		}
+		if o.phaseIn(v1beta1.OperationPhaseFailed) {
+			// the failed app deployments were retried
+			o.logger.Info("failed applications were retried, reconciling operation")
+			o.operation.Status.Phase = v1beta1.OperationPhaseReconciling
+			meta.RemoveStatusCondition(&o.operation.Status.Conditions, v1beta1.OperationConditionAppsFailed)
+			if err := o.client.Status().Update(ctx, o.operation); err != nil {
+				return reconciler.RequeueWithError(err)
+			}
+			return reconciler.Requeue()
+		}
 		if err != nil {
 			o.logger.Error(err, "reconciling applications failed")
 			o.recorder.Event(o.operation, "Warning", "ReconcileFailed", "Failed to reconcile deployments")
 			return reconciler.RequeueWithError(err)
 		}
 
-		o.operation.Status.Phase = v1alpha1.OperationPhaseReconciled
+		o.operation.Status.Phase = v1beta1.OperationPhaseReconciled
+		if o.operation.Status.ReadyTime == nil {
+			now := metav1.Now()
+			o.operation.Status.ReadyTime = &now
+		}
+		// a spec change sends a reconciled operation back to reconciling, only the initial provisioning is observed
+		if o.operation.Generation <= 1 {
+			metrics.ObserveOperationProvisioned(o.operation.CreationTimestamp.Time)
+		}
 		return reconciler.RequeueOnErrorOrStop(o.client.Status().Update(ctx, o.operation))
 	}
 
 	// check the diff between the expected and actual apps, set phase to reconciling and requeue if changes
 	expectedCacheKey := o.cacheutils.NewCacheKeyFromApplications(o.operation.Spec.Applications)
 	if o.operation.Status.CacheKey != expectedCacheKey {
+		// a key of an older version which still matches the applications is only re-keyed
+		if !o.cacheutils.CacheKeyMatches(o.operation.Status.CacheKey, o.operation.Spec.Applications) {
+			o.operation.Status.Phase = v1beta1.OperationPhaseReconciling
+		}
 		o.operation.Status.CacheKey = expectedCacheKey
-		o.operation.Status.Phase = v1alpha1.OperationPhaseReconciling
 	}
 	return reconciler.RequeueOnErrorOrContinue(o.client.Status().Update(ctx, o.operation))
 }
 
 func (o *OperationHandler) EnsureFinalizer(ctx context.Context) (reconciler.OperationResult, error) {
 	o.logger.V(1).Info("operation EnsureFinalizer")
-	if o.operation.ObjectMeta.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(o.operation, v1alpha1.OperationFinalizerName) {
-		controllerutil.AddFinalizer(o.operation, v1alpha1.OperationFinalizerName)
+	if o.operation.ObjectMeta.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(o.operation, v1beta1.OperationFinalizerName) {
+		controllerutil.AddFinalizer(o.operation, v1beta1.OperationFinalizerName)
 	}
 	return reconciler.RequeueOnErrorOrContinue(o.client.Update(ctx, o.operation))
 }
 
 func (o *OperationHandler) EnsureFinalizerRemoved(ctx context.Context) (reconciler.OperationResult, error) {
 	o.logger.V(1).Info("operation EnsureFinalizerDeleted")
-	if !o.operation.ObjectMeta.DeletionTimestamp.IsZero() && controllerutil.ContainsFinalizer(o.operation, v1alpha1.OperationFinalizerName) {
-		if o.phaseIn(v1alpha1.OperationPhaseDeleted) {
+	if !o.operation.ObjectMeta.DeletionTimestamp.IsZero() && controllerutil.ContainsFinalizer(o.operation, v1beta1.OperationFinalizerName) {
+		if o.phaseIn(v1beta1.OperationPhaseDeleted) {
 			o.logger.V(1).Info("All app deleted removing finalizer")
-			controllerutil.RemoveFinalizer(o.operation, v1alpha1.OperationFinalizerName)
+			controllerutil.RemoveFinalizer(o.operation, v1beta1.OperationFinalizerName)
 			return reconciler.RequeueOnErrorOrContinue(o.client.Update(ctx, o.operation))
 		}
-		if !o.phaseIn(v1alpha1.OperationPhaseDeleting) {
+		if !o.phaseIn(v1beta1.OperationPhaseDeleting) {
 			o.logger.V(1).Info("App is not deleted yet, setting phase to deleting")
-			o.operation.Status.Phase = v1alpha1.OperationPhaseDeleting
+			o.operation.Status.Phase = v1beta1.OperationPhaseDeleting
 			return reconciler.RequeueOnErrorOrContinue(o.client.Status().Update(ctx, o.operation))
 		}
 	}
@@ -147,12 +203,117 @@ func (o *OperationHandler) EnsureFinalizerRemoved(ctx context.Context) (reconcil
 
 func (o *OperationHandler) EnsureAllAppsAreDeleted(ctx context.Context) (reconciler.OperationResult, error) {
 	o.logger.V(1).Info("Operation EnsureAllAppsAreDeleted")
-	if o.phaseIn(v1alpha1.OperationPhaseDeleting) {
-		// deleting logic here
-		o.operation.Status.Phase = v1alpha1.OperationPhaseDeleted
+	if !o.phaseIn(v1beta1.OperationPhaseDeleting) {
+		return reconciler.ContinueProcessing()
+	}
+	appDeployments, err := o.listCurrentAppDeployments(ctx)
+	if err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	if len(appDeployments) == 0 {
+		o.setAppsDeletedCondition(metav1.ConditionTrue, v1beta1.OperationConditionReasonTeardownCompleted, "All applications are torn down")
+		o.operation.Status.Phase = v1beta1.OperationPhaseDeleted
 		return reconciler.RequeueOnErrorOrStop(o.client.Status().Update(ctx, o.operation))
 	}
-	return reconciler.ContinueProcessing()
+
+	// the app deployments run their teardown jobs before they go away, an app is only deleted once
+	// everything depending on it is gone
+	for _, app := range o.oputils.AppDeploymentsToTeardown(appDeployments) {
+		if !app.DeletionTimestamp.IsZero() {
+			continue
+		}
+		o.logger.V(1).Info("tearing down app deployment", "appName", app.Name)
+		if err := o.client.Delete(ctx, &app, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			o.recorder.Event(o.operation, "Warning", "TeardownFailed", fmt.Sprintf("Failed to delete app deployment %s", app.Name))
+			return reconciler.RequeueWithError(fmt.Errorf("failed to delete app deployment %s: %w", app.Name, err))
+		}
+	}
+	remaining := lo.Map(appDeployments, func(app v1beta1.AppDeployment, _ int) string { return app.Name })
+	o.setAppsDeletedCondition(metav1.ConditionFalse, v1beta1.OperationConditionReasonTeardownInProgress,
+		fmt.Sprintf("Waiting for %d application(s) to be torn down: %s", len(remaining), strings.Join(remaining, ", ")))
+	if err := o.client.Status().Update(ctx, o.operation); err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	return reconciler.Requeue()
+}
+
+// appsFailedError is returned when app deployments of the operation failed, they are only retried on request
+type appsFailedError struct {
+	failures []string
+}
+
+func (e *appsFailedError) Error() string {
+	return strings.Join(e.failures, "\n")
+}
+
+// failOperation moves the operation to the failed phase with the failures of its app deployments
+func (o *OperationHandler) failOperation(ctx context.Context, appsErr *appsFailedError) (reconciler.OperationResult, error) {
+	if !o.phaseIn(v1beta1.OperationPhaseFailed) {
+		o.logger.Error(appsErr, "applications failed")
+		o.recorder.Event(o.operation, "Warning", "AppsFailed",
+			fmt.Sprintf("Applications failed, annotate with %s to retry", ctrlutils.AnnotationNameRetry))
+	}
+	o.operation.Status.Phase = v1beta1.OperationPhaseFailed
+	meta.SetStatusCondition(&o.operation.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.OperationConditionAppsFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1beta1.OperationConditionReasonProvisionFailed,
+		Message: appsErr.Error(),
+	})
+	return reconciler.RequeueOnErrorOrStop(o.client.Status().Update(ctx, o.operation))
+}
+
+// retryIfRequested passes the retry annotation of a failed operation on to its failed app deployments
+func (o *OperationHandler) retryIfRequested(ctx context.Context) error {
+	if _, ok := o.operation.Annotations[ctrlutils.AnnotationNameRetry]; !ok {
+		return nil
+	}
+	appDeploymentList := &v1beta1.AppDeploymentList{}
+	if err := o.client.List(ctx, appDeploymentList, client.InNamespace(o.operation.Namespace), client.MatchingFields{v1beta1.OperationOwnerKey: o.operation.Name}); err != nil {
+		return fmt.Errorf("failed to list appDeployments: %w", err)
+	}
+	for i := range appDeploymentList.Items {
+		app := &appDeploymentList.Items[i]
+		if app.Status.Phase != v1beta1.AppDeploymentPhaseFailed {
+			continue
+		}
+		original := app.DeepCopy()
+		if app.Annotations == nil {
+			app.Annotations = map[string]string{}
+		}
+		app.Annotations[ctrlutils.AnnotationNameRetry] = o.operation.Annotations[ctrlutils.AnnotationNameRetry]
+		if err := o.client.Patch(ctx, app, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to retry app deployment %s: %w", app.Name, err)
+		}
+	}
+	o.logger.Info("retrying failed applications")
+	o.recorder.Event(o.operation, "Normal", "Retrying", "Retrying failed applications")
+	delete(o.operation.Annotations, ctrlutils.AnnotationNameRetry)
+	return o.client.Update(ctx, o.operation)
+}
+
+func (o *OperationHandler) setDependenciesValidCondition(err error) {
+	condition := metav1.Condition{
+		Type:    v1beta1.OperationConditionDependenciesValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1beta1.OperationConditionReasonDependenciesValid,
+		Message: "Application dependencies form a valid DAG",
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1beta1.OperationConditionReasonInvalidDependency
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&o.operation.Status.Conditions, condition)
+}
+
+func (o *OperationHandler) setAppsDeletedCondition(status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&o.operation.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.OperationConditionAppsDeleted,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
 }
 
 func (o *OperationHandler) reconcilingApplications(ctx context.Context) error {
@@ -163,18 +324,18 @@ func (o *OperationHandler) reconcilingApplications(ctx context.Context) error {
 	}
 	logger.V(1).Info(fmt.Sprintf("current app deployments count %d", len(currentAppDeployments)))
 	for _, app := range currentAppDeployments {
-		logger.V(1).Info("current app deployment", "appName", app.Name, "opId", app.Spec.OpId, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
+		logger.V(1).Info("current app deployment", "appName", app.Name, "opId", app.Spec.OperationID, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
 	}
 
 	expectedAppDeployments := o.expectedAppDeployments()
 	logger.V(1).Info(fmt.Sprintf("expected app deployments count %d", len(expectedAppDeployments)))
 	for _, app := range expectedAppDeployments {
-		logger.V(1).Info("expected app deployment", "appName", app.Name, "opId", app.Spec.OpId, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
+		logger.V(1).Info("expected app deployment", "appName", app.Name, "opId", app.Spec.OperationID, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
 	}
 
-	added, removed, updated := o.oputils.DiffAppDeployments(expectedAppDeployments, currentAppDeployments, o.oputils.CompareProvisionJobs)
+	added, removed, updated := o.oputils.DiffAppDeployments(expectedAppDeployments, currentAppDeployments, o.oputils.CompareAppDeployments)
 	for _, app := range added {
-		logger.V(1).Info(fmt.Sprintf("app to be added %s", app.Name), "opId", app.Spec.OpId, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
+		logger.V(1).Info(fmt.Sprintf("app to be added %s", app.Name), "opId", app.Spec.OperationID, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
 		if err := ctrl.SetControllerReference(o.operation, &app, o.client.Scheme()); err != nil {
 			return fmt.Errorf("failed to set controller reference: %w", err)
 		}
@@ -184,58 +345,88 @@ func (o *OperationHandler) reconcilingApplications(ctx context.Context) error {
 	}
 
 	for _, app := range removed {
-		logger.V(1).Info(fmt.Sprintf("app to be removed %s", app.Name), "opId", app.Spec.OpId, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
+		logger.V(1).Info(fmt.Sprintf("app to be removed %s", app.Name), "opId", app.Spec.OperationID, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
 		if err := o.client.Delete(ctx, &app, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
 			return fmt.Errorf("failed to delete app deployment: %w", err)
 		}
 	}
 
 	for _, app := range updated {
-		logger.V(1).Info(fmt.Sprintf("app to be updated %s", app.Name), "appName", app.Name, "opId", app.Spec.OpId, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
+		logger.V(1).Info(fmt.Sprintf("app to be updated %s", app.Name), "appName", app.Name, "opId", app.Spec.OperationID, "provision", app.Spec.Provision, "teardown", app.Spec.Teardown, "dependencies", app.Spec.Dependencies)
 		if err := o.client.Update(ctx, &app); err != nil {
 			return fmt.Errorf("failed to update app deployment: %w", err)
 		}
 	}
 
-	// check if all expected app deployments are ready
-	for _, app := range expectedAppDeployments {
-		appdeployment := &v1alpha1.AppDeployment{}
+	// check if all expected app deployments are ready, a failed one fails the operation
+	outputs := make([]v1beta1.ApplicationOutputs, 0, len(expectedAppDeployments))
+	failures := []string{}
+	var notReadyErr error
+	for i, app := range expectedAppDeployments {
+		appdeployment := &v1beta1.AppDeployment{}
 		if err := o.client.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, appdeployment); err != nil {
 			return fmt.Errorf("failed to get app deployment: %w", err)
 		}
+		if appdeployment.Status.Phase == v1beta1.AppDeploymentPhaseFailed {
+			message := "provision failed"
+			if condition := meta.FindStatusCondition(appdeployment.Status.Conditions, v1beta1.AppDeploymentConditionProvisionFailed); condition != nil {
+				message = condition.Message
+			}
+			failures = append(failures, fmt.Sprintf("application %s: %s", o.operation.Spec.Applications[i].Name, message))
+			continue
+		}
 		// check if all dependencies are ready
-		if appdeployment.Status.Phase != v1alpha1.AppDeploymentPhaseReady {
-			return fmt.Errorf("app deployment is not ready: name %s, status, %s", app.Name, app.Status.Phase)
+		if appdeployment.Status.Phase != v1beta1.AppDeploymentPhaseReady {
+			if notReadyErr == nil {
+				notReadyErr = fmt.Errorf("app deployment is not ready: name %s, status, %s", app.Name, appdeployment.Status.Phase)
+			}
+			continue
 		}
+		// the expected app deployments are in the order of the applications
+		outputs = append(outputs, v1beta1.ApplicationOutputs{
+			Name:       o.operation.Spec.Applications[i].Name,
+			Outputs:    appdeployment.Status.Outputs,
+			SecretName: appdeployment.Status.OutputsSecretName,
+		})
+	}
+	if len(failures) > 0 {
+		return &appsFailedError{failures: failures}
+	}
+	if notReadyErr != nil {
+		return notReadyErr
 	}
+	o.operation.Status.Outputs = outputs
 
 	return nil
 }
 
-func (o *OperationHandler) expectedAppDeployments() []v1alpha1.AppDeployment {
-	return lo.Map(o.operation.Spec.Applications, func(app v1alpha1.ApplicationSpec, index int) v1alpha1.AppDeployment {
-		return v1alpha1.AppDeployment{
+func (o *OperationHandler) expectedAppDeployments() []v1beta1.AppDeployment {
+	return lo.Map(o.operation.Spec.Applications, func(app v1beta1.ApplicationSpec, index int) v1beta1.AppDeployment {
+		return v1beta1.AppDeployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      ctrlutils.OperationScopedAppDeployment(app.Name, o.operation.Status.OperationID),
 				Namespace: o.operation.Namespace,
 			},
-			Spec: v1alpha1.AppDeploymentSpec{
-				OpId:         o.operation.Status.OperationID,
-				Provision:    app.Provision,
-				Teardown:     app.Teardown,
-				Dependencies: app.Dependencies,
+			Spec: v1beta1.AppDeploymentSpec{
+				OperationID:            o.operation.Status.OperationID,
+				Provision:              app.Provision,
+				Teardown:               app.Teardown,
+				Dependencies:           app.Dependencies,
+				MaxRetries:             app.MaxRetries,
+				FailedJobsHistoryLimit: app.FailedJobsHistoryLimit,
+				TeardownPolicy:         app.TeardownPolicy,
 			},
 		}
 	})
 }
 
-func (o *OperationHandler) listCurrentAppDeployments(ctx context.Context) ([]v1alpha1.AppDeployment, error) {
-	appDeploymentList := &v1alpha1.AppDeploymentList{}
-	if err := o.client.List(ctx, appDeploymentList, client.MatchingFields{v1alpha1.OperationOwnerKey: o.operation.Name}); err != nil {
+func (o *OperationHandler) listCurrentAppDeployments(ctx context.Context) ([]v1beta1.AppDeployment, error) {
+	appDeploymentList := &v1beta1.AppDeploymentList{}
+	if err := o.client.List(ctx, appDeploymentList, client.InNamespace(o.operation.Namespace), client.MatchingFields{v1beta1.OperationOwnerKey: o.operation.Name}); err != nil {
 		return nil, fmt.Errorf("failed to list appDeployments: %w", err)
 	}
-	return lo.Map(appDeploymentList.Items, func(app v1alpha1.AppDeployment, index int) v1alpha1.AppDeployment {
-		return v1alpha1.AppDeployment{
+	return lo.Map(appDeploymentList.Items, func(app v1beta1.AppDeployment, index int) v1beta1.AppDeployment {
+		return v1beta1.AppDeployment{
 			ObjectMeta: app.ObjectMeta,
 			Spec:       app.Spec,
 		}
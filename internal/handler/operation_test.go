@@ -5,13 +5,18 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	mockpkg "github.com/Azure/operation-cache-controller/internal/utils/mocks"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -20,14 +25,14 @@ import (
 )
 
 var (
-	emptyOperation = &v1alpha1.Operation{}
-	validOperation = &v1alpha1.Operation{
+	emptyOperation = &v1beta1.Operation{}
+	validOperation = &v1beta1.Operation{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-operation",
 			Namespace: "default",
 		},
-		Spec: v1alpha1.OperationSpec{
-			Applications: []v1alpha1.ApplicationSpec{
+		Spec: v1beta1.OperationSpec{
+			Applications: []v1beta1.ApplicationSpec{
 				{
 					Name:      "test-app1",
 					Provision: newTestJobSpec(),
@@ -42,27 +47,27 @@ var (
 				},
 			},
 		},
-		Status: v1alpha1.OperationStatus{
+		Status: v1beta1.OperationStatus{
 			OperationID: "test-operation",
 		},
 	}
-	emptyAppDeploymentList = &v1alpha1.AppDeploymentList{}
+	emptyAppDeploymentList = &v1beta1.AppDeploymentList{}
 
-	validAppDeploymentList = &v1alpha1.AppDeploymentList{
-		Items: []v1alpha1.AppDeployment{
+	validAppDeploymentList = &v1beta1.AppDeploymentList{
+		Items: []v1beta1.AppDeployment{
 			{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-operation-test-app1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.AppDeploymentSpec{
-					OpId:         "test-operation",
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID:  "test-operation",
 					Provision:    newTestJobSpec(),
 					Teardown:     newTestJobSpec(),
 					Dependencies: []string{"test-app2"},
 				},
-				Status: v1alpha1.AppDeploymentStatus{
-					Phase: v1alpha1.AppDeploymentPhaseReady,
+				Status: v1beta1.AppDeploymentStatus{
+					Phase: v1beta1.AppDeploymentPhaseReady,
 				},
 			},
 			{
@@ -70,27 +75,27 @@ var (
 					Name:      "test-operation-test-app2",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.AppDeploymentSpec{
-					OpId:      "test-operation",
-					Provision: newTestJobSpec(),
-					Teardown:  newTestJobSpec(),
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID: "test-operation",
+					Provision:   newTestJobSpec(),
+					Teardown:    newTestJobSpec(),
 				},
-				Status: v1alpha1.AppDeploymentStatus{
-					Phase: v1alpha1.AppDeploymentPhaseReady,
+				Status: v1beta1.AppDeploymentStatus{
+					Phase: v1beta1.AppDeploymentPhaseReady,
 				},
 			},
 		},
 	}
 
-	changedValidAppDeploymentList = &v1alpha1.AppDeploymentList{
-		Items: []v1alpha1.AppDeployment{
+	changedValidAppDeploymentList = &v1beta1.AppDeploymentList{
+		Items: []v1beta1.AppDeployment{
 			{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-operation-test-app2",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.AppDeploymentSpec{
-					OpId: "test-operation",
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID: "test-operation",
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -112,8 +117,8 @@ var (
 					},
 					Teardown: newTestJobSpec(),
 				},
-				Status: v1alpha1.AppDeploymentStatus{
-					Phase: v1alpha1.AppDeploymentPhaseReady,
+				Status: v1beta1.AppDeploymentStatus{
+					Phase: v1beta1.AppDeploymentPhaseReady,
 				},
 			},
 			{
@@ -121,13 +126,13 @@ var (
 					Name:      "test-operation-test-app3",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.AppDeploymentSpec{
-					OpId:      "test-operation",
-					Provision: newTestJobSpec(),
-					Teardown:  newTestJobSpec(),
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID: "test-operation",
+					Provision:   newTestJobSpec(),
+					Teardown:    newTestJobSpec(),
 				},
-				Status: v1alpha1.AppDeploymentStatus{
-					Phase: v1alpha1.AppDeploymentPhaseReady,
+				Status: v1beta1.AppDeploymentStatus{
+					Phase: v1beta1.AppDeploymentPhaseReady,
 				},
 			},
 		},
@@ -156,10 +161,11 @@ func TestOperationHandler_EnsureNotExpired(t *testing.T) {
 	mockClient := mockpkg.NewMockClient(mockCtrl)
 	mockRecorderCtrl := gomock.NewController(t)
 	mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
+	mockStatusWriterCtrl := gomock.NewController(t)
+	mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusWriterCtrl)
 
 	t.Run("happy path: continue processing when expire is not set", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.Spec.ExpireAt = time.Now().Add(time.Hour).Format(time.RFC3339)
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureNotExpired(ctx)
 		assert.NoError(t, err)
@@ -167,32 +173,104 @@ func TestOperationHandler_EnsureNotExpired(t *testing.T) {
 	})
 	t.Run("happy path: continue processing when operation is in deleting phase", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.Spec.ExpireAt = time.Now().Add(time.Hour).Format(time.RFC3339)
-		operation.Status.Phase = v1alpha1.OperationPhaseDeleting
+		operation.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+		operation.Status.Phase = v1beta1.OperationPhaseDeleting
 
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureNotExpired(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
 
-		operation.Status.Phase = v1alpha1.OperationPhaseDeleted
+		operation.Status.Phase = v1beta1.OperationPhaseDeleted
 		adapter = NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 		res, err = adapter.EnsureNotExpired(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
 	})
-	t.Run("happy path: continue processing when expire time is in the future", func(t *testing.T) {
+	t.Run("happy path: publish the expire time when it is in the future", func(t *testing.T) {
+		operation := validOperation.DeepCopy()
+		operation.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, operation).Return(nil)
+
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		// checked again when it is expiring soon
+		assert.InDelta(t, time.Hour-config.DefaultExpiringSoonWindow, res.RequeueWithin, float64(time.Second))
+		assert.True(t, ctrlutils.TimeEqual(operation.Spec.ExpireAt, operation.Status.ExpireAt))
+		assert.Nil(t, meta.FindStatusCondition(operation.Status.Conditions, v1beta1.ConditionExpiringSoon))
+
+		// the published expire time is not updated again
+		res, err = adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+	})
+	t.Run("happy path: mark operation expiring soon", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.Spec.ExpireAt = time.Now().Add(time.Hour).Format(time.RFC3339)
+		operation.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		operation.Status.ExpireAt = operation.Spec.ExpireAt.DeepCopy()
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, operation).Return(nil)
+		mockRecorder.EXPECT().Event(operation, "Warning", "ExpiringSoon", gomock.Any())
+
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		// checked again when it expires
+		assert.InDelta(t, time.Minute, res.RequeueWithin, float64(time.Second))
+		assert.True(t, meta.IsStatusConditionTrue(operation.Status.Conditions, v1beta1.ConditionExpiringSoon))
+
+		// the event is only recorded once
+		_, err = adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+
+		// extending the operation clears the condition
+		operation.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, operation).Return(nil)
+		_, err = adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionFalse(operation.Status.Conditions, v1beta1.ConditionExpiringSoon))
+	})
+	t.Run("happy path: expire time follows the last access", func(t *testing.T) {
+		operation := validOperation.DeepCopy()
+		readyTime := time.Now().Add(-2 * time.Hour)
+		lastAccess := time.Now().Add(-30 * time.Minute)
+		operation.Annotations = map[string]string{ctrlutils.AnnotationNameLastAccess: lastAccess.Format(time.RFC3339)}
+		operation.Spec.TTLAfterLastAccess = &metav1.Duration{Duration: time.Hour}
+		operation.Status.ReadyTime = &metav1.Time{Time: readyTime}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, operation).Return(nil)
+
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		assert.True(t, ctrlutils.TimeEqual(&metav1.Time{Time: lastAccess.Add(time.Hour)}, operation.Status.ExpireAt))
+	})
+	t.Run("happy path: delete operation when the ttl after ready elapsed", func(t *testing.T) {
+		operation := validOperation.DeepCopy()
+		operation.Spec.TTLAfterReady = &metav1.Duration{Duration: time.Hour}
+		operation.Status.ReadyTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		operation.Status.ExpireAt = &metav1.Time{Time: operation.Status.ReadyTime.Add(time.Hour)}
+
+		mockClient.EXPECT().Delete(ctx, operation, gomock.Any()).Return(nil)
+
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureNotExpired(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
 	})
-	t.Run("Sad path: failed to parse expire time", func(t *testing.T) {
+	t.Run("happy path: invalid last access annotation is ignored", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.Spec.ExpireAt = "invalid-time"
-		mockRecorder.EXPECT().Event(operation, "Warning", "InvalidExpireTime", "Failed to parse expire time")
+		operation.Annotations = map[string]string{ctrlutils.AnnotationNameLastAccess: "yesterday"}
+
+		mockRecorder.EXPECT().Event(operation, "Warning", "InvalidLastAccessTime", gomock.Any())
 
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureNotExpired(ctx)
@@ -202,8 +280,10 @@ func TestOperationHandler_EnsureNotExpired(t *testing.T) {
 
 	t.Run("happy path: delete operation when expire time is in the past", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.Spec.ExpireAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+		operation.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
 
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, operation).Return(nil)
 		mockClient.EXPECT().Delete(ctx, operation, gomock.Any()).Return(nil)
 
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
@@ -211,9 +291,22 @@ func TestOperationHandler_EnsureNotExpired(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
 	})
+	t.Run("sad path: publishing the expire time failed", func(t *testing.T) {
+		operation := validOperation.DeepCopy()
+		operation.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, operation).Return(assert.AnError)
+
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+	})
 	t.Run("sad path: delete operation failed", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.Spec.ExpireAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+		operation.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		operation.Status.ExpireAt = operation.Spec.ExpireAt.DeepCopy()
 
 		mockClient.EXPECT().Delete(ctx, operation, gomock.Any()).Return(assert.AnError)
 
@@ -237,14 +330,14 @@ func TestOperationHandler_EnsureAllAppsAreReady(t *testing.T) {
 		mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
 
 		operation := validOperation.DeepCopy()
-		operation.Status.Phase = v1alpha1.OperationPhaseDeleting
+		operation.Status.Phase = v1beta1.OperationPhaseDeleting
 
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureAllAppsAreReady(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
 
-		operation.Status.Phase = v1alpha1.OperationPhaseDeleted
+		operation.Status.Phase = v1beta1.OperationPhaseDeleted
 		adapter = NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 		res, err = adapter.EnsureAllAppsAreReady(ctx)
 		assert.NoError(t, err)
@@ -267,7 +360,33 @@ func TestOperationHandler_EnsureAllAppsAreReady(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
 		assert.NotEmpty(t, operation.Status.CacheKey)
-		assert.Equal(t, operation.Status.Phase, v1alpha1.OperationPhaseReconciling)
+		assert.Equal(t, operation.Status.Phase, v1beta1.OperationPhaseReconciling)
+	})
+
+	t.Run("sad path: stop processing when application dependencies are invalid", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockStatusWriterCtrl := gomock.NewController(t)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusWriterCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockRecorderCtrl := gomock.NewController(t)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
+
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseReconciling
+		operation.Spec.Applications[1].Dependencies = []string{"test-app1"}
+
+		mockRecorder.EXPECT().Event(operation, "Warning", "InvalidDependencies", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureAllAppsAreReady(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, CancelRequest: true}, res)
+		assert.Equal(t, v1beta1.OperationPhaseReconciling, operation.Status.Phase)
+		condition := meta.FindStatusCondition(operation.Status.Conditions, v1beta1.OperationConditionDependenciesValid)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "test-app1 -> test-app2 -> test-app1")
 	})
 
 	t.Run("happy path: continue processing when operation is in reconciling phase", func(t *testing.T) {
@@ -277,18 +396,18 @@ func TestOperationHandler_EnsureAllAppsAreReady(t *testing.T) {
 		mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
 
 		operation := validOperation.DeepCopy()
-		operation.Status.Phase = v1alpha1.OperationPhaseReconciling
+		operation.Status.Phase = v1beta1.OperationPhaseReconciling
 
 		appList := emptyAppDeploymentList.DeepCopy()
-		mockClient.EXPECT().List(ctx, appList, gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1alpha1.AppDeploymentList, opts ...any) error {
+		mockClient.EXPECT().List(ctx, appList, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1beta1.AppDeploymentList, opts ...any) error {
 			*list = *changedValidAppDeploymentList
 			return nil
 		})
 		scheme := runtime.NewScheme()
-		utilruntime.Must(v1alpha1.AddToScheme(scheme))
+		utilruntime.Must(v1beta1.AddToScheme(scheme))
 		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opt ...any) error {
-			*obj.(*v1alpha1.AppDeployment) = v1alpha1.AppDeployment{}
+			*obj.(*v1beta1.AppDeployment) = v1beta1.AppDeployment{}
 			return nil
 		}).AnyTimes()
 		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(nil)
@@ -300,7 +419,7 @@ func TestOperationHandler_EnsureAllAppsAreReady(t *testing.T) {
 		res, err := adapter.EnsureAllAppsAreReady(ctx)
 		assert.ErrorContains(t, err, "app deployment is not ready")
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
-		assert.Equal(t, operation.Status.Phase, v1alpha1.OperationPhaseReconciling)
+		assert.Equal(t, operation.Status.Phase, v1beta1.OperationPhaseReconciling)
 
 	})
 
@@ -315,19 +434,23 @@ func TestOperationHandler_EnsureAllAppsAreReady(t *testing.T) {
 
 		// set operation to reconciling phase
 		operation := validOperation.DeepCopy()
-		operation.Status.Phase = v1alpha1.OperationPhaseReconciling
+		operation.Status.Phase = v1beta1.OperationPhaseReconciling
 
 		appList := emptyAppDeploymentList.DeepCopy()
-		mockClient.EXPECT().List(ctx, appList, gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1alpha1.AppDeploymentList, opts ...interface{}) error {
+		mockClient.EXPECT().List(ctx, appList, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1beta1.AppDeploymentList, opts ...interface{}) error {
 			*list = *validAppDeploymentList
 			return nil
 		})
 		scheme := runtime.NewScheme()
 		mockClient.EXPECT().Scheme().Return(scheme).AnyTimes()
-		readyAppDeployment := &v1alpha1.AppDeployment{}
-		readyAppDeployment.Status.Phase = v1alpha1.AppDeploymentPhaseReady
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opt ...interface{}) error {
-			*obj.(*v1alpha1.AppDeployment) = *readyAppDeployment
+			readyAppDeployment := &v1beta1.AppDeployment{}
+			readyAppDeployment.Status.Phase = v1beta1.AppDeploymentPhaseReady
+			readyAppDeployment.Status.Outputs = map[string]string{"app": key.Name}
+			if key.Name == "test-operation-test-app2" {
+				readyAppDeployment.Status.OutputsSecretName = key.Name + "-outputs"
+			}
+			*obj.(*v1beta1.AppDeployment) = *readyAppDeployment
 			return nil
 		}).AnyTimes()
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
@@ -336,9 +459,137 @@ func TestOperationHandler_EnsureAllAppsAreReady(t *testing.T) {
 		res, err := adapter.EnsureAllAppsAreReady(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, CancelRequest: true}, res)
-		assert.Equal(t, operation.Status.Phase, v1alpha1.OperationPhaseReconciled)
+		assert.Equal(t, operation.Status.Phase, v1beta1.OperationPhaseReconciled)
+		assert.NotNil(t, operation.Status.ReadyTime)
+		assert.Equal(t, []v1beta1.ApplicationOutputs{
+			{Name: "test-app1", Outputs: map[string]string{"app": "test-operation-test-app1"}},
+			{Name: "test-app2", Outputs: map[string]string{"app": "test-operation-test-app2"}, SecretName: "test-operation-test-app2-outputs"},
+		}, operation.Status.Outputs)
 	})
 
+	t.Run("happy path: update app deployments whose retry settings changed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseReconciling
+		operation.Spec.Applications[0].MaxRetries = ptr.Of(int32(5))
+
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1beta1.AppDeploymentList, opts ...any) error {
+			*list = *validAppDeploymentList.DeepCopy()
+			for i := range list.Items {
+				list.Items[i].ResourceVersion = "42"
+			}
+			return nil
+		})
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.AppDeployment{})).
+			DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+				app := obj.(*v1beta1.AppDeployment)
+				assert.Equal(t, "test-operation-test-app1", app.Name)
+				assert.Equal(t, "42", app.ResourceVersion)
+				assert.Equal(t, ptr.Of(int32(5)), app.Spec.MaxRetries)
+				return nil
+			})
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opt ...any) error {
+			*obj.(*v1beta1.AppDeployment) = v1beta1.AppDeployment{Status: v1beta1.AppDeploymentStatus{Phase: v1beta1.AppDeploymentPhaseReady}}
+			return nil
+		}).Times(2)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureAllAppsAreReady(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, CancelRequest: true}, res)
+	})
+
+	t.Run("sad path: fail the operation when an app deployment failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseReconciling
+
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1beta1.AppDeploymentList, opts ...any) error {
+			*list = *validAppDeploymentList
+			return nil
+		})
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opt ...any) error {
+			appDeployment := &v1beta1.AppDeployment{}
+			appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseReady
+			if key.Name == "test-operation-test-app2" {
+				appDeployment.Status.Phase = v1beta1.AppDeploymentPhaseFailed
+				appDeployment.Status.Conditions = []metav1.Condition{{
+					Type:    v1beta1.AppDeploymentConditionProvisionFailed,
+					Status:  metav1.ConditionTrue,
+					Reason:  "BackoffLimitExceeded",
+					Message: "job provision-test-app2 failed",
+				}}
+			}
+			*obj.(*v1beta1.AppDeployment) = *appDeployment
+			return nil
+		}).Times(2)
+		mockRecorder.EXPECT().Event(operation, "Warning", "AppsFailed", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureAllAppsAreReady(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.CancelRequest)
+		assert.Equal(t, v1beta1.OperationPhaseFailed, operation.Status.Phase)
+		condition := meta.FindStatusCondition(operation.Status.Conditions, v1beta1.OperationConditionAppsFailed)
+		require.NotNil(t, condition)
+		assert.Equal(t, "application test-app2: job provision-test-app2 failed", condition.Message)
+	})
+
+	t.Run("happy path: retry the failed app deployments of an annotated operation", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockClient := mockpkg.NewMockClient(mockCtrl)
+		mockRecorder := mockpkg.NewMockEventRecorder(mockCtrl)
+		mockStatusWriter := mockpkg.NewMockStatusWriter(mockCtrl)
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+
+		operation := validOperation.DeepCopy()
+		operation.Annotations = map[string]string{ctrlutils.AnnotationNameRetry: "true"}
+		operation.Status.Phase = v1beta1.OperationPhaseFailed
+		operation.Status.Conditions = []metav1.Condition{{Type: v1beta1.OperationConditionAppsFailed, Status: metav1.ConditionTrue, Reason: v1beta1.OperationConditionReasonProvisionFailed}}
+
+		failedAppDeployments := validAppDeploymentList.DeepCopy()
+		failedAppDeployments.Items[1].Status.Phase = v1beta1.AppDeploymentPhaseFailed
+		// listed once to retry the failed app deployments and once to reconcile them
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1beta1.AppDeploymentList, opts ...any) error {
+			*list = *failedAppDeployments.DeepCopy()
+			return nil
+		}).Times(2)
+		mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, obj *v1beta1.AppDeployment, patch client.Patch, opts ...any) error {
+			assert.Equal(t, failedAppDeployments.Items[1].Name, obj.Name)
+			assert.Contains(t, obj.Annotations, ctrlutils.AnnotationNameRetry)
+			return nil
+		})
+		mockRecorder.EXPECT().Event(operation, "Normal", "Retrying", gomock.Any())
+		mockClient.EXPECT().Update(ctx, operation).DoAndReturn(func(ctx context.Context, obj *v1beta1.Operation, opts ...any) error {
+			assert.NotContains(t, obj.Annotations, ctrlutils.AnnotationNameRetry)
+			return nil
+		})
+		// the app deployment picked up the retry
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object, opt ...any) error {
+			*obj.(*v1beta1.AppDeployment) = v1beta1.AppDeployment{Status: v1beta1.AppDeploymentStatus{Phase: v1beta1.AppDeploymentPhaseDeploying}}
+			return nil
+		}).Times(2)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureAllAppsAreReady(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.RequeueRequest)
+		assert.Equal(t, v1beta1.OperationPhaseReconciling, operation.Status.Phase)
+		assert.Nil(t, meta.FindStatusCondition(operation.Status.Conditions, v1beta1.OperationConditionAppsFailed))
+	})
 }
 
 func TestOperationHandler_EnsureFinalizer(t *testing.T) {
@@ -387,9 +638,9 @@ func TestOperationHandler_EnsureFinalizerRemoved(t *testing.T) {
 	t.Run("happy path: continue processing when operation is in deleted phase", func(t *testing.T) {
 
 		operation := validOperation.DeepCopy()
-		operation.ObjectMeta.Finalizers = []string{v1alpha1.OperationFinalizerName}
+		operation.ObjectMeta.Finalizers = []string{v1beta1.OperationFinalizerName}
 		operation.ObjectMeta.DeletionTimestamp = &metav1.Time{Time: time.Now()}
-		operation.Status.Phase = v1alpha1.OperationPhaseDeleted
+		operation.Status.Phase = v1beta1.OperationPhaseDeleted
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 
 		mockClient.EXPECT().Update(ctx, operation).Return(nil)
@@ -402,7 +653,7 @@ func TestOperationHandler_EnsureFinalizerRemoved(t *testing.T) {
 
 	t.Run("happy path: continue processing when operation is not in deleting phase", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.ObjectMeta.Finalizers = []string{v1alpha1.OperationFinalizerName}
+		operation.ObjectMeta.Finalizers = []string{v1beta1.OperationFinalizerName}
 		operation.ObjectMeta.DeletionTimestamp = &metav1.Time{Time: time.Now()}
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 
@@ -423,20 +674,82 @@ func TestOperationHandler_EnsureAllAppsAreDeleted(t *testing.T) {
 	mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
 	mockStatusWriterCtrl := gomock.NewController(t)
 	mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusWriterCtrl)
-	mockClient.EXPECT().Status().Return(mockStatusWriter)
+	mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
 
-	t.Run("happy path: continue processing when operation is in deleting phase", func(t *testing.T) {
+	expectAppDeploymentList := func(apps *v1beta1.AppDeploymentList) {
+		mockClient.EXPECT().List(ctx, gomock.AssignableToTypeOf(&v1beta1.AppDeploymentList{}), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, list *v1beta1.AppDeploymentList, opts ...any) error {
+			*list = *apps.DeepCopy()
+			return nil
+		})
+	}
+
+	t.Run("happy path: operation is deleted when all apps are torn down", func(t *testing.T) {
 		operation := validOperation.DeepCopy()
-		operation.Status.Phase = v1alpha1.OperationPhaseDeleting
+		operation.Status.Phase = v1beta1.OperationPhaseDeleting
 		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
 
+		expectAppDeploymentList(emptyAppDeploymentList)
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 		res, err := adapter.EnsureAllAppsAreDeleted(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, CancelRequest: true}, res)
-		assert.Equal(t, operation.Status.Phase, v1alpha1.OperationPhaseDeleted)
+		assert.Equal(t, operation.Status.Phase, v1beta1.OperationPhaseDeleted)
+		assert.True(t, meta.IsStatusConditionTrue(operation.Status.Conditions, v1beta1.OperationConditionAppsDeleted))
+	})
+
+	t.Run("happy path: dependents are torn down first", func(t *testing.T) {
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseDeleting
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+
+		// test-app1 depends on test-app2
+		expectAppDeploymentList(validAppDeploymentList)
+		mockClient.EXPECT().Delete(ctx, gomock.Cond(func(app *v1beta1.AppDeployment) bool {
+			return app.Name == "test-operation-test-app1"
+		}), gomock.Any()).Return(nil)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		res, err := adapter.EnsureAllAppsAreDeleted(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+		assert.Equal(t, v1beta1.OperationPhaseDeleting, operation.Status.Phase)
+		condition := meta.FindStatusCondition(operation.Status.Conditions, v1beta1.OperationConditionAppsDeleted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, v1beta1.OperationConditionReasonTeardownInProgress, condition.Reason)
+	})
+
+	t.Run("happy path: wait for app deployments which are already being torn down", func(t *testing.T) {
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseDeleting
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+
+		apps := validAppDeploymentList.DeepCopy()
+		apps.Items = apps.Items[1:]
+		apps.Items[0].DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		expectAppDeploymentList(apps)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
+		res, err := adapter.EnsureAllAppsAreDeleted(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+		assert.Equal(t, v1beta1.OperationPhaseDeleting, operation.Status.Phase)
+	})
+
+	t.Run("sad path: failed to delete app deployment", func(t *testing.T) {
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseDeleting
+		adapter := NewOperationHandler(ctx, operation, logger, mockClient, mockRecorder)
+
+		expectAppDeploymentList(validAppDeploymentList)
+		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(assert.AnError)
+		mockRecorder.EXPECT().Event(operation, "Warning", "TeardownFailed", gomock.Any())
+
+		res, err := adapter.EnsureAllAppsAreDeleted(ctx)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+		assert.Equal(t, v1beta1.OperationPhaseDeleting, operation.Status.Phase)
 	})
 
 	t.Run("happy path: continue processing when operation is in empty phase", func(t *testing.T) {
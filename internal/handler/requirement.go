@@ -2,20 +2,26 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
+	"github.com/Azure/operation-cache-controller/internal/metrics"
 	ctlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
-	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
@@ -23,6 +29,7 @@ type RequiremenContextKey struct{}
 
 //go:generate mockgen -destination=./mocks/mock_requirement.go -package=mocks github.com/Azure/operation-cache-controller/internal/handler RequirementHandlerInterface
 type RequirementHandlerInterface interface {
+	EnsureFinalizerDeleted(ctx context.Context) (reconciler.OperationResult, error)
 	EnsureNotExpired(ctx context.Context) (reconciler.OperationResult, error)
 	EnsureInitialized(ctx context.Context) (reconciler.OperationResult, error)
 	EnsureCacheExisted(ctx context.Context) (reconciler.OperationResult, error)
@@ -31,7 +38,7 @@ type RequirementHandlerInterface interface {
 }
 
 type RequirementHandler struct {
-	requirement *v1alpha1.Requirement
+	requirement *v1beta1.Requirement
 	logger      logr.Logger
 	client      client.Client
 	recorder    record.EventRecorder
@@ -41,7 +48,7 @@ type RequirementHandler struct {
 	rqutils    ctlutils.RequirementHelper
 }
 
-func NewRequirementHandler(ctx context.Context, requirement *v1alpha1.Requirement, logger logr.Logger, client client.Client, recorder record.EventRecorder) RequirementHandlerInterface {
+func NewRequirementHandler(ctx context.Context, requirement *v1beta1.Requirement, logger logr.Logger, client client.Client, recorder record.EventRecorder) RequirementHandlerInterface {
 	if requirementHandler, ok := ctx.Value(RequiremenContextKey{}).(RequirementHandlerInterface); ok {
 		return requirementHandler
 	}
@@ -68,23 +75,103 @@ func (o *RequirementHandler) phaseIn(phases ...string) bool {
 	return false
 }
 
-func (r *RequirementHandler) EnsureNotExpired(ctx context.Context) (reconciler.OperationResult, error) {
-	r.logger.V(1).Info("operation: EnsureNotExpired")
-	if len(r.requirement.Spec.ExpireAt) == 0 {
+// EnsureFinalizerDeleted returns the cached operation a deleted requirement acquired to its cache when the cache
+// recycles operations. Otherwise it deletes the operation acquired from the cache pool of another namespace, which the
+// garbage collector cannot do as owner references cannot cross namespaces.
+func (r *RequirementHandler) EnsureFinalizerDeleted(ctx context.Context) (reconciler.OperationResult, error) {
+	if r.requirement.DeletionTimestamp.IsZero() || !controllerutil.ContainsFinalizer(r.requirement, v1beta1.RequirementFinalizerName) {
 		return reconciler.ContinueProcessing()
 	}
+	r.logger.V(1).Info("operation: EnsureFinalizerDeleted")
+	if len(r.requirement.Status.OperationName) > 0 {
+		operation := &v1beta1.Operation{}
+		key := types.NamespacedName{Name: r.requirement.Status.OperationName, Namespace: r.operationNamespace()}
+		if err := r.client.Get(ctx, key, operation); client.IgnoreNotFound(err) != nil {
+			return reconciler.RequeueWithError(fmt.Errorf("failed to get operation %s: %w", key, err))
+		}
+		// an operation still in the cache pool was never acquired, it belongs to the cache
+		if r.isAcquiredByRequirement(operation) {
+			released, err := r.releaseOperation(ctx, operation)
+			if err != nil {
+				return reconciler.RequeueWithError(err)
+			}
+			if !released && r.isPooled() {
+				r.logger.Info("deleting operation acquired from the cache pool", "operation", key)
+				if err := r.client.Delete(ctx, operation, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+					return reconciler.RequeueWithError(fmt.Errorf("failed to delete operation %s: %w", key, err))
+				}
+			}
+		}
+	}
+	controllerutil.RemoveFinalizer(r.requirement, v1beta1.RequirementFinalizerName)
+	return reconciler.RequeueOnErrorOrStop(r.client.Update(ctx, r.requirement))
+}
+
+// releaseOperation hands the operation over to its cache again when the cache recycles operations, the cache resets it
+// before it can be acquired again. It returns false when the operation cannot be reused, because the cache does not
+// recycle operations, the operation is not ready, its applications changed since it was acquired, or it was reused as
+// often as the cache allows.
+func (r *RequirementHandler) releaseOperation(ctx context.Context, operation *v1beta1.Operation) (bool, error) {
+	cache := &v1beta1.Cache{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: r.defaultCacheName(), Namespace: operation.Namespace}, cache); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to get cache %s: %w", r.defaultCacheName(), err)
+		}
+		return false, nil
+	}
+	if !cache.DeletionTimestamp.IsZero() || !r.oputils.IsOperationReady(operation) ||
+		operation.Status.CacheKey != cache.Status.CacheKey || operation.Status.ReuseCount >= r.cacheutils.MaxReuse(cache) {
+		return false, nil
+	}
+	original := operation.DeepCopy()
+	delete(operation.Annotations, v1beta1.OperationAcquiredAnnotationKey)
+	delete(operation.Annotations, v1beta1.OperationAcquiredByAnnotationKey)
+	operation.Annotations[v1beta1.OperationReleasedAnnotationKey] = time.Now().Format(time.RFC3339)
+	// objects read from the api server have no type meta, so the kind cannot be taken from the cache itself
+	operation.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(cache, v1beta1.GroupVersion.WithKind("Cache"))}
+	if err := r.client.Patch(ctx, operation, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return false, fmt.Errorf("failed to release operation %s: %w", operation.Name, err)
+	}
+	r.logger.Info("operation released to the cache pool", "operation", operation.Name, "reuseCount", operation.Status.ReuseCount)
+	r.recorder.Event(r.requirement, "Normal", "OperationReleased", fmt.Sprintf("Operation %s returned to cache %s", operation.Name, cache.Name))
+	return true, nil
+}
 
-	expireTime, err := time.Parse(time.RFC3339, r.requirement.Spec.ExpireAt)
+func (r *RequirementHandler) EnsureNotExpired(ctx context.Context) (reconciler.OperationResult, error) {
+	r.logger.V(1).Info("operation: EnsureNotExpired")
+	lastAccessTime, err := ctlutils.LastAccessTime(r.requirement)
 	if err != nil {
-		r.logger.Error(err, fmt.Sprintf("Failed to parse expire time: %s", r.requirement.Spec.ExpireAt))
-		r.recorder.Event(r.requirement, "Warning", "InvalidExpireTime", "Failed to parse expire time")
-		return reconciler.ContinueProcessing()
+		r.logger.Error(err, "ignoring the last access time")
+		r.recorder.Event(r.requirement, "Warning", "InvalidLastAccessTime", err.Error())
+	}
+	spec := r.requirement.Spec
+	expiry := ctlutils.Expiry{ExpireAt: spec.ExpireAt, TTLAfterReady: spec.TTLAfterReady, TTLAfterLastAccess: spec.TTLAfterLastAccess}
+	expireAt := expiry.Deadline(r.requirement.Status.ReadyTime, lastAccessTime)
+	now := time.Now()
+	leaseEnd, changed := r.renewLease(now, expireAt)
+	if leaseEnd != nil {
+		// the lease replaces the expiry while the consumer renews the requirement
+		expireAt = leaseEnd
+	}
+	changed = changed || !ctlutils.TimeEqual(expireAt, r.requirement.Status.ExpireAt)
+	r.requirement.Status.ExpireAt = expireAt
+	conditionChanged, expiringSoon := ctlutils.SetExpiringSoonCondition(&r.requirement.Status.Conditions, expireAt, now)
+	if changed || conditionChanged {
+		if err := r.client.Status().Update(ctx, r.requirement); err != nil {
+			return reconciler.RequeueWithError(err)
+		}
 	}
-	if time.Now().Before(expireTime) {
+	if expireAt == nil {
 		return reconciler.ContinueProcessing()
 	}
+	if now.Before(expireAt.Time) {
+		if expiringSoon {
+			r.recorder.Event(r.requirement, "Warning", "ExpiringSoon", fmt.Sprintf("Requirement expires at %s, extend it to keep it", expireAt.UTC().Format(time.RFC3339)))
+		}
+		return reconciler.ContinueProcessingWithin(ctlutils.ExpiryRequeueDelay(expireAt, now))
+	}
 	// Expired
-	r.logger.Info("deleting expired requirement", "expireAt", r.requirement.Spec.ExpireAt)
+	r.logger.Info("deleting expired requirement", "expireAt", expireAt)
 	if err := r.client.Delete(ctx, r.requirement, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
 		r.logger.Error(err, "Failed to delete expired requirement")
 		r.recorder.Event(r.requirement, "Warning", "DeleteFailed", "Failed to delete expired requirement")
@@ -93,53 +180,135 @@ func (r *RequirementHandler) EnsureNotExpired(ctx context.Context) (reconciler.O
 	return reconciler.ContinueProcessing()
 }
 
+// renewLease records a renewal of the requirement by its consumer. It returns the end of the lease, nil if the
+// requirement is not renewed, and whether the status changed. Every change of the renew-until annotation is a
+// heartbeat. A renewal beyond the max lifetime is capped, and rejected when the capped lease would end before the
+// requirement expires on its own, a heartbeat never shortens the life of the requirement.
+func (r *RequirementHandler) renewLease(now time.Time, expireAt *metav1.Time) (*metav1.Time, bool) {
+	renewUntil, err := ctlutils.RenewUntil(r.requirement)
+	if err != nil {
+		r.logger.Error(err, "ignoring the renewal, keeping the current lease")
+		r.recorder.Event(r.requirement, "Warning", "InvalidRenewUntil", err.Error())
+		return r.requirement.Status.RenewUntil, false
+	}
+	annotation := r.requirement.Annotations[ctlutils.AnnotationNameRenewUntil]
+	heartbeat := annotation != r.requirement.Status.ObservedRenewUntil
+	changed := heartbeat
+	r.requirement.Status.ObservedRenewUntil = annotation
+	if renewUntil == nil {
+		changed = changed || r.requirement.Status.RenewUntil != nil
+		r.requirement.Status.RenewUntil = nil
+		return nil, changed
+	}
+	if heartbeat {
+		r.logger.Info("requirement renewed", "renewUntil", renewUntil)
+		r.requirement.Status.LastHeartbeatTime = &metav1.Time{Time: now}
+	}
+	leaseEnd, capped := ctlutils.LeaseDeadline(renewUntil, r.requirement.CreationTimestamp)
+	if capped && (expireAt == nil || !leaseEnd.After(expireAt.Time)) {
+		if heartbeat {
+			r.recorder.Event(r.requirement, "Warning", "LeaseRejected", fmt.Sprintf("Renewal rejected, the max lifetime ended at %s, the requirement keeps its own expiry", leaseEnd.UTC().Format(time.RFC3339)))
+		}
+		leaseEnd = nil
+	} else if capped && heartbeat {
+		r.recorder.Event(r.requirement, "Warning", "LeaseCapped", fmt.Sprintf("Renewal capped at the max lifetime, the requirement expires at %s", leaseEnd.UTC().Format(time.RFC3339)))
+	}
+	changed = changed || !ctlutils.TimeEqual(leaseEnd, r.requirement.Status.RenewUntil)
+	r.requirement.Status.RenewUntil = leaseEnd
+	return leaseEnd, changed
+}
+
 func (r *RequirementHandler) EnsureInitialized(ctx context.Context) (reconciler.OperationResult, error) {
 	r.logger.V(1).Info("operation: EnsureInitialized")
-	if !r.phaseIn(v1alpha1.RequirementPhaseEmpty) {
+	if !r.phaseIn(v1beta1.RequirementPhaseEmpty) {
 		return reconciler.ContinueProcessing()
 	}
 	r.requirement.Status.CacheKey = r.cacheutils.NewCacheKeyFromApplications(r.requirement.Spec.Template.Applications)
 	r.rqutils.ClearConditions(r.requirement)
-	if r.requirement.Spec.EnableCache {
-		r.requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+	if r.requirement.Spec.CacheEnabled() {
+		r.requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 	} else {
-		r.requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
+		r.requirement.Status.Phase = v1beta1.RequirementPhaseOperating
 	}
 	return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 }
 
 func (r *RequirementHandler) ownerReference() metav1.OwnerReference {
-	return metav1.OwnerReference{
-		APIVersion:         r.requirement.APIVersion,
-		Kind:               r.requirement.Kind,
-		Name:               r.requirement.Name,
-		UID:                r.requirement.UID,
-		Controller:         ptr.Of(true),
-		BlockOwnerDeletion: ptr.Of(true),
-	}
+	// objects read from the api server have no type meta, so the kind cannot be taken from the requirement itself
+	return *metav1.NewControllerRef(r.requirement, v1beta1.GroupVersion.WithKind("Requirement"))
 }
 
 func (r *RequirementHandler) setCacheNotExistedStatus() {
-	r.requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
-	_ = r.rqutils.UpdateCondition(r.requirement, v1alpha1.RequirementConditionCacheResourceFound, metav1.ConditionFalse, v1alpha1.RequirementConditionReasonCacheCRFound, "Cache CR found")
+	r.requirement.Status.Phase = v1beta1.RequirementPhaseOperating
+	_ = r.rqutils.UpdateCondition(r.requirement, v1beta1.RequirementConditionCacheResourceFound, metav1.ConditionFalse, v1beta1.RequirementConditionReasonCacheCRFound, "Cache CR found")
 }
 
 func (r *RequirementHandler) setCacheHitStatus() {
-	r.requirement.Status.Phase = v1alpha1.RequirementPhaseReady
-	_ = r.rqutils.UpdateCondition(r.requirement, v1alpha1.RequirementConditionOperationReady, metav1.ConditionTrue, v1alpha1.RequirementConditionReasonCacheHit, "Cached Operation acquired")
+	r.requirement.Status.Phase = v1beta1.RequirementPhaseReady
+	r.setReadyTime()
+	_ = r.rqutils.UpdateCondition(r.requirement, v1beta1.RequirementConditionOperationReady, metav1.ConditionTrue, v1beta1.RequirementConditionReasonCacheHit, "Cached Operation acquired")
 }
 
 func (r *RequirementHandler) setCacheMissStatus() {
-	r.requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
-	_ = r.rqutils.UpdateCondition(r.requirement, v1alpha1.RequirementConditionCachedOperationAcquired, metav1.ConditionTrue, v1alpha1.RequirementConditionReasonCacheMiss, "No cached operation available")
+	r.requirement.Status.Phase = v1beta1.RequirementPhaseOperating
+	_ = r.rqutils.UpdateCondition(r.requirement, v1beta1.RequirementConditionCachedOperationAcquired, metav1.ConditionTrue, v1beta1.RequirementConditionReasonCacheMiss, "No cached operation available")
+}
+
+// setReadyTime records the time the requirement first got ready, its ttlAfterReady counts from it
+func (r *RequirementHandler) setReadyTime() {
+	if r.requirement.Status.ReadyTime == nil {
+		now := metav1.Now()
+		r.requirement.Status.ReadyTime = &now
+	}
+}
+
+// observeReady records the time to ready of the requirement. A spec change sends a ready requirement back to
+// operating, so only the first time it gets ready is observed.
+func (r *RequirementHandler) observeReady(hit bool) {
+	if r.requirement.Generation > 1 {
+		return
+	}
+	metrics.ObserveRequirementReady(r.requirement.CreationTimestamp.Time, hit)
 }
 
 func (r *RequirementHandler) defaultCacheName() string {
-	return fmt.Sprintf("cache-%s", r.requirement.Status.CacheKey)
+	return r.cacheutils.CacheName(r.requirement.Status.CacheKey)
+}
+
+// cacheNamespace is the namespace of the cache of the requirement, the cache pool when its namespace is allowed to
+// use the pool
+func (r *RequirementHandler) cacheNamespace() string {
+	if pool := config.Current().CachePool; pool.Allows(r.requirement.Namespace) {
+		return pool.Namespace
+	}
+	return r.requirement.Namespace
+}
+
+// operationNamespace is the namespace of the operation of the requirement
+func (r *RequirementHandler) operationNamespace() string {
+	if len(r.requirement.Status.OperationNamespace) > 0 {
+		return r.requirement.Status.OperationNamespace
+	}
+	return r.requirement.Namespace
+}
+
+// isPooled reports whether the operation of the requirement is in the cache pool of another namespace
+func (r *RequirementHandler) isPooled() bool {
+	return len(r.requirement.Status.OperationNamespace) > 0
+}
+
+func (r *RequirementHandler) acquiredByValue() string {
+	return r.requirement.Namespace + "/" + r.requirement.Name
+}
+
+// clearOperation forgets the cached operation the requirement selected
+func (r *RequirementHandler) clearOperation() {
+	r.requirement.Status.OperationName = ""
+	r.requirement.Status.OperationNamespace = ""
 }
 
 func (r *RequirementHandler) EnsureCacheExisted(ctx context.Context) (reconciler.OperationResult, error) {
-	if !r.phaseIn(v1alpha1.RequirementPhaseCacheChecking) {
+	if !r.phaseIn(v1beta1.RequirementPhaseCacheChecking) {
 		return reconciler.ContinueProcessing()
 	}
 
@@ -154,17 +323,22 @@ func (r *RequirementHandler) EnsureCacheExisted(ctx context.Context) (reconciler
 		r.logger.Error(fmt.Errorf("empty cache key"), "Cache key is empty, cannot proceed with cache creation")
 		return reconciler.RequeueWithError(fmt.Errorf("empty cache key"))
 	}
-	cache := &v1alpha1.Cache{}
+	// requirements initialized before an upgrade of the cache key look up the re-keyed cache
+	if !r.cacheutils.IsCurrentCacheKey(r.requirement.Status.CacheKey) {
+		r.requirement.Status.CacheKey = r.cacheutils.NewCacheKeyFromApplications(r.requirement.Spec.Template.Applications)
+	}
+	cache := &v1beta1.Cache{}
+	cacheNamespace := r.cacheNamespace()
 	// Try to get the Cache CR
-	if err := r.client.Get(ctx, types.NamespacedName{Name: r.defaultCacheName(), Namespace: r.requirement.Namespace}, cache); err != nil {
+	if err := r.client.Get(ctx, types.NamespacedName{Name: r.defaultCacheName(), Namespace: cacheNamespace}, cache); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			// If the error is not a NotFound error, return it
 			return reconciler.RequeueWithError(err)
 		}
 		// cache cr not found, create it
 		cache.Name = r.defaultCacheName()
-		cache.Namespace = r.requirement.Namespace
-		cache.Spec = v1alpha1.CacheSpec{
+		cache.Namespace = cacheNamespace
+		cache.Spec = v1beta1.CacheSpec{
 			OperationTemplate: r.requirement.Spec.Template,
 			ExpireTime:        r.cacheutils.DefaultCacheExpireTime(),
 		}
@@ -172,6 +346,7 @@ func (r *RequirementHandler) EnsureCacheExisted(ctx context.Context) (reconciler
 		if err != nil {
 			return reconciler.RequeueWithError(err)
 		}
+		r.recordCreatedCacheMiss(ctx, cache)
 		r.setCacheNotExistedStatus()
 		return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 	}
@@ -179,63 +354,259 @@ func (r *RequirementHandler) EnsureCacheExisted(ctx context.Context) (reconciler
 	cache.Spec.ExpireTime = r.cacheutils.DefaultCacheExpireTime()
 	_ = r.client.Update(ctx, cache)
 	r.requirement.Status.OperationName = r.cacheutils.RandomSelectCachedOperation(cache)
+	if cacheNamespace != r.requirement.Namespace {
+		r.requirement.Status.OperationNamespace = cacheNamespace
+	}
 	return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 }
 
 func (r *RequirementHandler) EnsureCachedOperationAcquired(ctx context.Context) (reconciler.OperationResult, error) {
-	if !r.phaseIn(v1alpha1.RequirementPhaseCacheChecking) {
+	if !r.phaseIn(v1beta1.RequirementPhaseCacheChecking) {
 		return reconciler.ContinueProcessing()
 	}
 	r.logger.V(1).Info("operation: EnsureCachedOperationAcquired")
 	if len(r.requirement.Status.OperationName) == 0 {
 		r.logger.V(1).Info("no cached operation available")
+		r.recordCacheDemand(ctx, false)
 		r.setCacheMissStatus()
 		return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 	}
-	operation := &v1alpha1.Operation{}
-	if err := r.client.Get(ctx, types.NamespacedName{Name: r.requirement.Status.OperationName, Namespace: r.requirement.Namespace}, operation); err != nil {
+	operation := &v1beta1.Operation{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: r.requirement.Status.OperationName, Namespace: r.operationNamespace()}, operation); client.IgnoreNotFound(err) != nil {
 		r.setCacheMissStatus()
 		return reconciler.RequeueOnErrorOrContinue(fmt.Errorf("failed to get operation %s: %w", r.requirement.Status.OperationName, err))
 	}
-	// already acquired
-	if _, ok := operation.Annotations[v1alpha1.OperationAcquiredAnnotationKey]; ok {
-		if len(operation.OwnerReferences) != 0 {
-			if operation.OwnerReferences[0].UID != r.requirement.UID {
-				// return error if owner is not this requirement
-				r.logger.V(1).Info("operation already acquired by other requirement", "operation", r.requirement.Status.OperationName)
-				r.setCacheMissStatus()
-				return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
-			} else {
-				// set to ready status if the operation already acquired by this requirement
-				r.logger.V(1).Info("operation already acquired by this requirement", "operation", r.requirement.Status.OperationName)
-				r.setCacheHitStatus()
-				return reconciler.RequeueOnErrorOrStop(r.client.Status().Update(ctx, r.requirement))
-			}
+	// already acquired by this requirement in a previous reconcile
+	if r.isAcquiredByRequirement(operation) {
+		r.logger.V(1).Info("operation already acquired by this requirement", "operation", r.requirement.Status.OperationName)
+		if err := r.bindOutputs(ctx, operation); err != nil {
+			return reconciler.RequeueWithError(err)
 		}
+		r.setCacheHitStatus()
+		return reconciler.RequeueOnErrorOrStop(r.client.Status().Update(ctx, r.requirement))
 	}
-	// if operation not acquired, acquire it
-	if err := r.acquireCachedOperation(ctx, operation); err != nil {
+
+	acquired, err := r.acquireFromCandidates(ctx, operation)
+	if err != nil {
 		r.setCacheMissStatus()
-		return reconciler.RequeueOnErrorOrContinue(fmt.Errorf("failed to update operation %s: %w", r.requirement.Status.OperationName, err))
+		return reconciler.RequeueOnErrorOrContinue(fmt.Errorf("failed to acquire cached operation: %w", err))
+	}
+	if acquired == nil {
+		r.logger.V(1).Info("all cached operations already acquired by other requirements")
+		r.clearOperation()
+		r.recordCacheDemand(ctx, false)
+		r.setCacheMissStatus()
+		return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 	}
 	// set to ready status if the operation acquired
+	r.requirement.Status.OperationName = acquired.Name
+	if err := r.bindOutputs(ctx, acquired); err != nil {
+		// the acquired operation is kept, the next reconcile finds it acquired and binds its outputs again
+		return reconciler.RequeueWithError(errors.Join(err, r.client.Status().Update(ctx, r.requirement)))
+	}
+	r.recordCacheDemand(ctx, true)
 	r.setCacheHitStatus()
+	r.observeReady(true)
 	return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 }
 
-func (r *RequirementHandler) acquireCachedOperation(ctx context.Context, operation *v1alpha1.Operation) error {
-	operation.Annotations[v1alpha1.OperationAcquiredAnnotationKey] = time.Now().Format(time.RFC3339)
-	operation.OwnerReferences = []metav1.OwnerReference{r.ownerReference()}
-	return r.client.Update(ctx, operation)
+func (r *RequirementHandler) isAcquiredByRequirement(operation *v1beta1.Operation) bool {
+	if _, ok := operation.Annotations[v1beta1.OperationAcquiredAnnotationKey]; !ok {
+		return false
+	}
+	if r.isPooled() {
+		return operation.Annotations[v1beta1.OperationAcquiredByAnnotationKey] == r.acquiredByValue()
+	}
+	for _, owner := range operation.OwnerReferences {
+		if owner.UID == r.requirement.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// isAcquirable checks that the operation is still a ready operation in the cache pool, released operations wait for
+// their reset
+func (r *RequirementHandler) isAcquirable(operation *v1beta1.Operation) bool {
+	if operation.Name == "" || !operation.DeletionTimestamp.IsZero() {
+		return false
+	}
+	if _, ok := operation.Annotations[v1beta1.OperationAcquiredAnnotationKey]; ok {
+		return false
+	}
+	owner := metav1.GetControllerOf(operation)
+	if owner == nil || owner.Kind != "Cache" || owner.Name != r.defaultCacheName() {
+		return false
+	}
+	return r.oputils.IsOperationAvailable(operation)
+}
+
+// acquireFromCandidates tries to acquire the selected operation first, and when another requirement was faster,
+// the remaining operations available in the cache pool in random order. It returns nil when all of them are gone.
+func (r *RequirementHandler) acquireFromCandidates(ctx context.Context, selected *v1beta1.Operation) (*v1beta1.Operation, error) {
+	cache := &v1beta1.Cache{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: r.defaultCacheName(), Namespace: r.operationNamespace()}, cache); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get cache %s: %w", r.defaultCacheName(), err)
+		}
+		cache = nil
+	}
+	ok, err := r.tryAcquireCachedOperation(ctx, cache, selected)
+	if err != nil || ok {
+		return selected, err
+	}
+	if cache == nil {
+		return nil, nil
+	}
+	for _, name := range r.cacheutils.ShuffledCachedOperations(cache) {
+		if name == selected.Name {
+			continue
+		}
+		candidate := &v1beta1.Operation{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: r.operationNamespace()}, candidate); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get operation %s: %w", name, err)
+			}
+			continue
+		}
+		ok, err := r.tryAcquireCachedOperation(ctx, cache, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+// tryAcquireCachedOperation returns false without error when the operation is not available anymore or another
+// requirement acquired it concurrently.
+func (r *RequirementHandler) tryAcquireCachedOperation(ctx context.Context, cache *v1beta1.Cache, operation *v1beta1.Operation) (bool, error) {
+	if !r.isAcquirable(operation) {
+		return false, nil
+	}
+	if err := r.acquireCachedOperation(ctx, cache, operation); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			r.logger.V(1).Info("cached operation taken by another requirement", "operation", operation.Name)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update operation %s: %w", operation.Name, err)
+	}
+	return true, nil
+}
+
+// bindOutputs sets the outputs of the acquired operation to the requirement. The requirement cannot read the secrets of
+// the cache pool, the secret outputs of an operation of the pool are copied to the namespace of the requirement and
+// owned by it.
+func (r *RequirementHandler) bindOutputs(ctx context.Context, operation *v1beta1.Operation) error {
+	outputs := make([]v1beta1.ApplicationOutputs, 0, len(operation.Status.Outputs))
+	for _, output := range operation.Status.Outputs {
+		output := *output.DeepCopy()
+		if len(output.SecretName) > 0 && r.isPooled() {
+			name, err := r.copyOutputsSecret(ctx, output)
+			if err != nil {
+				return err
+			}
+			output.SecretName = name
+		}
+		outputs = append(outputs, output)
+	}
+	if len(outputs) == 0 {
+		outputs = nil
+	}
+	r.requirement.Status.Outputs = outputs
+	return nil
+}
+
+// copyOutputsSecret copies the secret outputs of an application of the cache pool to the namespace of the requirement
+func (r *RequirementHandler) copyOutputsSecret(ctx context.Context, output v1beta1.ApplicationOutputs) (string, error) {
+	source := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: output.SecretName, Namespace: r.operationNamespace()}, source); err != nil {
+		return "", fmt.Errorf("failed to get secret outputs %s: %w", output.SecretName, err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctlutils.RequirementOutputsSecretName(r.requirement, output.Name),
+			Namespace: r.requirement.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+		secret.Data = source.Data
+		return controllerutil.SetControllerReference(r.requirement, secret, r.client.Scheme())
+	}); err != nil {
+		r.recorder.Event(r.requirement, "Warning", "FailedCopyOutputs", err.Error())
+		return "", fmt.Errorf("failed to copy secret outputs %s: %w", output.SecretName, err)
+	}
+	return secret.Name, nil
+}
+
+// recordCacheDemand counts a cache hit or miss in the status of the cache cr, which the cache controller sizes its pool from.
+// Failing to record is logged only, the demand is an estimation and must not block the requirement.
+func (r *RequirementHandler) recordCacheDemand(ctx context.Context, hit bool) {
+	metrics.RecordCacheResult(r.requirement.Namespace, r.requirement.Status.CacheKey, hit)
+	key := types.NamespacedName{Name: r.defaultCacheName(), Namespace: r.cacheNamespace()}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cache := &v1beta1.Cache{}
+		if err := r.client.Get(ctx, key, cache); err != nil {
+			return err
+		}
+		r.cacheutils.RecordDemand(cache, hit, time.Now())
+		return r.client.Status().Update(ctx, cache)
+	})
+	if err != nil {
+		r.logger.Error(err, "failed to record cache demand", "cache", key.Name)
+	}
+}
+
+// recordCreatedCacheMiss counts the miss that created the cache cr. It is recorded on the created object itself, the
+// cached client does not see a cache cr right after its creation and would drop the first miss.
+func (r *RequirementHandler) recordCreatedCacheMiss(ctx context.Context, cache *v1beta1.Cache) {
+	metrics.RecordCacheResult(r.requirement.Namespace, r.requirement.Status.CacheKey, false)
+	r.cacheutils.RecordDemand(cache, false, time.Now())
+	if err := r.client.Status().Update(ctx, cache); err != nil {
+		r.logger.Error(err, "failed to record cache demand", "cache", cache.Name)
+	}
 }
 
-func (r *RequirementHandler) getOperation() (*v1alpha1.Operation, error) {
+// acquireCachedOperation takes the operation over from the cache cr. The patch carries the resourceVersion the
+// operation was read at, so when two requirements race for the same operation the api server rejects the second one.
+// An operation of the cache pool cannot be owned by a requirement of another namespace, it is recorded in an
+// annotation instead. The finalizer of the requirement, added only when the operation is in the cache pool or the cache
+// recycles operations, returns the operation to a recycling cache once the requirement is deleted and deletes a pooled
+// operation which is not returned. Other operations are garbage collected with the requirement owning them.
+func (r *RequirementHandler) acquireCachedOperation(ctx context.Context, cache *v1beta1.Cache, operation *v1beta1.Operation) error {
+	original := operation.DeepCopy()
+	if operation.Annotations == nil {
+		operation.Annotations = map[string]string{}
+	}
+	operation.Annotations[v1beta1.OperationAcquiredAnnotationKey] = time.Now().Format(time.RFC3339)
+	recycled := cache != nil && cache.Spec.Recycle != nil
+	if (r.isPooled() || recycled) && !controllerutil.ContainsFinalizer(r.requirement, v1beta1.RequirementFinalizerName) {
+		status := r.requirement.Status.DeepCopy()
+		controllerutil.AddFinalizer(r.requirement, v1beta1.RequirementFinalizerName)
+		if err := r.client.Update(ctx, r.requirement); err != nil {
+			return fmt.Errorf("failed to add finalizer: %w", err)
+		}
+		// the update returns the stored status, keep the one being reconciled
+		r.requirement.Status = *status
+	}
+	if r.isPooled() {
+		operation.Annotations[v1beta1.OperationAcquiredByAnnotationKey] = r.acquiredByValue()
+		operation.OwnerReferences = nil
+	} else {
+		operation.OwnerReferences = []metav1.OwnerReference{r.ownerReference()}
+	}
+	return r.client.Patch(ctx, operation, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+func (r *RequirementHandler) getOperation() (*v1beta1.Operation, error) {
 	namespacedName := types.NamespacedName{
 		Name:      r.requirement.Status.OperationName,
-		Namespace: r.requirement.Namespace,
+		Namespace: r.operationNamespace(),
 	}
 
-	operation := &v1alpha1.Operation{}
+	operation := &v1beta1.Operation{}
 	if err := r.client.Get(context.Background(), namespacedName, operation); err != nil {
 		return nil, fmt.Errorf("failed to get operation %s: %w", r.requirement.Status.OperationName, err)
 	}
@@ -252,7 +623,7 @@ func (r *RequirementHandler) updateOperation() error {
 }
 
 func (r *RequirementHandler) createOperation() error {
-	operation := &v1alpha1.Operation{
+	operation := &v1beta1.Operation{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      r.requirement.Status.OperationName,
 			Namespace: r.requirement.Namespace,
@@ -267,36 +638,51 @@ func (r *RequirementHandler) createOperation() error {
 
 func (r *RequirementHandler) EnsureOperationReady(ctx context.Context) (reconciler.OperationResult, error) {
 	r.logger.V(1).Info("operation: EnsureOperationReady")
-	if r.phaseIn(v1alpha1.RequirementPhaseReady) {
+	if r.phaseIn(v1beta1.RequirementPhaseReady) {
 		// check if application changed
 		cacheKey := r.cacheutils.NewCacheKeyFromApplications(r.requirement.Spec.Template.Applications)
+		if r.requirement.Status.CacheKey != cacheKey && r.cacheutils.CacheKeyMatches(r.requirement.Status.CacheKey, r.requirement.Spec.Template.Applications) {
+			r.logger.V(1).Info("re-keying requirement", "oldCacheKey", r.requirement.Status.CacheKey, "newCacheKey", cacheKey)
+			r.requirement.Status.CacheKey = cacheKey
+			return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
+		}
 		if r.requirement.Status.CacheKey != cacheKey {
 			r.logger.Info("application changed, updating operation", "oldCacheKey", r.requirement.Status.CacheKey, "newCacheKey", cacheKey)
 			if err := r.updateOperation(); err != nil {
 				return reconciler.RequeueWithError(err)
 			}
 			r.requirement.Status.CacheKey = cacheKey
-			r.requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
+			r.requirement.Status.Phase = v1beta1.RequirementPhaseOperating
 			return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 		}
 		return reconciler.ContinueProcessing()
 	}
-	if !r.phaseIn(v1alpha1.RequirementPhaseOperating) {
+	if r.phaseIn(v1beta1.RequirementPhaseFailed) {
+		return r.retryIfRequested(ctx)
+	}
+	if !r.phaseIn(v1beta1.RequirementPhaseOperating) {
 		return reconciler.ContinueProcessing()
 	}
 	if r.rqutils.IsCacheMissed(r.requirement) {
 		r.logger.V(1).Info("cache missed, creating operation")
 		r.requirement.Status.OperationName = r.requirement.Name // reset operation name to requirement name
+		r.requirement.Status.OperationNamespace = ""
 	}
 	// check operation status
 	if op, err := r.getOperation(); err == nil {
 		r.logger.V(1).Info("requirement operation found", "operation", op.Name)
-		if op.Status.Phase == v1alpha1.OperationPhaseReconciled {
+		if op.Status.Phase == v1beta1.OperationPhaseReconciled {
 			r.logger.Info("operation is reconciled, set requirement to ready", "operationName", op.Name, "operationId", op.Status.OperationID)
-			r.requirement.Status.Phase = v1alpha1.RequirementPhaseReady
-			r.requirement.Status.OperationId = op.Status.OperationID
+			r.requirement.Status.Phase = v1beta1.RequirementPhaseReady
+			r.setReadyTime()
+			r.requirement.Status.OperationID = op.Status.OperationID
+			r.requirement.Status.Outputs = op.Status.Outputs
+			r.observeReady(false)
 			return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
 		}
+		if op.Status.Phase == v1beta1.OperationPhaseFailed {
+			return r.failRequirement(ctx, op)
+		}
 		r.logger.V(1).Info("reconciling requirement operation...", "operation", op.Name)
 		return reconciler.Requeue()
 	}
@@ -306,3 +692,49 @@ func (r *RequirementHandler) EnsureOperationReady(ctx context.Context) (reconcil
 	}
 	return reconciler.Requeue()
 }
+
+// failRequirement moves the requirement to the failed phase with the failures of its operation
+func (r *RequirementHandler) failRequirement(ctx context.Context, op *v1beta1.Operation) (reconciler.OperationResult, error) {
+	message := fmt.Sprintf("Operation %s failed", op.Name)
+	if condition := meta.FindStatusCondition(op.Status.Conditions, v1beta1.OperationConditionAppsFailed); condition != nil {
+		message = fmt.Sprintf("Operation %s failed:\n%s", op.Name, condition.Message)
+	}
+	r.logger.Info("operation failed, set requirement to failed", "operationName", op.Name)
+	r.recorder.Event(r.requirement, "Warning", "OperationFailed",
+		fmt.Sprintf("Operation %s failed, annotate with %s to retry", op.Name, ctlutils.AnnotationNameRetry))
+	r.requirement.Status.Phase = v1beta1.RequirementPhaseFailed
+	_ = r.rqutils.UpdateCondition(r.requirement, v1beta1.RequirementConditionOperationReady, metav1.ConditionFalse, v1beta1.RequirementConditionReasonOperationFailed, message)
+	return reconciler.RequeueOnErrorOrStop(r.client.Status().Update(ctx, r.requirement))
+}
+
+// retryIfRequested passes the retry annotation of a failed requirement on to its operation. The requirement goes back
+// to operating once its operation is no longer failed, also when the operation was retried directly.
+func (r *RequirementHandler) retryIfRequested(ctx context.Context) (reconciler.OperationResult, error) {
+	op, err := r.getOperation()
+	if err != nil {
+		return reconciler.RequeueWithError(err)
+	}
+	value, ok := r.requirement.Annotations[ctlutils.AnnotationNameRetry]
+	if ok {
+		original := op.DeepCopy()
+		if op.Annotations == nil {
+			op.Annotations = map[string]string{}
+		}
+		op.Annotations[ctlutils.AnnotationNameRetry] = value
+		if err := r.client.Patch(ctx, op, client.MergeFrom(original)); err != nil {
+			return reconciler.RequeueWithError(fmt.Errorf("failed to retry operation %s: %w", op.Name, err))
+		}
+		r.logger.Info("retrying failed operation", "operationName", op.Name)
+		r.recorder.Event(r.requirement, "Normal", "Retrying", fmt.Sprintf("Retrying failed operation %s", op.Name))
+		delete(r.requirement.Annotations, ctlutils.AnnotationNameRetry)
+		// the operation is still failed until its app deployments are retried, the requirement is reconciled again
+		// when it changes
+		return reconciler.RequeueOnErrorOrStop(r.client.Update(ctx, r.requirement))
+	}
+	if op.Status.Phase == v1beta1.OperationPhaseFailed {
+		return reconciler.StopProcessing()
+	}
+	r.requirement.Status.Phase = v1beta1.RequirementPhaseOperating
+	meta.RemoveStatusCondition(&r.requirement.Status.Conditions, v1beta1.RequirementConditionOperationReady)
+	return reconciler.RequeueOnErrorOrContinue(r.client.Status().Update(ctx, r.requirement))
+}
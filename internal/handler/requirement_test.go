@@ -8,29 +8,35 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
 	ctlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 	mockpkg "github.com/Azure/operation-cache-controller/internal/utils/mocks"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 	"github.com/Azure/operation-cache-controller/internal/utils/reconciler"
 )
 
 var (
 	cacheutils        = ctlutils.NewCacheHelper()
 	testOperationName = "test-operation"
-	emptyRequirement  = &v1alpha1.Requirement{}
-	validRequirement  = &v1alpha1.Requirement{
-		Spec: v1alpha1.RequirementSpec{
-			ExpireAt: time.Now().Add(time.Hour).Format(time.RFC3339),
-			Template: v1alpha1.OperationSpec{
-				Applications: []v1alpha1.ApplicationSpec{
+	emptyRequirement  = &v1beta1.Requirement{}
+	validRequirement  = &v1beta1.Requirement{
+		Spec: v1beta1.RequirementSpec{
+			CachePolicy: v1beta1.CachePolicyDisabled,
+			ExpireAt:    &metav1.Time{Time: time.Now().Add(time.Hour)},
+			Template: v1beta1.OperationSpec{
+				Applications: []v1beta1.ApplicationSpec{
 					{
 						Name:      "test-app1",
 						Provision: newTestJobSpec(),
@@ -47,8 +53,8 @@ var (
 			},
 		},
 	}
-	validCache = &v1alpha1.Cache{
-		Status: v1alpha1.CacheStatus{
+	validCache = &v1beta1.Cache{
+		Status: v1beta1.CacheStatus{
 			AvailableCaches: []string{"test-cache1", "test-cache2"},
 		},
 	}
@@ -77,10 +83,12 @@ func TestRequirementAdapter_EnsureNotExpired(t *testing.T) {
 	mockClient := mockpkg.NewMockClient(mockCtrl)
 	mockRecorderCtrl := gomock.NewController(t)
 	mockRecorder := mockpkg.NewMockEventRecorder(mockRecorderCtrl)
+	mockStatusWriterCtrl := gomock.NewController(t)
+	mockStatusWriter := mockpkg.NewMockStatusWriter(mockStatusWriterCtrl)
 
 	t.Run("happy path: continue processing when expire is not set", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Spec.ExpireAt = time.Now().Add(time.Hour).Format(time.RFC3339)
+		requirement.Spec.ExpireAt = nil
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureNotExpired(ctx)
 		assert.NoError(t, err)
@@ -88,28 +96,210 @@ func TestRequirementAdapter_EnsureNotExpired(t *testing.T) {
 	})
 	t.Run("happy path: continue processing when requirement is not expired", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Spec.ExpireAt = time.Now().Add(time.Hour).Format(time.RFC3339)
+		requirement.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueWithin: res.RequeueWithin}, res)
+		assert.InDelta(t, time.Hour-config.DefaultExpiringSoonWindow, res.RequeueWithin, float64(time.Second))
+		assert.True(t, ctlutils.TimeEqual(requirement.Spec.ExpireAt, requirement.Status.ExpireAt))
+	})
+	t.Run("happy path: mark requirement expiring soon", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		requirement.Status.ExpireAt = requirement.Spec.ExpireAt.DeepCopy()
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+		mockRecorder.EXPECT().Event(requirement, "Warning", "ExpiringSoon", gomock.Any())
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.InDelta(t, time.Minute, res.RequeueWithin, float64(time.Second))
+		condition := meta.FindStatusCondition(requirement.Status.Conditions, v1beta1.ConditionExpiringSoon)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, v1beta1.ConditionReasonDeadlineApproaching, condition.Reason)
+	})
+	t.Run("happy path: ttl after ready applies once the requirement is ready", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.Spec.ExpireAt = nil
+		requirement.Spec.TTLAfterReady = &metav1.Duration{Duration: time.Hour}
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureNotExpired(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
+		assert.Nil(t, requirement.Status.ExpireAt)
+
+		readyTime := time.Now().Add(-2 * time.Hour)
+		requirement.Status.ReadyTime = &metav1.Time{Time: readyTime}
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+		mockClient.EXPECT().Delete(ctx, requirement, gomock.Any()).Return(nil)
+		res, err = adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{}, res)
+		assert.True(t, ctlutils.TimeEqual(&metav1.Time{Time: readyTime.Add(time.Hour)}, requirement.Status.ExpireAt))
+	})
+	t.Run("happy path: recent access keeps the requirement", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.Spec.ExpireAt = nil
+		requirement.Spec.TTLAfterLastAccess = &metav1.Duration{Duration: time.Hour}
+		requirement.Status.ReadyTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameLastAccess: time.Now().Format(time.RFC3339)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		assert.True(t, requirement.Status.ExpireAt.After(time.Now()))
+	})
+
+	t.Run("happy path: renewal extends the requirement past its expire time", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.CreationTimestamp = metav1.Now()
+		requirement.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		renewUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRenewUntil: renewUntil.Format(time.RFC3339)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		assert.True(t, renewUntil.Equal(requirement.Status.RenewUntil.Time))
+		assert.True(t, renewUntil.Equal(requirement.Status.ExpireAt.Time))
+		require.NotNil(t, requirement.Status.LastHeartbeatTime)
+		heartbeat := requirement.Status.LastHeartbeatTime
+
+		// the same renewal is no new heartbeat
+		res, err = adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		assert.Equal(t, heartbeat, requirement.Status.LastHeartbeatTime)
+	})
+	t.Run("happy path: renewal is capped at the max lifetime", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.CreationTimestamp = metav1.Now()
+		renewUntil := time.Now().Add(2 * config.DefaultLeaseMaxLifetime)
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRenewUntil: renewUntil.Format(time.RFC3339)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+		mockRecorder.EXPECT().Event(requirement, "Warning", "LeaseCapped", gomock.Any())
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		_, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ctlutils.TimeEqual(&metav1.Time{Time: requirement.CreationTimestamp.Add(config.DefaultLeaseMaxLifetime)}, requirement.Status.ExpireAt))
+	})
+	t.Run("happy path: a capped renewal is a heartbeat", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.CreationTimestamp = metav1.Now()
+		maxDeadline := metav1.NewTime(requirement.CreationTimestamp.Add(config.DefaultLeaseMaxLifetime))
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRenewUntil: time.Now().Add(3 * config.DefaultLeaseMaxLifetime).Format(time.RFC3339)}
+		requirement.Status.ObservedRenewUntil = time.Now().Add(2 * config.DefaultLeaseMaxLifetime).Format(time.RFC3339)
+		requirement.Status.RenewUntil = &maxDeadline
+		requirement.Status.ExpireAt = &maxDeadline
 
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+		mockRecorder.EXPECT().Event(requirement, "Warning", "LeaseCapped", gomock.Any())
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		_, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.NotNil(t, requirement.Status.LastHeartbeatTime)
+		assert.Equal(t, requirement.Annotations[ctlutils.AnnotationNameRenewUntil], requirement.Status.ObservedRenewUntil)
+		assert.True(t, ctlutils.TimeEqual(&maxDeadline, requirement.Status.ExpireAt))
 	})
-	t.Run("Sad path: failed to parse expire time", func(t *testing.T) {
+	t.Run("happy path: renewal past the max lifetime keeps the own expiry", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Spec.ExpireAt = "invalid-time"
-		mockRecorder.EXPECT().Event(requirement, "Warning", "InvalidExpireTime", "Failed to parse expire time")
+		requirement.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * config.DefaultLeaseMaxLifetime))
+		expireAt := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+		requirement.Spec.ExpireAt = &expireAt
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRenewUntil: time.Now().Add(2 * time.Hour).Format(time.RFC3339)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+		mockRecorder.EXPECT().Event(requirement, "Warning", "LeaseRejected", gomock.Any())
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		assert.Nil(t, requirement.Status.RenewUntil)
+		assert.True(t, ctlutils.TimeEqual(&expireAt, requirement.Status.ExpireAt))
+		assert.NotNil(t, requirement.Status.LastHeartbeatTime)
+
+		// the rejection is not repeated until the consumer renews again
+		res, err = adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+	})
+	t.Run("happy path: renewal past the max lifetime does not delete a requirement without expiry", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * config.DefaultLeaseMaxLifetime))
+		requirement.Spec.ExpireAt = nil
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRenewUntil: time.Now().Add(time.Hour).Format(time.RFC3339)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(nil)
+		mockRecorder.EXPECT().Event(requirement, "Warning", "LeaseRejected", gomock.Any())
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureNotExpired(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
+		assert.Nil(t, requirement.Status.ExpireAt)
+	})
+	t.Run("happy path: reclaim requirement whose consumer stopped renewing", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		renewUntil := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRenewUntil: renewUntil.Format(time.RFC3339)}
+		requirement.Status.ObservedRenewUntil = renewUntil.Format(time.RFC3339)
+		requirement.Status.RenewUntil = &renewUntil
+		requirement.Status.ExpireAt = &renewUntil
+
+		mockClient.EXPECT().Delete(ctx, requirement, gomock.Any()).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{}, res)
+	})
+	t.Run("happy path: invalid renewal keeps the current lease", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		renewUntil := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRenewUntil: "1h"}
+		requirement.Status.RenewUntil = &renewUntil
+		requirement.Status.ExpireAt = &renewUntil
+
+		mockRecorder.EXPECT().Event(requirement, "Warning", "InvalidRenewUntil", gomock.Any())
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		assert.Equal(t, &renewUntil, requirement.Status.RenewUntil)
 	})
 
 	t.Run("happy path: delete operation when expire time is in the past", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Spec.ExpireAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+		requirement.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		requirement.Status.ExpireAt = requirement.Spec.ExpireAt.DeepCopy()
 
 		mockClient.EXPECT().Delete(ctx, requirement, gomock.Any()).Return(nil)
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
@@ -117,9 +307,22 @@ func TestRequirementAdapter_EnsureNotExpired(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
 	})
+	t.Run("sad path: publishing the expire time failed", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+
+		mockClient.EXPECT().Status().Return(mockStatusWriter)
+		mockStatusWriter.EXPECT().Update(ctx, requirement).Return(assert.AnError)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureNotExpired(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
+	})
 	t.Run("sad path: delete operation failed", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Spec.ExpireAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+		requirement.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		requirement.Status.ExpireAt = requirement.Spec.ExpireAt.DeepCopy()
 
 		mockClient.EXPECT().Delete(ctx, requirement, gomock.Any()).Return(assert.AnError)
 		mockRecorder.EXPECT().Event(requirement, "Warning", "DeleteFailed", "Failed to delete expired requirement")
@@ -151,24 +354,24 @@ func TestRequirementAdapter_EnsureInitialized(t *testing.T) {
 		res, err := adapter.EnsureInitialized(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 	})
 
 	t.Run("happy path: continue processing when requirement is in empty phase and cache enabled", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Spec.EnableCache = true
+		requirement.Spec.CachePolicy = v1beta1.CachePolicyEnabled
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 		res, err := adapter.EnsureInitialized(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
-		assert.Equal(t, v1alpha1.RequirementPhaseCacheChecking, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseCacheChecking, requirement.Status.Phase)
 	})
 
 	t.Run("happy path: continue processing requirement is not in empty phase", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
+		requirement.Status.Phase = v1beta1.RequirementPhaseOperating
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
 		res, err := adapter.EnsureInitialized(ctx)
@@ -200,39 +403,39 @@ func TestRequirementAdapter_EnsureCacheExisted(t *testing.T) {
 
 	t.Run("happy path: continue processing when candidate operation exist", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		requirement.Status.OperationName = testOperationName
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
 		res, err := adapter.EnsureCacheExisted(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{}, res)
-		assert.Equal(t, v1alpha1.RequirementPhaseCacheChecking, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseCacheChecking, requirement.Status.Phase)
 	})
 
 	t.Run("happy path: when get a candidate operation", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		requirement.Status.CacheKey = cacheutils.NewCacheKeyFromApplications(requirement.Spec.Template.Applications)
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 		cache := validCache.DeepCopy()
 
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(cache), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Cache) = *cache
+			*obj.(*v1beta1.Cache) = *cache
 			return nil
 		})
 
-		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1alpha1.Cache{})).Return(nil)
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.Cache{})).Return(nil)
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 		res, err := adapter.EnsureCacheExisted(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
-		assert.Equal(t, v1alpha1.RequirementPhaseCacheChecking, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseCacheChecking, requirement.Status.Phase)
 	})
 	t.Run("sad path: cache key is not set", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		requirement.Status.CacheKey = ""
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
@@ -243,7 +446,7 @@ func TestRequirementAdapter_EnsureCacheExisted(t *testing.T) {
 
 	t.Run("sad path: failed to get cache", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		requirement.Status.CacheKey = cacheutils.NewCacheKeyFromApplications(requirement.Spec.Template.Applications)
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
@@ -258,23 +461,32 @@ func TestRequirementAdapter_EnsureCacheExisted(t *testing.T) {
 
 	t.Run("happy path: cache not found, create a new cache", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		requirement.Status.CacheKey = cacheutils.NewCacheKeyFromApplications(requirement.Spec.Template.Applications)
 		errCacheNotFound := apierrors.NewNotFound(schema.GroupResource{Group: "appsv1", Resource: "Cache"}, "cache not found")
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(errCacheNotFound)
 		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(nil)
-		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		var recorded *v1beta1.Cache
+		mockStatusWriter.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.Cache{})).DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			recorded = obj.(*v1beta1.Cache)
+			return nil
+		})
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(requirement)).Return(nil)
 		res, err := adapter.EnsureCacheExisted(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
+		if assert.NotNil(t, recorded) {
+			assert.Equal(t, "cache-"+requirement.Status.CacheKey, recorded.Name)
+			assert.Equal(t, int32(1), recorded.Status.Demand.Misses)
+		}
 	})
 
 	t.Run("happy path: cache is not available", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		requirement.Status.CacheKey = cacheutils.NewCacheKeyFromApplications(requirement.Spec.Template.Applications)
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
@@ -282,10 +494,10 @@ func TestRequirementAdapter_EnsureCacheExisted(t *testing.T) {
 		cache.Status.AvailableCaches = nil
 
 		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(cache), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Cache) = *cache
+			*obj.(*v1beta1.Cache) = *cache
 			return nil
 		})
-		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1alpha1.Cache{})).Return(nil)
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.Cache{})).Return(nil)
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 		res, err := adapter.EnsureCacheExisted(ctx)
 		assert.NoError(t, err)
@@ -294,6 +506,56 @@ func TestRequirementAdapter_EnsureCacheExisted(t *testing.T) {
 	})
 }
 
+// expectCacheDemandRecorded expects a single hit or miss to be recorded in the status of the cache cr
+func expectCacheDemandRecorded(ctx context.Context, mockClient *mockpkg.MockClient, mockStatusWriter *mockpkg.MockStatusWriter, hit bool) {
+	mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Cache{}), gomock.Any()).Return(nil)
+	mockStatusWriter.EXPECT().Update(ctx, gomock.Cond(func(cache *v1beta1.Cache) bool {
+		if hit {
+			return cache.Status.Demand.Acquisitions == 1 && cache.Status.Demand.Misses == 0
+		}
+		return cache.Status.Demand.Acquisitions == 0 && cache.Status.Demand.Misses == 1
+	})).Return(nil)
+}
+
+// newCachedOperation returns a ready operation owned by the cache of the requirement
+func newCachedOperation(requirement *v1beta1.Requirement, name string) *v1beta1.Operation {
+	operation := validOperation.DeepCopy()
+	operation.Name = name
+	operation.Namespace = requirement.Namespace
+	operation.Status.Phase = v1beta1.OperationPhaseReconciled
+	operation.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: v1beta1.GroupVersion.String(),
+			Kind:       "Cache",
+			Name:       "cache-" + requirement.Status.CacheKey,
+			UID:        "cache-uid",
+			Controller: ptr.Of(true),
+		},
+	}
+	return operation
+}
+
+func expectOperationGet(ctx context.Context, mockClient *mockpkg.MockClient, operation *v1beta1.Operation) {
+	mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: operation.Name, Namespace: operation.Namespace}, gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+		*obj.(*v1beta1.Operation) = *operation.DeepCopy()
+		return nil
+	})
+}
+
+func expectCacheGet(ctx context.Context, mockClient *mockpkg.MockClient, cache *v1beta1.Cache) {
+	mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Cache{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+		*obj.(*v1beta1.Cache) = *cache.DeepCopy()
+		return nil
+	})
+}
+
+// expectFinalizerAdded expects the finalizer returning or deleting the acquired operation to be added to the requirement
+func expectFinalizerAdded(ctx context.Context, mockClient *mockpkg.MockClient) {
+	mockClient.EXPECT().Update(ctx, gomock.Cond(func(r *v1beta1.Requirement) bool {
+		return controllerutil.ContainsFinalizer(r, v1beta1.RequirementFinalizerName)
+	})).Return(nil)
+}
+
 func TestRequirementAdapter_EnsureCachedOperationAcquired(t *testing.T) {
 	ctx := context.Background()
 	logger := log.FromContext(ctx)
@@ -319,24 +581,25 @@ func TestRequirementAdapter_EnsureCachedOperationAcquired(t *testing.T) {
 	t.Run("happy path: continue processing when operation is not set", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = ""
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
-		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		expectCacheDemandRecorded(ctx, mockClient, mockStatusWriter, false)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(requirement)).Return(nil)
 		res, err := adapter.EnsureCachedOperationAcquired(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 	})
 
 	t.Run("happy path: continue processing when operation is already acquired", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.UID = testRequirementUID
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		operation := validOperation.DeepCopy()
 		operation.Annotations = map[string]string{
-			v1alpha1.OperationAcquiredAnnotationKey: "2021-09-01T00:00:00Z",
+			v1beta1.OperationAcquiredAnnotationKey: "2021-09-01T00:00:00Z",
 		}
 		operation.OwnerReferences = []metav1.OwnerReference{
 			{
@@ -347,8 +610,8 @@ func TestRequirementAdapter_EnsureCachedOperationAcquired(t *testing.T) {
 			},
 		}
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Operation) = *operation
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operation
 			return nil
 		})
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
@@ -357,7 +620,8 @@ func TestRequirementAdapter_EnsureCachedOperationAcquired(t *testing.T) {
 
 		res, err := adapter.EnsureCachedOperationAcquired(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, v1alpha1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.NotNil(t, requirement.Status.ReadyTime)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, CancelRequest: true}, res)
 	})
 
@@ -365,10 +629,10 @@ func TestRequirementAdapter_EnsureCachedOperationAcquired(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.UID = testRequirementUID
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		operation := validOperation.DeepCopy()
 		operation.Annotations = map[string]string{
-			v1alpha1.OperationAcquiredAnnotationKey: "2021-09-01T00:00:00Z",
+			v1beta1.OperationAcquiredAnnotationKey: "2021-09-01T00:00:00Z",
 		}
 		operation.OwnerReferences = []metav1.OwnerReference{
 			{
@@ -379,55 +643,156 @@ func TestRequirementAdapter_EnsureCachedOperationAcquired(t *testing.T) {
 			},
 		}
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Operation) = *operation
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operation
 			return nil
 		})
-		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		// no other operation left in the cache
+		expectCacheGet(ctx, mockClient, &v1beta1.Cache{Status: v1beta1.CacheStatus{AvailableCaches: []string{testOperationName}}})
+		expectCacheDemandRecorded(ctx, mockClient, mockStatusWriter, false)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(requirement)).Return(nil)
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
 		res, err := adapter.EnsureCachedOperationAcquired(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Empty(t, requirement.Status.OperationName)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
 	})
 
-	t.Run("happy path: continue processing when operation is not acquired, acquired it with success", func(t *testing.T) {
+	t.Run("happy path: acquire the next available operation when the selected one is acquired by other requirement", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.UID = testRequirementUID
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
+		taken := newCachedOperation(requirement, testOperationName)
+		taken.Annotations = map[string]string{
+			v1beta1.OperationAcquiredAnnotationKey: "2021-09-01T00:00:00Z",
+		}
+		next := newCachedOperation(requirement, "next-operation")
+
+		expectOperationGet(ctx, mockClient, taken)
+		expectCacheGet(ctx, mockClient, &v1beta1.Cache{Status: v1beta1.CacheStatus{AvailableCaches: []string{testOperationName, next.Name}}})
+		expectOperationGet(ctx, mockClient, next)
+		mockClient.EXPECT().Patch(ctx, gomock.Cond(func(op *v1beta1.Operation) bool {
+			return op.Name == next.Name && op.OwnerReferences[0].UID == testRequirementUID
+		}), gomock.Any()).Return(nil)
+		expectCacheDemandRecorded(ctx, mockClient, mockStatusWriter, true)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(requirement)).Return(nil)
+
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
-		operation := validOperation.DeepCopy()
-		operation.Annotations = map[string]string{}
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Operation) = *operation
-			return nil
+		res, err := adapter.EnsureCachedOperationAcquired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, next.Name, requirement.Status.OperationName)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+	})
+
+	t.Run("happy path: acquire the next available operation when losing the race on the selected one", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.UID = testRequirementUID
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
+		selected := newCachedOperation(requirement, testOperationName)
+		next := newCachedOperation(requirement, "next-operation")
+		conflict := apierrors.NewConflict(schema.GroupResource{Group: v1beta1.GroupVersion.Group, Resource: "operations"}, selected.Name, assert.AnError)
+
+		expectOperationGet(ctx, mockClient, selected)
+		expectCacheGet(ctx, mockClient, &v1beta1.Cache{
+			Spec:   v1beta1.CacheSpec{Recycle: &v1beta1.CacheRecycle{}},
+			Status: v1beta1.CacheStatus{AvailableCaches: []string{selected.Name, next.Name}},
 		})
-		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1alpha1.Operation{})).Return(nil)
-		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		// the finalizer returning the operation to the recycling cache is added once
+		expectFinalizerAdded(ctx, mockClient)
+		mockClient.EXPECT().Patch(ctx, gomock.Cond(func(op *v1beta1.Operation) bool { return op.Name == selected.Name }), gomock.Any()).Return(conflict)
+		expectOperationGet(ctx, mockClient, next)
+		mockClient.EXPECT().Patch(ctx, gomock.Cond(func(op *v1beta1.Operation) bool { return op.Name == next.Name }), gomock.Any()).Return(nil)
+		expectCacheDemandRecorded(ctx, mockClient, mockStatusWriter, true)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+
+		res, err := adapter.EnsureCachedOperationAcquired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, next.Name, requirement.Status.OperationName)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+	})
+
+	t.Run("happy path: cache miss when every candidate is lost to other requirements", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.UID = testRequirementUID
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
+		selected := newCachedOperation(requirement, testOperationName)
+		notReady := newCachedOperation(requirement, "not-ready-operation")
+		notReady.Status.Phase = v1beta1.OperationPhaseReconciling
+		released := newCachedOperation(requirement, "released-operation")
+		released.Annotations = map[string]string{v1beta1.OperationReleasedAnnotationKey: time.Now().Format(time.RFC3339)}
+		conflict := apierrors.NewConflict(schema.GroupResource{Group: v1beta1.GroupVersion.Group, Resource: "operations"}, selected.Name, assert.AnError)
+
+		expectOperationGet(ctx, mockClient, selected)
+		mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(conflict)
+		expectCacheGet(ctx, mockClient, &v1beta1.Cache{Status: v1beta1.CacheStatus{AvailableCaches: []string{selected.Name, "deleted-operation", notReady.Name, released.Name}}})
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "deleted-operation", Namespace: requirement.Namespace}, gomock.Any(), gomock.Any()).
+			Return(apierrors.NewNotFound(schema.GroupResource{}, "deleted-operation"))
+		expectOperationGet(ctx, mockClient, notReady)
+		expectOperationGet(ctx, mockClient, released)
+		expectCacheDemandRecorded(ctx, mockClient, mockStatusWriter, false)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+
+		res, err := adapter.EnsureCachedOperationAcquired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Empty(t, requirement.Status.OperationName)
+		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
+	})
+
+	t.Run("happy path: continue processing when operation is not acquired, acquired it with success", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.UID = testRequirementUID
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Status.Outputs = []v1beta1.ApplicationOutputs{{Name: "test-app1", SecretName: "test-app1-outputs"}}
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, validCache)
+		mockClient.EXPECT().Patch(ctx, gomock.Cond(func(op *v1beta1.Operation) bool {
+			_, acquired := op.Annotations[v1beta1.OperationAcquiredAnnotationKey]
+			return acquired && len(op.OwnerReferences) == 1 && op.OwnerReferences[0].UID == testRequirementUID &&
+				op.OwnerReferences[0].Kind == "Requirement"
+		}), gomock.Any()).Return(nil)
+		expectCacheDemandRecorded(ctx, mockClient, mockStatusWriter, true)
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.AssignableToTypeOf(requirement)).Return(nil)
 
 		res, err := adapter.EnsureCachedOperationAcquired(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
 		assert.Equal(t, operation.Name, requirement.Status.OperationName)
-		assert.Equal(t, v1alpha1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, operation.Status.Outputs, requirement.Status.Outputs)
+		// the owned operation is garbage collected with the requirement, no finalizer is needed
+		assert.False(t, controllerutil.ContainsFinalizer(requirement, v1beta1.RequirementFinalizerName))
 	})
 
 	t.Run("sad path: failed to get operation", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.UID = testRequirementUID
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).Return(assert.AnError)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).Return(assert.AnError)
 
 		res, err := adapter.EnsureCachedOperationAcquired(ctx)
 		assert.ErrorIs(t, err, assert.AnError)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
 	})
 
@@ -435,21 +800,18 @@ func TestRequirementAdapter_EnsureCachedOperationAcquired(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.UID = testRequirementUID
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseCacheChecking
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
-		operation := validOperation.DeepCopy()
-		operation.Annotations = map[string]string{}
+		operation := newCachedOperation(requirement, testOperationName)
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Operation) = *operation
-			return nil
-		})
-		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1alpha1.Operation{})).Return(assert.AnError)
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, validCache)
+		mockClient.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).Return(assert.AnError)
 
 		res, err := adapter.EnsureCachedOperationAcquired(ctx)
 		assert.Error(t, err)
 		assert.Equal(t, requirement.Status.OperationName, operation.Name)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
 	})
 }
@@ -479,7 +841,7 @@ func TestRequirementAdapter_EnsureOperationReady(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = testOperationName
 		requirement.Status.CacheKey = cacheutils.NewCacheKeyFromApplications(requirement.Spec.Template.Applications)
-		requirement.Status.Phase = v1alpha1.RequirementPhaseReady
+		requirement.Status.Phase = v1beta1.RequirementPhaseReady
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
 		res, err := adapter.EnsureOperationReady(ctx)
@@ -490,47 +852,47 @@ func TestRequirementAdapter_EnsureOperationReady(t *testing.T) {
 	t.Run("happy path: ready phase but cachekey is changed", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseReady
+		requirement.Status.Phase = v1beta1.RequirementPhaseReady
 		requirement.Status.CacheKey = "test-cache-key"
 		operaition := validOperation.DeepCopy()
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Operation) = *operaition
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operaition
 			return nil
 		})
-		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1alpha1.Operation{})).Return(nil)
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.Operation{})).Return(nil)
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 		res, err := adapter.EnsureOperationReady(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
 	})
 	t.Run("sad path: failed to get operation", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseReady
+		requirement.Status.Phase = v1beta1.RequirementPhaseReady
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).Return(assert.AnError)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).Return(assert.AnError)
 
 		res, err := adapter.EnsureOperationReady(ctx)
 		assert.ErrorIs(t, err, assert.AnError)
-		assert.Equal(t, v1alpha1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
 	})
 
 	t.Run("happy path: continue processing when operation is not ready", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
+		requirement.Status.Phase = v1beta1.RequirementPhaseOperating
 
 		operation := validOperation.DeepCopy()
-		operation.Status.Phase = v1alpha1.OperationPhaseReconciling
+		operation.Status.Phase = v1beta1.OperationPhaseReconciling
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Operation) = *operation
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operation
 			return nil
 		})
 
@@ -538,19 +900,20 @@ func TestRequirementAdapter_EnsureOperationReady(t *testing.T) {
 
 		res, err := adapter.EnsureOperationReady(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
 	})
 
 	t.Run("happy path: continue processing when operation is ready", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
+		requirement.Status.Phase = v1beta1.RequirementPhaseOperating
 		operation := validOperation.DeepCopy()
-		operation.Status.Phase = v1alpha1.OperationPhaseReconciled
+		operation.Status.Phase = v1beta1.OperationPhaseReconciled
+		operation.Status.Outputs = []v1beta1.ApplicationOutputs{{Name: "test-app1", Outputs: map[string]string{"endpoint": "https://example.com"}}}
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
-			*obj.(*v1alpha1.Operation) = *operation
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operation
 			return nil
 		})
 		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
@@ -559,42 +922,494 @@ func TestRequirementAdapter_EnsureOperationReady(t *testing.T) {
 
 		res, err := adapter.EnsureOperationReady(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, v1alpha1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, operation.Status.Outputs, requirement.Status.Outputs)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay}, res)
 	})
 
+	t.Run("sad path: fail the requirement when the operation failed", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.Phase = v1beta1.RequirementPhaseOperating
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseFailed
+		operation.Status.Conditions = []metav1.Condition{{
+			Type:    v1beta1.OperationConditionAppsFailed,
+			Status:  metav1.ConditionTrue,
+			Reason:  v1beta1.OperationConditionReasonProvisionFailed,
+			Message: "application test-app2: job provision-test-app2 failed",
+		}}
+
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operation
+			return nil
+		})
+		mockRecorder.EXPECT().Event(requirement, "Warning", "OperationFailed", gomock.Any())
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureOperationReady(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.CancelRequest)
+		assert.Equal(t, v1beta1.RequirementPhaseFailed, requirement.Status.Phase)
+		condition := meta.FindStatusCondition(requirement.Status.Conditions, v1beta1.RequirementConditionOperationReady)
+		require.NotNil(t, condition)
+		assert.Equal(t, v1beta1.RequirementConditionReasonOperationFailed, condition.Reason)
+		assert.Contains(t, condition.Message, "job provision-test-app2 failed")
+	})
+
+	t.Run("happy path: failed requirement annotated to retry retries its operation", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.Annotations = map[string]string{ctlutils.AnnotationNameRetry: "true"}
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.Phase = v1beta1.RequirementPhaseFailed
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseFailed
+
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operation
+			return nil
+		})
+		mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, obj *v1beta1.Operation, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(t, "true", obj.Annotations[ctlutils.AnnotationNameRetry])
+			return nil
+		})
+		mockRecorder.EXPECT().Event(requirement, "Normal", "Retrying", gomock.Any())
+		mockClient.EXPECT().Update(ctx, requirement).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureOperationReady(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.CancelRequest)
+		assert.NotContains(t, requirement.Annotations, ctlutils.AnnotationNameRetry)
+		assert.Equal(t, v1beta1.RequirementPhaseFailed, requirement.Status.Phase)
+	})
+
+	t.Run("happy path: failed requirement operates again once its operation is retried", func(t *testing.T) {
+		requirement := validRequirement.DeepCopy()
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.Phase = v1beta1.RequirementPhaseFailed
+		operation := validOperation.DeepCopy()
+		operation.Status.Phase = v1beta1.OperationPhaseReconciling
+
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Operation) = *operation
+			return nil
+		})
+		mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		res, err := adapter.EnsureOperationReady(ctx)
+		assert.NoError(t, err)
+		assert.False(t, res.RequeueOrCancel())
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
+	})
+
 	t.Run("happy path: operation not found, create one", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
+		requirement.Status.Phase = v1beta1.RequirementPhaseOperating
 		scheme := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(scheme)
+		_ = v1beta1.AddToScheme(scheme)
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{Group: "appsv1", Resource: "Operation"}, "operation not found"))
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{Group: "appsv1", Resource: "Operation"}, "operation not found"))
 		mockClient.EXPECT().Scheme().Return(scheme)
 		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(nil)
 
 		res, err := adapter.EnsureOperationReady(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
 	})
 
 	t.Run("sad path: failed to create operation", func(t *testing.T) {
 		requirement := validRequirement.DeepCopy()
 		requirement.Status.OperationName = testOperationName
-		requirement.Status.Phase = v1alpha1.RequirementPhaseOperating
+		requirement.Status.Phase = v1beta1.RequirementPhaseOperating
 		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
 		schema := runtime.NewScheme()
-		_ = v1alpha1.AddToScheme(schema)
-		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.Operation{}), gomock.Any()).Return(assert.AnError)
+		_ = v1beta1.AddToScheme(schema)
+		mockClient.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.Operation{}), gomock.Any()).Return(assert.AnError)
 		mockClient.EXPECT().Scheme().Return(schema)
 		mockClient.EXPECT().Create(ctx, gomock.Any()).Return(assert.AnError)
 
 		res, err := adapter.EnsureOperationReady(ctx)
 		assert.ErrorIs(t, err, assert.AnError)
-		assert.Equal(t, v1alpha1.RequirementPhaseOperating, requirement.Status.Phase)
+		assert.Equal(t, v1beta1.RequirementPhaseOperating, requirement.Status.Phase)
 		assert.Equal(t, reconciler.OperationResult{RequeueDelay: reconciler.DefaultRequeueDelay, RequeueRequest: true}, res)
 	})
 }
+
+func TestRequirementAdapter_CachePool(t *testing.T) {
+	ctx := context.Background()
+	logger := log.FromContext(ctx)
+
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockpkg.NewMockClient(mockCtrl)
+	mockRecorder := mockpkg.NewMockEventRecorder(gomock.NewController(t))
+	mockStatusWriter := mockpkg.NewMockStatusWriter(gomock.NewController(t))
+	mockClient.EXPECT().Status().Return(mockStatusWriter).AnyTimes()
+
+	pooled := config.Default()
+	pooled.CachePool = config.CachePoolConfig{Namespace: "pool", AllowedNamespaces: []string{"team-a"}}
+	config.Set(pooled)
+	t.Cleanup(func() { config.Set(config.Default()) })
+
+	newPoolRequirement := func(namespace string) *v1beta1.Requirement {
+		requirement := validRequirement.DeepCopy()
+		requirement.Name = "test-requirement"
+		requirement.Namespace = namespace
+		requirement.UID = "test-uid"
+		requirement.Status.Phase = v1beta1.RequirementPhaseCacheChecking
+		requirement.Status.CacheKey = cacheutils.NewCacheKeyFromApplications(requirement.Spec.Template.Applications)
+		return requirement
+	}
+
+	t.Run("happy path: allowed namespace selects a candidate from the cache pool", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+
+		cache := validCache.DeepCopy()
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "cache-" + requirement.Status.CacheKey, Namespace: "pool"}, gomock.AssignableToTypeOf(cache), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			*obj.(*v1beta1.Cache) = *cache
+			return nil
+		})
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.Cache{})).Return(nil)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureCacheExisted(ctx)
+		assert.NoError(t, err)
+		assert.Contains(t, cache.Status.AvailableCaches, requirement.Status.OperationName)
+		assert.Equal(t, "pool", requirement.Status.OperationNamespace)
+	})
+
+	t.Run("happy path: other namespaces keep their own cache", func(t *testing.T) {
+		requirement := newPoolRequirement("team-b")
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "cache-" + requirement.Status.CacheKey, Namespace: "team-b"}, gomock.AssignableToTypeOf(&v1beta1.Cache{}), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.Cache{})).Return(nil)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureCacheExisted(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, requirement.Status.OperationNamespace)
+	})
+
+	t.Run("happy path: acquire an operation of the cache pool without owning it", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.OperationNamespace = "pool"
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Namespace = "pool"
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, validCache)
+		expectFinalizerAdded(ctx, mockClient)
+		mockClient.EXPECT().Patch(ctx, gomock.Cond(func(op *v1beta1.Operation) bool {
+			_, acquired := op.Annotations[v1beta1.OperationAcquiredAnnotationKey]
+			return acquired && op.Annotations[v1beta1.OperationAcquiredByAnnotationKey] == "team-a/test-requirement" &&
+				len(op.OwnerReferences) == 0
+		}), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "cache-" + requirement.Status.CacheKey, Namespace: "pool"}, gomock.AssignableToTypeOf(&v1beta1.Cache{}), gomock.Any()).Return(nil)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.AssignableToTypeOf(&v1beta1.Cache{})).Return(nil)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureCachedOperationAcquired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, "pool", requirement.Status.OperationNamespace)
+	})
+
+	t.Run("happy path: secret outputs of an operation of the cache pool are copied to the requirement namespace", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.OperationNamespace = "pool"
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Namespace = "pool"
+		operation.OwnerReferences = nil
+		operation.Annotations = map[string]string{
+			v1beta1.OperationAcquiredAnnotationKey:   time.Now().Format(time.RFC3339),
+			v1beta1.OperationAcquiredByAnnotationKey: "team-a/test-requirement",
+		}
+		operation.Status.Outputs = []v1beta1.ApplicationOutputs{
+			{Name: "db", Outputs: map[string]string{"host": "db.example.com"}, SecretName: "op-db-outputs"},
+			{Name: "web", Outputs: map[string]string{"endpoint": "https://example.com"}},
+		}
+		scheme := runtime.NewScheme()
+		_ = v1beta1.AddToScheme(scheme)
+
+		expectOperationGet(ctx, mockClient, operation)
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "op-db-outputs", Namespace: "pool"}, gomock.AssignableToTypeOf(&corev1.Secret{}), gomock.Any()).DoAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{"password": []byte("p@ss")}
+			return nil
+		})
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "test-requirement-db-outputs", Namespace: "team-a"}, gomock.AssignableToTypeOf(&corev1.Secret{}), gomock.Any()).
+			Return(apierrors.NewNotFound(corev1.Resource("secret"), "test-requirement-db-outputs"))
+		mockClient.EXPECT().Scheme().Return(scheme)
+		mockClient.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+			secret := obj.(*corev1.Secret)
+			assert.Equal(t, "team-a", secret.Namespace)
+			assert.Equal(t, []byte("p@ss"), secret.Data["password"])
+			if assert.Len(t, secret.OwnerReferences, 1) {
+				assert.Equal(t, requirement.UID, secret.OwnerReferences[0].UID)
+			}
+			return nil
+		})
+		mockStatusWriter.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureCachedOperationAcquired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+		assert.Equal(t, []v1beta1.ApplicationOutputs{
+			{Name: "db", Outputs: map[string]string{"host": "db.example.com"}, SecretName: "test-requirement-db-outputs"},
+			{Name: "web", Outputs: map[string]string{"endpoint": "https://example.com"}},
+		}, requirement.Status.Outputs)
+		// the outputs of the operation are left untouched
+		assert.Equal(t, "op-db-outputs", operation.Status.Outputs[0].SecretName)
+	})
+
+	t.Run("sad path: failed to copy the secret outputs of an operation of the cache pool", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.OperationNamespace = "pool"
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Namespace = "pool"
+		operation.OwnerReferences = nil
+		operation.Annotations = map[string]string{
+			v1beta1.OperationAcquiredAnnotationKey:   time.Now().Format(time.RFC3339),
+			v1beta1.OperationAcquiredByAnnotationKey: "team-a/test-requirement",
+		}
+		operation.Status.Outputs = []v1beta1.ApplicationOutputs{{Name: "db", SecretName: "op-db-outputs"}}
+
+		expectOperationGet(ctx, mockClient, operation)
+		mockClient.EXPECT().Get(ctx, types.NamespacedName{Name: "op-db-outputs", Namespace: "pool"}, gomock.AssignableToTypeOf(&corev1.Secret{}), gomock.Any()).Return(assert.AnError)
+
+		res, err := adapter.EnsureCachedOperationAcquired(ctx)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, res.RequeueRequest)
+		assert.Equal(t, v1beta1.RequirementPhaseCacheChecking, requirement.Status.Phase)
+	})
+
+	t.Run("happy path: an operation acquired from the cache pool is recognized by its annotation", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.OperationNamespace = "pool"
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Namespace = "pool"
+		operation.OwnerReferences = nil
+		operation.Annotations = map[string]string{
+			v1beta1.OperationAcquiredAnnotationKey:   time.Now().Format(time.RFC3339),
+			v1beta1.OperationAcquiredByAnnotationKey: "team-a/test-requirement",
+		}
+
+		expectOperationGet(ctx, mockClient, operation)
+		mockStatusWriter.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		res, err := adapter.EnsureCachedOperationAcquired(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.CancelRequest)
+		assert.Equal(t, v1beta1.RequirementPhaseReady, requirement.Status.Phase)
+	})
+
+	t.Run("happy path: continue processing when the requirement is not deleted", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		controllerutil.AddFinalizer(requirement, v1beta1.RequirementFinalizerName)
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+
+		res, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reconciler.OperationResult{}, res)
+	})
+
+	t.Run("happy path: deleted requirement deletes the operation it acquired from the cache pool", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		requirement.DeletionTimestamp = ptr.Of(metav1.Now())
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.OperationNamespace = "pool"
+		controllerutil.AddFinalizer(requirement, v1beta1.RequirementFinalizerName)
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Namespace = "pool"
+		operation.OwnerReferences = nil
+		operation.Annotations = map[string]string{
+			v1beta1.OperationAcquiredAnnotationKey:   time.Now().Format(time.RFC3339),
+			v1beta1.OperationAcquiredByAnnotationKey: "team-a/test-requirement",
+		}
+
+		expectOperationGet(ctx, mockClient, operation)
+		// the cache does not recycle operations
+		expectCacheGet(ctx, mockClient, validCache)
+		mockClient.EXPECT().Delete(ctx, gomock.Cond(func(op *v1beta1.Operation) bool {
+			return op.Name == testOperationName && op.Namespace == "pool"
+		}), gomock.Any()).Return(nil)
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		res, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.CancelRequest)
+		assert.False(t, controllerutil.ContainsFinalizer(requirement, v1beta1.RequirementFinalizerName))
+	})
+
+	t.Run("happy path: deleted requirement leaves an operation it did not acquire in the cache pool", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		requirement.DeletionTimestamp = ptr.Of(metav1.Now())
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.OperationNamespace = "pool"
+		controllerutil.AddFinalizer(requirement, v1beta1.RequirementFinalizerName)
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Namespace = "pool"
+
+		expectOperationGet(ctx, mockClient, operation)
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.NoError(t, err)
+		assert.False(t, controllerutil.ContainsFinalizer(requirement, v1beta1.RequirementFinalizerName))
+	})
+
+	t.Run("sad path: failed to delete the operation acquired from the cache pool", func(t *testing.T) {
+		requirement := newPoolRequirement("team-a")
+		requirement.DeletionTimestamp = ptr.Of(metav1.Now())
+		requirement.Status.OperationName = testOperationName
+		requirement.Status.OperationNamespace = "pool"
+		controllerutil.AddFinalizer(requirement, v1beta1.RequirementFinalizerName)
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Namespace = "pool"
+		operation.OwnerReferences = nil
+		operation.Annotations = map[string]string{
+			v1beta1.OperationAcquiredAnnotationKey:   time.Now().Format(time.RFC3339),
+			v1beta1.OperationAcquiredByAnnotationKey: "team-a/test-requirement",
+		}
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, validCache)
+		mockClient.EXPECT().Delete(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewForbidden(schema.GroupResource{}, testOperationName, nil))
+
+		_, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.Error(t, err)
+		assert.True(t, controllerutil.ContainsFinalizer(requirement, v1beta1.RequirementFinalizerName))
+	})
+}
+
+func TestRequirementAdapter_RecycleOperation(t *testing.T) {
+	ctx := context.Background()
+	logger := log.FromContext(ctx)
+
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockpkg.NewMockClient(mockCtrl)
+	mockRecorder := mockpkg.NewMockEventRecorder(gomock.NewController(t))
+
+	newDeletedRequirement := func() *v1beta1.Requirement {
+		requirement := validRequirement.DeepCopy()
+		requirement.Name = "test-requirement"
+		requirement.Namespace = "default"
+		requirement.UID = "test-uid"
+		requirement.DeletionTimestamp = ptr.Of(metav1.Now())
+		requirement.Status.CacheKey = cacheutils.NewCacheKeyFromApplications(requirement.Spec.Template.Applications)
+		requirement.Status.OperationName = testOperationName
+		controllerutil.AddFinalizer(requirement, v1beta1.RequirementFinalizerName)
+		return requirement
+	}
+	newAcquiredOperation := func(requirement *v1beta1.Requirement) *v1beta1.Operation {
+		operation := newCachedOperation(requirement, testOperationName)
+		operation.Annotations = map[string]string{v1beta1.OperationAcquiredAnnotationKey: time.Now().Format(time.RFC3339)}
+		operation.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(requirement, v1beta1.GroupVersion.WithKind("Requirement"))}
+		operation.Status.CacheKey = requirement.Status.CacheKey
+		return operation
+	}
+	newRecyclingCache := func(requirement *v1beta1.Requirement) *v1beta1.Cache {
+		return &v1beta1.Cache{
+			ObjectMeta: metav1.ObjectMeta{Name: "cache-" + requirement.Status.CacheKey, Namespace: requirement.Namespace, UID: "cache-uid"},
+			Spec:       v1beta1.CacheSpec{Recycle: &v1beta1.CacheRecycle{Reset: newTestJobSpec(), MaxReuse: ptr.Of(int32(2))}},
+			Status:     v1beta1.CacheStatus{CacheKey: requirement.Status.CacheKey},
+		}
+	}
+
+	t.Run("happy path: deleted requirement returns the operation to a recycling cache", func(t *testing.T) {
+		requirement := newDeletedRequirement()
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newAcquiredOperation(requirement)
+		operation.Status.ReuseCount = 1
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, newRecyclingCache(requirement))
+		mockClient.EXPECT().Patch(ctx, gomock.Cond(func(op *v1beta1.Operation) bool {
+			_, acquired := op.Annotations[v1beta1.OperationAcquiredAnnotationKey]
+			_, released := op.Annotations[v1beta1.OperationReleasedAnnotationKey]
+			owner := metav1.GetControllerOf(op)
+			return !acquired && released && owner != nil && owner.Kind == "Cache" && owner.UID == "cache-uid"
+		}), gomock.Any()).Return(nil)
+		mockRecorder.EXPECT().Event(requirement, "Normal", "OperationReleased", gomock.Any())
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		res, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.NoError(t, err)
+		assert.True(t, res.CancelRequest)
+		assert.False(t, controllerutil.ContainsFinalizer(requirement, v1beta1.RequirementFinalizerName))
+	})
+
+	t.Run("happy path: operation reused as often as the cache allows is left to the garbage collector", func(t *testing.T) {
+		requirement := newDeletedRequirement()
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newAcquiredOperation(requirement)
+		operation.Status.ReuseCount = 2
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, newRecyclingCache(requirement))
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.NoError(t, err)
+		assert.False(t, controllerutil.ContainsFinalizer(requirement, v1beta1.RequirementFinalizerName))
+	})
+
+	t.Run("happy path: failed operation is not returned", func(t *testing.T) {
+		requirement := newDeletedRequirement()
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newAcquiredOperation(requirement)
+		operation.Status.Phase = v1beta1.OperationPhaseFailed
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, newRecyclingCache(requirement))
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("happy path: operation whose applications changed is not returned", func(t *testing.T) {
+		requirement := newDeletedRequirement()
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newAcquiredOperation(requirement)
+		operation.Status.CacheKey = "other-cache-key"
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, newRecyclingCache(requirement))
+		mockClient.EXPECT().Update(ctx, gomock.AssignableToTypeOf(requirement)).Return(nil)
+
+		_, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("sad path: failed to return the operation", func(t *testing.T) {
+		requirement := newDeletedRequirement()
+		adapter := NewRequirementHandler(ctx, requirement, logger, mockClient, mockRecorder)
+		operation := newAcquiredOperation(requirement)
+
+		expectOperationGet(ctx, mockClient, operation)
+		expectCacheGet(ctx, mockClient, newRecyclingCache(requirement))
+		mockClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		_, err := adapter.EnsureFinalizerDeleted(ctx)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, controllerutil.ContainsFinalizer(requirement, v1beta1.RequirementFinalizerName))
+	})
+}
@@ -0,0 +1,183 @@
+// Package metrics defines the prometheus metrics of the operation cache controller. They are registered to the
+// controller-runtime registry and served on the metrics endpoint of the manager.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "operation_cache_controller"
+
+	LabelNamespace = "namespace"
+	LabelCache     = "cache"
+	LabelCacheKey  = "cache_key"
+	LabelResult    = "result"
+	LabelJobType   = "job_type"
+
+	ResultHit       = "hit"
+	ResultMiss      = "miss"
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+)
+
+var (
+	// CacheHitsTotal counts the requirements which acquired a cached operation
+	CacheHitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "hits_total",
+		Help:      "Number of requirements which acquired a cached operation.",
+	}, []string{LabelNamespace, LabelCacheKey})
+
+	// CacheMissesTotal counts the requirements which had to create their own operation
+	CacheMissesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "misses_total",
+		Help:      "Number of requirements which found no cached operation and created their own.",
+	}, []string{LabelNamespace, LabelCacheKey})
+
+	// CacheKeepAliveOperations is the keepAliveCount of each cache
+	CacheKeepAliveOperations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "keepalive_operations",
+		Help:      "Number of operations the cache wants to keep alive.",
+	}, []string{LabelNamespace, LabelCache})
+
+	// CacheReadyOperations is the number of ready operations available in each cache
+	CacheReadyOperations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "ready_operations",
+		Help:      "Number of ready operations available in the cache.",
+	}, []string{LabelNamespace, LabelCache})
+
+	// CacheRecycledOperationsTotal counts the stuck cached operations deleted and replaced by each cache
+	CacheRecycledOperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "recycled_operations_total",
+		Help:      "Number of cached operations deleted and replaced because they were not ready within the provision timeout.",
+	}, []string{LabelNamespace, LabelCache})
+
+	// RequirementTimeToReadySeconds observes how long requirements take from creation to ready, by cache result
+	RequirementTimeToReadySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "requirement",
+		Name:      "time_to_ready_seconds",
+		Help:      "Time from the creation of a requirement until it is ready.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{LabelResult})
+
+	// OperationProvisionDurationSeconds observes how long operations take from creation to reconciled
+	OperationProvisionDurationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "operation",
+		Name:      "provision_duration_seconds",
+		Help:      "Time from the creation of an operation until all its applications are ready.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	})
+
+	// AppDeploymentJobDurationSeconds observes the run time of finished provision and teardown jobs
+	AppDeploymentJobDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "appdeployment",
+		Name:      "job_duration_seconds",
+		Help:      "Run time of finished provision and teardown jobs.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{LabelJobType, LabelResult})
+
+	// AppDeploymentJobFailuresTotal counts failed provision and teardown jobs
+	AppDeploymentJobFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "appdeployment",
+		Name:      "job_failures_total",
+		Help:      "Number of failed provision and teardown jobs.",
+	}, []string{LabelJobType})
+
+	// AppDeploymentJobRetriesTotal counts the jobs recreated after a failure
+	AppDeploymentJobRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "appdeployment",
+		Name:      "job_retries_total",
+		Help:      "Number of jobs recreated after a failure.",
+	}, []string{LabelJobType})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		CacheHitsTotal,
+		CacheMissesTotal,
+		CacheKeepAliveOperations,
+		CacheReadyOperations,
+		CacheRecycledOperationsTotal,
+		RequirementTimeToReadySeconds,
+		OperationProvisionDurationSeconds,
+		AppDeploymentJobDurationSeconds,
+		AppDeploymentJobFailuresTotal,
+		AppDeploymentJobRetriesTotal,
+	)
+}
+
+// RecordCacheResult counts a cache hit or a cache miss of a requirement
+func RecordCacheResult(namespace, cacheKey string, hit bool) {
+	if hit {
+		CacheHitsTotal.WithLabelValues(namespace, cacheKey).Inc()
+		return
+	}
+	CacheMissesTotal.WithLabelValues(namespace, cacheKey).Inc()
+}
+
+// SetCachePool records the keepAliveCount and the ready operations of a cache
+func SetCachePool(namespace, cache string, keepAlive, ready int) {
+	CacheKeepAliveOperations.WithLabelValues(namespace, cache).Set(float64(keepAlive))
+	CacheReadyOperations.WithLabelValues(namespace, cache).Set(float64(ready))
+}
+
+// DeleteCachePool removes the gauges of a deleted cache
+func DeleteCachePool(namespace, cache string) {
+	CacheKeepAliveOperations.DeleteLabelValues(namespace, cache)
+	CacheReadyOperations.DeleteLabelValues(namespace, cache)
+	CacheRecycledOperationsTotal.DeleteLabelValues(namespace, cache)
+}
+
+// RecordRecycledOperations counts stuck cached operations replaced by a cache
+func RecordRecycledOperations(namespace, cache string, count int) {
+	CacheRecycledOperationsTotal.WithLabelValues(namespace, cache).Add(float64(count))
+}
+
+// ObserveRequirementReady records the time to ready of a requirement created at the given time
+func ObserveRequirementReady(created time.Time, hit bool) {
+	result := ResultMiss
+	if hit {
+		result = ResultHit
+	}
+	RequirementTimeToReadySeconds.WithLabelValues(result).Observe(time.Since(created).Seconds())
+}
+
+// ObserveOperationProvisioned records the provision duration of an operation created at the given time
+func ObserveOperationProvisioned(created time.Time) {
+	OperationProvisionDurationSeconds.Observe(time.Since(created).Seconds())
+}
+
+// ObserveJobFinished records the run time of a finished job
+func ObserveJobFinished(jobType string, succeeded bool, duration time.Duration) {
+	result := ResultFailed
+	if succeeded {
+		result = ResultSucceeded
+	}
+	AppDeploymentJobDurationSeconds.WithLabelValues(jobType, result).Observe(duration.Seconds())
+	if !succeeded {
+		AppDeploymentJobFailuresTotal.WithLabelValues(jobType).Inc()
+	}
+}
+
+// RecordJobRetry counts a job recreated after a failure
+func RecordJobRetry(jobType string) {
+	AppDeploymentJobRetriesTotal.WithLabelValues(jobType).Inc()
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordCacheResult(t *testing.T) {
+	RecordCacheResult("test-ns", "test-key", true)
+	RecordCacheResult("test-ns", "test-key", false)
+	RecordCacheResult("test-ns", "test-key", false)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(CacheHitsTotal.WithLabelValues("test-ns", "test-key")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(CacheMissesTotal.WithLabelValues("test-ns", "test-key")))
+}
+
+func TestCachePool(t *testing.T) {
+	SetCachePool("test-ns", "test-cache", 3, 1)
+	RecordRecycledOperations("test-ns", "test-cache", 2)
+	assert.Equal(t, float64(3), testutil.ToFloat64(CacheKeepAliveOperations.WithLabelValues("test-ns", "test-cache")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(CacheReadyOperations.WithLabelValues("test-ns", "test-cache")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(CacheRecycledOperationsTotal.WithLabelValues("test-ns", "test-cache")))
+
+	DeleteCachePool("test-ns", "test-cache")
+	assert.Equal(t, 0, testutil.CollectAndCount(CacheKeepAliveOperations))
+	assert.Equal(t, 0, testutil.CollectAndCount(CacheReadyOperations))
+	assert.Equal(t, 0, testutil.CollectAndCount(CacheRecycledOperationsTotal))
+}
+
+func TestObserveJobFinished(t *testing.T) {
+	ObserveJobFinished("provision", true, time.Minute)
+	ObserveJobFinished("provision", false, time.Minute)
+	RecordJobRetry("provision")
+
+	assert.Equal(t, 2, testutil.CollectAndCount(AppDeploymentJobDurationSeconds))
+	assert.Equal(t, float64(1), testutil.ToFloat64(AppDeploymentJobFailuresTotal.WithLabelValues("provision")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(AppDeploymentJobRetriesTotal.WithLabelValues("provision")))
+}
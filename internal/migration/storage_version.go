@@ -0,0 +1,112 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// listPageSize is the number of objects listed at once while migrating
+const listPageSize = 100
+
+// resources are the crds of the controller with the list type of their objects
+var resources = []struct {
+	plural string
+	list   func() client.ObjectList
+}{
+	{"appdeployments", func() client.ObjectList { return &v1beta1.AppDeploymentList{} }},
+	{"operations", func() client.ObjectList { return &v1beta1.OperationList{} }},
+	{"requirements", func() client.ObjectList { return &v1beta1.RequirementList{} }},
+	{"caches", func() client.ObjectList { return &v1beta1.CacheList{} }},
+}
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update
+
+// StorageVersionMigrator rewrites the objects stored in an older version of the api in the storage version, then
+// drops the older versions from the stored versions of the crds so they can be removed from the crds later.
+type StorageVersionMigrator struct {
+	client client.Client
+	reader client.Reader
+	logger logr.Logger
+}
+
+// NewStorageVersionMigrator returns a migrator writing with the client and reading with the reader, which should not
+// be cached since the crds are not watched.
+func NewStorageVersionMigrator(c client.Client, reader client.Reader, logger logr.Logger) *StorageVersionMigrator {
+	return &StorageVersionMigrator{
+		client: c,
+		reader: reader,
+		logger: logger,
+	}
+}
+
+// NeedLeaderElection makes only the leader migrate
+func (m *StorageVersionMigrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start migrates all crds once. A failed migration is logged and retried by the next leader, it must not stop the
+// controllers.
+func (m *StorageVersionMigrator) Start(ctx context.Context) error {
+	for _, resource := range resources {
+		name := resource.plural + "." + v1beta1.GroupVersion.Group
+		if err := m.migrate(ctx, name, resource.list); err != nil {
+			m.logger.Error(err, "failed to migrate storage version", "crd", name)
+		}
+	}
+	return nil
+}
+
+func (m *StorageVersionMigrator) migrate(ctx context.Context, name string, newList func() client.ObjectList) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := m.reader.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		return fmt.Errorf("failed to get crd: %w", err)
+	}
+	storageVersion := v1beta1.GroupVersion.Version
+	if slices.Equal(crd.Status.StoredVersions, []string{storageVersion}) {
+		return nil
+	}
+	m.logger.Info("migrating storage version", "crd", name, "storedVersions", crd.Status.StoredVersions)
+
+	migrated := 0
+	continueToken := ""
+	for {
+		list := newList()
+		if err := m.reader.List(ctx, list, client.Limit(listPageSize), client.Continue(continueToken)); err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		objects, err := meta.ExtractList(list)
+		if err != nil {
+			return fmt.Errorf("failed to extract objects: %w", err)
+		}
+		for _, o := range objects {
+			obj := o.(client.Object)
+			// a no-op update is written in the storage version, a conflict means the object was written meanwhile
+			if err := m.client.Update(ctx, obj); err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to migrate %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+			}
+			migrated++
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	if err := m.client.Status().Update(ctx, crd); err != nil {
+		return fmt.Errorf("failed to update stored versions: %w", err)
+	}
+	m.logger.Info("migrated storage version", "crd", name, "objects", migrated)
+	return nil
+}
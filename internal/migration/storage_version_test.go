@@ -0,0 +1,90 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+func newCRD(plural string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: plural + "." + v1beta1.GroupVersion.Group},
+		Status:     apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&apiextensionsv1.CustomResourceDefinition{}).
+		Build()
+}
+
+func TestStorageVersionMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("migrates objects and drops old stored versions", func(t *testing.T) {
+		operation := &v1beta1.Operation{ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "default"}}
+		c := newFakeClient(t,
+			newCRD("appdeployments", "v1beta1"),
+			newCRD("operations", "v1alpha1", "v1beta1"),
+			newCRD("requirements", "v1beta1"),
+			newCRD("caches", "v1beta1"),
+			operation,
+		)
+		before := &v1beta1.Operation{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(operation), before))
+
+		require.NoError(t, NewStorageVersionMigrator(c, c, logr.Discard()).Start(ctx))
+
+		after := &v1beta1.Operation{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(operation), after))
+		assert.NotEqual(t, before.ResourceVersion, after.ResourceVersion)
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(newCRD("operations")), crd))
+		assert.Equal(t, []string{"v1beta1"}, crd.Status.StoredVersions)
+	})
+
+	t.Run("migrated crds are skipped", func(t *testing.T) {
+		cache := &v1beta1.Cache{ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "default"}}
+		c := newFakeClient(t,
+			newCRD("appdeployments", "v1beta1"),
+			newCRD("operations", "v1beta1"),
+			newCRD("requirements", "v1beta1"),
+			newCRD("caches", "v1beta1"),
+			cache,
+		)
+		before := &v1beta1.Cache{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cache), before))
+
+		require.NoError(t, NewStorageVersionMigrator(c, c, logr.Discard()).Start(ctx))
+
+		after := &v1beta1.Cache{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cache), after))
+		assert.Equal(t, before.ResourceVersion, after.ResourceVersion)
+	})
+
+	t.Run("a missing crd does not stop the others", func(t *testing.T) {
+		c := newFakeClient(t, newCRD("caches", "v1alpha1", "v1beta1"))
+
+		require.NoError(t, NewStorageVersionMigrator(c, c, logr.Discard()).Start(ctx))
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(newCRD("caches")), crd))
+		assert.Equal(t, []string{"v1beta1"}, crd.Status.StoredVersions)
+	})
+}
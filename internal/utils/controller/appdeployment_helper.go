@@ -5,18 +5,20 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 )
 
 const (
 	// env keys
 	OperationIDEnvKey = "OPERATION_ID"
+	// OutputsSecretEnvKey names the secret a provision job writes its secret outputs to
+	OutputsSecretEnvKey = "OUTPUTS_SECRET_NAME"
 )
 
 type AppDeploymentHelper struct{}
 
 func NewAppDeploymentHelper() AppDeploymentHelper { return AppDeploymentHelper{} }
 
-func (ad AppDeploymentHelper) ClearConditions(ctx context.Context, appdeployment *v1alpha1.AppDeployment) {
+func (ad AppDeploymentHelper) ClearConditions(ctx context.Context, appdeployment *v1beta1.AppDeployment) {
 	appdeployment.Status.Conditions = []metav1.Condition{}
 }
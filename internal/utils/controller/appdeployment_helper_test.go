@@ -7,7 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
 )
 
@@ -43,8 +43,8 @@ func TestClearConditions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			appDep := &v1alpha1.AppDeployment{
-				Status: v1alpha1.AppDeploymentStatus{
+			appDep := &v1beta1.AppDeployment{
+				Status: v1beta1.AppDeploymentStatus{
 					Conditions: tt.existingConditions,
 				},
 			}
@@ -2,24 +2,26 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 )
 
 const (
 	JobTypeProvision = "provision"
 	JobTypeTeardown  = "teardown"
+	JobTypeReset     = "reset"
 	JobNamePattern   = "%s-%s-%s" // jobType-appName-operationId
 )
 
 var (
-	backOffLimit            int32 = 10
-	ttlSecondsAfterFinished int32 = 3600
-	MaxAppNameLength        int   = 36
+	MaxAppNameLength int = 36
 )
 
 func validJobName(appName, jobType string) string {
@@ -30,32 +32,67 @@ func validJobName(appName, jobType string) string {
 	return originName
 }
 
-func ProvisionJobFromAppDeploymentSpec(appDeployment *v1alpha1.AppDeployment) *batchv1.Job {
+func ProvisionJobFromAppDeploymentSpec(appDeployment *v1beta1.AppDeployment) *batchv1.Job {
 	return jobFromAppDeploymentSpec(appDeployment, JobTypeProvision)
 }
 
-func TeardownJobFromAppDeploymentSpec(appDeployment *v1alpha1.AppDeployment) *batchv1.Job {
+func TeardownJobFromAppDeploymentSpec(appDeployment *v1beta1.AppDeployment) *batchv1.Job {
 	return jobFromAppDeploymentSpec(appDeployment, JobTypeTeardown)
 }
 
-func GetProvisionJobName(appDeployment *v1alpha1.AppDeployment) string {
-	return validJobName(appDeployment.Name, JobTypeProvision)
+// GetProvisionJobName returns the name of the job of the current provision attempt. Retries are suffixed with the
+// attempt index, so the jobs of failed attempts can be kept next to them.
+func GetProvisionJobName(appDeployment *v1beta1.AppDeployment) string {
+	name := validJobName(appDeployment.Name, JobTypeProvision)
+	if appDeployment.Status.ProvisionAttempt == 0 {
+		return name
+	}
+	suffix := fmt.Sprintf("-%d", appDeployment.Status.ProvisionAttempt)
+	if len(name)+len(suffix) > MaxResourceNameLength {
+		name = name[:MaxResourceNameLength-len(suffix)]
+	}
+	return name + suffix
 }
 
-func GetTeardownJobName(appDeployment *v1alpha1.AppDeployment) string {
+func GetTeardownJobName(appDeployment *v1beta1.AppDeployment) string {
 	return validJobName(appDeployment.Name, JobTypeTeardown)
 }
 
-func jobFromAppDeploymentSpec(appDeployment *v1alpha1.AppDeployment, suffix string) *batchv1.Job {
+// GetResetJobName returns the name of the job resetting a released operation. Every reuse of the operation gets its
+// own job, so the finished job of the previous reuse is not taken for the current one.
+func GetResetJobName(operation *v1beta1.Operation) string {
+	name := validJobName(operation.Name, JobTypeReset)
+	suffix := fmt.Sprintf("-%d", operation.Status.ReuseCount+1)
+	if len(name)+len(suffix) > MaxResourceNameLength {
+		name = name[:MaxResourceNameLength-len(suffix)]
+	}
+	return name + suffix
+}
+
+// ResetJobFromCache returns the reset job of the cache for the released operation
+func ResetJobFromCache(cache *v1beta1.Cache, operation *v1beta1.Operation) *batchv1.Job {
+	return newJobWithOptions(jobOptions{
+		name:        GetResetJobName(operation),
+		namespace:   operation.Namespace,
+		labels:      operation.Labels,
+		jobSpec:     *cache.Spec.Recycle.Reset.DeepCopy(),
+		operationID: operation.Status.OperationID,
+	})
+}
+
+func jobFromAppDeploymentSpec(appDeployment *v1beta1.AppDeployment, suffix string) *batchv1.Job {
 	ops := jobOptions{
-		name:        validJobName(appDeployment.Name, suffix),
+		name:        GetProvisionJobName(appDeployment),
 		namespace:   appDeployment.Namespace,
 		labels:      appDeployment.Labels,
-		jobSpec:     appDeployment.Spec.Provision,
-		operationID: appDeployment.Spec.OpId,
+		jobSpec:     *appDeployment.Spec.Provision.DeepCopy(),
+		operationID: appDeployment.Spec.OperationID,
 	}
 	if suffix == JobTypeTeardown {
-		ops.jobSpec = appDeployment.Spec.Teardown
+		ops.name = GetTeardownJobName(appDeployment)
+		ops.jobSpec = *appDeployment.Spec.Teardown.DeepCopy()
+	} else {
+		ops.outputsSecretName = OutputsSecretName(appDeployment)
 	}
 	return newJobWithOptions(ops)
 }
@@ -68,13 +105,29 @@ type jobOptions struct {
 	ownerRefs   []metav1.OwnerReference
 	jobSpec     batchv1.JobSpec
 	operationID string
+	// outputsSecretName is the secret a provision job writes its secret outputs to
+	outputsSecretName string
 }
 
 func newJobWithOptions(options jobOptions) *batchv1.Job {
-	options.jobSpec.Template.Spec.Containers[0].Env = append(options.jobSpec.Template.Spec.Containers[0].Env, corev1.EnvVar{
-		Name:  OperationIDEnvKey,
-		Value: options.operationID,
-	})
+	// every container, e.g. a credentials sidecar or an init container fetching tools, knows the operation
+	podSpec := &options.jobSpec.Template.Spec
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			containers[i].Env = append(containers[i].Env, corev1.EnvVar{
+				Name:  OperationIDEnvKey,
+				Value: options.operationID,
+			})
+		}
+	}
+	if options.outputsSecretName != "" {
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, corev1.EnvVar{
+				Name:  OutputsSecretEnvKey,
+				Value: options.outputsSecretName,
+			})
+		}
+	}
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        options.name,
@@ -84,9 +137,26 @@ func newJobWithOptions(options jobOptions) *batchv1.Job {
 		},
 		Spec: options.jobSpec,
 	}
-	job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
-	job.Spec.BackoffLimit = &backOffLimit
-	job.Spec.TTLSecondsAfterFinished = &ttlSecondsAfterFinished
+	// failed containers without a termination message report the tail of their logs, which ends up in the conditions
+	for i := range job.Spec.Template.Spec.Containers {
+		if job.Spec.Template.Spec.Containers[i].TerminationMessagePolicy == "" {
+			job.Spec.Template.Spec.Containers[i].TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+		}
+	}
+	// failed containers are not restarted in place, so the pods of every failed attempt are kept with their logs. Any
+	// other restart policy is rejected by the validation.
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	// the controller defaults only apply to jobs which do not set their own. By default a job fails with its first
+	// failed pod, the controller retries with a new job so every attempt keeps its pod.
+	jobConfig := config.Current().Job
+	if job.Spec.BackoffLimit == nil {
+		job.Spec.BackoffLimit = ptr.Of(*jobConfig.BackoffLimit)
+	}
+	if job.Spec.TTLSecondsAfterFinished == nil {
+		job.Spec.TTLSecondsAfterFinished = ptr.Of(*jobConfig.TTLSecondsAfterFinished)
+	}
 
 	if len(options.ownerRefs) > 0 {
 		job.OwnerReferences = options.ownerRefs
@@ -103,11 +173,23 @@ var (
 	JobStatusRunning   JobStatus = "Running"
 )
 
+// CheckJobStatus returns the status of the job. A job retrying failed pods within its backoff limit is still running,
+// it has failed once the job controller says so, e.g. for an exceeded deadline or a pod failure policy, or once the
+// failed pods exceed the backoff limit.
 func CheckJobStatus(ctx context.Context, job *batchv1.Job) JobStatus {
 	if job.Status.Succeeded > 0 {
 		return JobStatusSucceeded
 	}
-	if job.Status.Failed > 0 {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return JobStatusFailed
+		}
+	}
+	limit := *config.Current().Job.BackoffLimit
+	if job.Spec.BackoffLimit != nil {
+		limit = *job.Spec.BackoffLimit
+	}
+	if job.Status.Failed > limit {
 		return JobStatusFailed
 	}
 	return JobStatusRunning
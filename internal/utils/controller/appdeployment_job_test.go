@@ -5,9 +5,11 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 	"github.com/stretchr/testify/assert"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -77,6 +79,34 @@ func TestCheckJobStatus(t *testing.T) {
 			},
 			expected: JobStatusRunning,
 		},
+		{
+			name: "Should return in progress when job retries within its backoff limit",
+			job: &batchv1.Job{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.Of(int32(2)),
+				},
+				Status: batchv1.JobStatus{
+					Failed: 2,
+				},
+			},
+			expected: JobStatusRunning,
+		},
+		{
+			name: "Should return failed when job controller failed the job",
+			job: &batchv1.Job{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.Of(int32(2)),
+				},
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{{
+						Type:   batchv1.JobFailed,
+						Status: corev1.ConditionTrue,
+						Reason: batchv1.JobReasonDeadlineExceeded,
+					}},
+				},
+			},
+			expected: JobStatusFailed,
+		},
 	}
 
 	for _, tt := range tests {
@@ -112,7 +142,7 @@ func TestGetProvisionJobName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			appDeployment := &v1alpha1.AppDeployment{
+			appDeployment := &v1beta1.AppDeployment{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: tt.appName,
 				},
@@ -123,6 +153,48 @@ func TestGetProvisionJobName(t *testing.T) {
 	}
 }
 
+func TestProvisionJobFromAppDeploymentSpec_JobFields(t *testing.T) {
+	appDeployment := &v1beta1.AppDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "op-app", Namespace: "default"},
+		Spec: v1beta1.AppDeploymentSpec{
+			OperationID: "op",
+			Provision: batchv1.JobSpec{
+				ActiveDeadlineSeconds:   ptr.Of(int64(7200)),
+				BackoffLimit:            ptr.Of(int32(2)),
+				TTLSecondsAfterFinished: ptr.Of(int32(60)),
+				PodFailurePolicy: &batchv1.PodFailurePolicy{
+					Rules: []batchv1.PodFailurePolicyRule{{
+						Action:      batchv1.PodFailurePolicyActionFailJob,
+						OnExitCodes: &batchv1.PodFailurePolicyOnExitCodesRequirement{Operator: batchv1.PodFailurePolicyOnExitCodesOpIn, Values: []int32{42}},
+					}},
+				},
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "provision"}}},
+				},
+			},
+		},
+	}
+	// the job honors the fields it sets instead of the controller defaults
+	job := ProvisionJobFromAppDeploymentSpec(appDeployment)
+	assert.Equal(t, int64(7200), *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, int32(2), *job.Spec.BackoffLimit)
+	assert.Equal(t, int32(60), *job.Spec.TTLSecondsAfterFinished)
+	assert.Equal(t, appDeployment.Spec.Provision.PodFailurePolicy, job.Spec.PodFailurePolicy)
+}
+
+func TestGetProvisionJobName_Attempts(t *testing.T) {
+	appDeployment := &v1beta1.AppDeployment{ObjectMeta: metav1.ObjectMeta{Name: "op123-my-app"}}
+	appDeployment.Status.ProvisionAttempt = 2
+	assert.Equal(t, "provision-op123-my-app-2", GetProvisionJobName(appDeployment))
+
+	appDeployment.Name = "op1234567890-a-very-long-application-name-exceeding-limit"
+	res := GetProvisionJobName(appDeployment)
+	assert.Len(t, res, MaxResourceNameLength)
+	assert.Equal(t, "provision-op1234567890-a-very-long-application-name-exceeding-2", res)
+	// the teardown job name does not depend on the attempt
+	assert.Equal(t, "teardown-op1234567890-a-very-long-application-name-exceeding-li", GetTeardownJobName(appDeployment))
+}
+
 func TestGetTeardownJobName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -149,7 +221,7 @@ func TestGetTeardownJobName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			appDeployment := &v1alpha1.AppDeployment{
+			appDeployment := &v1beta1.AppDeployment{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: tt.appName,
 				},
@@ -249,3 +321,67 @@ func TestOperationScopedAppDeployment_LengthConstraints(t *testing.T) {
 		}
 	}
 }
+
+func TestProvisionJobFromAppDeploymentSpec(t *testing.T) {
+	appDeployment := &v1beta1.AppDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "op-app", Namespace: "default"},
+		Spec: v1beta1.AppDeploymentSpec{
+			OperationID: "op",
+			Provision: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{{Name: "fetch-tools"}},
+						Containers: []corev1.Container{
+							{Name: "provision"},
+							{Name: "sidecar", TerminationMessagePolicy: corev1.TerminationMessageReadFile},
+						},
+					},
+				},
+			},
+		},
+	}
+	job := ProvisionJobFromAppDeploymentSpec(appDeployment)
+	assert.Equal(t, corev1.TerminationMessageFallbackToLogsOnError, job.Spec.Template.Spec.Containers[0].TerminationMessagePolicy)
+	assert.Equal(t, corev1.TerminationMessageReadFile, job.Spec.Template.Spec.Containers[1].TerminationMessagePolicy)
+	for _, container := range append(job.Spec.Template.Spec.InitContainers, job.Spec.Template.Spec.Containers...) {
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: OperationIDEnvKey, Value: "op"}, container.Name)
+	}
+	// only the containers of a provision job write secret outputs
+	for _, container := range job.Spec.Template.Spec.Containers {
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: OutputsSecretEnvKey, Value: OutputsSecretName(appDeployment)}, container.Name)
+	}
+	assert.NotContains(t, job.Spec.Template.Spec.InitContainers[0].Env, corev1.EnvVar{Name: OutputsSecretEnvKey, Value: OutputsSecretName(appDeployment)})
+	// failed pods are kept for their logs instead of restarting the containers in place
+	assert.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	// a kept failed job does not start new pods on its own
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, int32(3600), *job.Spec.TTLSecondsAfterFinished)
+	assert.Nil(t, job.Spec.ActiveDeadlineSeconds)
+	// the spec of the app deployment is left untouched
+	assert.Empty(t, appDeployment.Spec.Provision.Template.Spec.Containers[0].TerminationMessagePolicy)
+	assert.Empty(t, appDeployment.Spec.Provision.Template.Spec.Containers[0].Env)
+	assert.Empty(t, appDeployment.Spec.Provision.Template.Spec.InitContainers[0].Env)
+}
+
+func TestResetJobFromCache(t *testing.T) {
+	cache := &v1beta1.Cache{Spec: v1beta1.CacheSpec{Recycle: &v1beta1.CacheRecycle{
+		Reset: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "reset"}}},
+		}},
+	}}}
+	operation := &v1beta1.Operation{
+		ObjectMeta: metav1.ObjectMeta{Name: "cached-operation", Namespace: "default", Labels: map[string]string{"k": "v"}},
+		Status:     v1beta1.OperationStatus{OperationID: "op", ReuseCount: 1},
+	}
+
+	job := ResetJobFromCache(cache, operation)
+	assert.Equal(t, "reset-cached-operation-2", job.Name)
+	assert.Equal(t, "default", job.Namespace)
+	assert.Equal(t, operation.Labels, job.Labels)
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: OperationIDEnvKey, Value: "op"})
+	// the reset job of the cache is not changed by the job of an operation
+	assert.Empty(t, cache.Spec.Recycle.Reset.Template.Spec.Containers[0].Env)
+
+	operation.Name = "cached-operation-with-a-very-long-name-exceeding-the-resource-limit"
+	assert.Len(t, GetResetJobName(operation), MaxResourceNameLength)
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+const outputsSecretSuffix = "-outputs"
+
+var ErrInvalidJobOutputs = errors.New("invalid job outputs")
+
+// JobOutputs is the contract between a provision job and the controller. The first container of the job writes it
+// as JSON to its termination message (/dev/termination-log by default), e.g.
+//
+//	{"outputs": {"endpoint": "https://..."}}
+//
+// outputs are exposed in the status of the AppDeployment, Operation and Requirement. The termination message is
+// readable by anyone allowed to get pods, so secret outputs are not part of it: the job writes them to the Secret
+// named by the OUTPUTS_SECRET_NAME environment variable, which the controller grants it write access to.
+type JobOutputs struct {
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// ParseJobOutputs parses the termination message of a provision job, an empty message has no outputs
+func ParseJobOutputs(message string) (*JobOutputs, error) {
+	outputs := &JobOutputs{}
+	if strings.TrimSpace(message) == "" {
+		return outputs, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(message))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(outputs); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJobOutputs, err)
+	}
+	var errs error
+	for key := range outputs.Outputs {
+		// the keys become keys of the status map
+		if msgs := validation.IsConfigMapKey(key); len(msgs) > 0 {
+			errs = errors.Join(errs, fmt.Errorf("%w: key %q: %s", ErrInvalidJobOutputs, key, strings.Join(msgs, ", ")))
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	return outputs, nil
+}
+
+// JobOutputsFromPods parses the outputs the named container of the first succeeded pod of a job wrote, the messages
+// of other containers, e.g. a sidecar, are ignored
+func JobOutputsFromPods(pods []corev1.Pod, containerName string) (*JobOutputs, error) {
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == containerName && status.State.Terminated != nil {
+				return ParseJobOutputs(status.State.Terminated.Message)
+			}
+		}
+		return &JobOutputs{}, nil
+	}
+	return &JobOutputs{}, nil
+}
+
+// OutputsContainerName returns the name of the container of a job writing the outputs, the first one of its pod
+func OutputsContainerName(job *batchv1.Job) string {
+	if len(job.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+	return job.Spec.Template.Spec.Containers[0].Name
+}
+
+// OutputsSecretName returns the name of the secret holding the secret outputs of an app deployment
+func OutputsSecretName(appDeployment *v1beta1.AppDeployment) string {
+	return appDeployment.Name + outputsSecretSuffix
+}
+
+// OutputsRoleRules returns the rules of the role granting a provision job write access to the secret for its secret
+// outputs, and to no other secret of the namespace
+func OutputsRoleRules(appDeployment *v1beta1.AppDeployment) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{{
+		APIGroups:     []string{""},
+		Resources:     []string{"secrets"},
+		ResourceNames: []string{OutputsSecretName(appDeployment)},
+		Verbs:         []string{"get", "update", "patch"},
+	}}
+}
+
+// OutputsRoleSubjects returns the service account the pods of the provision job run as
+func OutputsRoleSubjects(appDeployment *v1beta1.AppDeployment) []rbacv1.Subject {
+	serviceAccount := appDeployment.Spec.Provision.Template.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	return []rbacv1.Subject{{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      serviceAccount,
+		Namespace: appDeployment.Namespace,
+	}}
+}
+
+// RequirementOutputsSecretName returns the name of the copy of the secret outputs of an application, which a
+// requirement bound to an operation of the cache pool reads in its own namespace. Names too long for a resource name
+// are truncated and keep a hash of the full name, so the copies of different applications do not collide.
+func RequirementOutputsSecretName(requirement *v1beta1.Requirement, appName string) string {
+	name := requirement.Name + "-" + appName + outputsSecretSuffix
+	if len(name) <= MaxResourceNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:8] + outputsSecretSuffix
+	return strings.TrimRight(name[:MaxResourceNameLength-len(suffix)], "-.") + suffix
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+func TestParseJobOutputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected *JobOutputs
+		wantErr  bool
+	}{
+		{
+			name:     "empty message",
+			message:  " \n",
+			expected: &JobOutputs{},
+		},
+		{
+			name:    "outputs",
+			message: `{"outputs":{"endpoint":"https://example.com"}}`,
+			expected: &JobOutputs{
+				Outputs: map[string]string{"endpoint": "https://example.com"},
+			},
+		},
+		{
+			name:    "secrets in the termination message",
+			message: `{"outputs":{"endpoint":"https://example.com"},"secrets":{"password":"p@ss"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			message: "done",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			message: `{"endpoint":"https://example.com"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			message: `{"outputs":{"end point":"https://example.com"}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs, err := ParseJobOutputs(tt.message)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidJobOutputs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, outputs)
+		})
+	}
+}
+
+func TestJobOutputsFromPods(t *testing.T) {
+	pod := func(phase corev1.PodPhase, message string) corev1.Pod {
+		return corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: phase,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:  "sidecar",
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: `{"outputs":{"proxy":"up"}}`}},
+					},
+					{
+						Name:  "provision",
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}},
+					},
+				},
+			},
+		}
+	}
+
+	outputs, err := JobOutputsFromPods(nil, "provision")
+	assert.NoError(t, err)
+	assert.Equal(t, &JobOutputs{}, outputs)
+
+	outputs, err = JobOutputsFromPods([]corev1.Pod{
+		pod(corev1.PodFailed, `{"outputs":{"attempt":"1"}}`),
+		pod(corev1.PodSucceeded, `{"outputs":{"attempt":"2"}}`),
+	}, "provision")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"attempt": "2"}, outputs.Outputs)
+
+	// the message of a sidecar is not taken for the one of the named container
+	outputs, err = JobOutputsFromPods([]corev1.Pod{pod(corev1.PodSucceeded, "")}, "provision")
+	assert.NoError(t, err)
+	assert.Equal(t, &JobOutputs{}, outputs)
+
+	_, err = JobOutputsFromPods([]corev1.Pod{pod(corev1.PodSucceeded, "done")}, "provision")
+	assert.ErrorIs(t, err, ErrInvalidJobOutputs)
+}
+
+func TestOutputsContainerName(t *testing.T) {
+	job := &batchv1.Job{}
+	assert.Empty(t, OutputsContainerName(job))
+	job.Spec.Template.Spec.Containers = []corev1.Container{{Name: "provision"}, {Name: "sidecar"}}
+	assert.Equal(t, "provision", OutputsContainerName(job))
+}
+
+func TestOutputsRole(t *testing.T) {
+	appDeployment := &v1beta1.AppDeployment{ObjectMeta: metav1.ObjectMeta{Name: "op-app", Namespace: "ns"}}
+	rules := OutputsRoleRules(appDeployment)
+	require.Len(t, rules, 1)
+	assert.Equal(t, []string{"secrets"}, rules[0].Resources)
+	assert.Equal(t, []string{"op-app-outputs"}, rules[0].ResourceNames)
+	assert.NotContains(t, rules[0].Verbs, "list")
+
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "default", Namespace: "ns"}},
+		OutputsRoleSubjects(appDeployment))
+	appDeployment.Spec.Provision.Template.Spec.ServiceAccountName = "provisioner"
+	assert.Equal(t, "provisioner", OutputsRoleSubjects(appDeployment)[0].Name)
+}
+
+func TestOutputsSecretName(t *testing.T) {
+	appDeployment := &v1beta1.AppDeployment{ObjectMeta: metav1.ObjectMeta{Name: "op-app"}}
+	assert.Equal(t, "op-app-outputs", OutputsSecretName(appDeployment))
+}
+
+func TestRequirementOutputsSecretName(t *testing.T) {
+	requirement := &v1beta1.Requirement{ObjectMeta: metav1.ObjectMeta{Name: "req"}}
+	assert.Equal(t, "req-app-outputs", RequirementOutputsSecretName(requirement, "app"))
+
+	requirement.Name = strings.Repeat("r", 253)
+	name := RequirementOutputsSecretName(requirement, "app")
+	assert.LessOrEqual(t, len(name), MaxResourceNameLength)
+	assert.Empty(t, validation.IsDNS1123Label(name))
+	assert.True(t, strings.HasSuffix(name, "-outputs"))
+	assert.NotEqual(t, name, RequirementOutputsSecretName(requirement, "other-app"))
+	assert.Equal(t, name, RequirementOutputsSecretName(requirement, "app"))
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
+)
+
+const (
+	// JobFailureReasonUnknown is the reason of a failed job without a failed condition
+	JobFailureReasonUnknown = "JobFailed"
+	// maxLogExcerptLength bounds the log excerpt kept in conditions, the tail of the log is kept
+	maxLogExcerptLength = 1024
+)
+
+// JobFailure describes why a job failed
+type JobFailure struct {
+	Reason  string
+	Message string
+}
+
+// MaxRetries returns the number of times a failed provision job of the app deployment is retried
+func (ad AppDeploymentHelper) MaxRetries(appdeployment *v1beta1.AppDeployment) int32 {
+	if appdeployment.Spec.MaxRetries == nil {
+		return v1beta1.DefaultMaxRetries
+	}
+	return *appdeployment.Spec.MaxRetries
+}
+
+// FailedJobsHistoryLimit returns the number of failed provision jobs of the app deployment to keep
+func (ad AppDeploymentHelper) FailedJobsHistoryLimit(appdeployment *v1beta1.AppDeployment) int32 {
+	if appdeployment.Spec.FailedJobsHistoryLimit == nil {
+		return v1beta1.DefaultFailedJobsHistoryLimit
+	}
+	return *appdeployment.Spec.FailedJobsHistoryLimit
+}
+
+// TeardownPolicy returns what happens when the teardown job of the app deployment fails
+func (ad AppDeploymentHelper) TeardownPolicy(appdeployment *v1beta1.AppDeployment) string {
+	if appdeployment.Spec.TeardownPolicy == "" {
+		return v1beta1.TeardownPolicyRetry
+	}
+	return appdeployment.Spec.TeardownPolicy
+}
+
+// RetryBackoff returns how long to wait after the given number of failures before the next attempt, doubling from the
+// retry base delay of the controller config up to its retry max delay, 10s up to 5m by default
+func (ad AppDeploymentHelper) RetryBackoff(failures int32) time.Duration {
+	jobConfig := config.Current().Job
+	delay, maxDelay := jobConfig.RetryBaseDelay.Duration, jobConfig.RetryMaxDelay.Duration
+	for i := int32(1); i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// JobFailureFromPods returns the failure reason of the job and its message with an excerpt of the logs of the last
+// failed container. Job containers fall back to their logs as termination message on error, so no log access is needed.
+func JobFailureFromPods(job *batchv1.Job, pods []corev1.Pod) JobFailure {
+	failure := JobFailure{Reason: JobFailureReasonUnknown, Message: fmt.Sprintf("job %s failed", job.Name)}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			if condition.Reason != "" {
+				failure.Reason = condition.Reason
+			}
+			if condition.Message != "" {
+				failure.Message = fmt.Sprintf("job %s failed: %s", job.Name, condition.Message)
+			}
+		}
+	}
+	if excerpt := lastFailedContainerMessage(pods); excerpt != "" {
+		failure.Message += "\nlast logs:\n" + excerpt
+	}
+	return failure
+}
+
+func lastFailedContainerMessage(pods []corev1.Pod) string {
+	var (
+		message  string
+		finished time.Time
+	)
+	for _, pod := range pods {
+		// a failed init container stops the pod before its containers run
+		for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated == nil || terminated.ExitCode == 0 || terminated.Message == "" {
+					continue
+				}
+				if message == "" || terminated.FinishedAt.After(finished) {
+					message, finished = terminated.Message, terminated.FinishedAt.Time
+				}
+			}
+		}
+	}
+	message = strings.TrimSpace(message)
+	if len(message) > maxLogExcerptLength {
+		message = message[len(message)-maxLogExcerptLength:]
+	}
+	return message
+}
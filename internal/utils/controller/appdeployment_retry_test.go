@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
+)
+
+func TestMaxRetries(t *testing.T) {
+	helper := NewAppDeploymentHelper()
+	appDeployment := &v1beta1.AppDeployment{}
+	assert.Equal(t, v1beta1.DefaultMaxRetries, helper.MaxRetries(appDeployment))
+
+	appDeployment.Spec.MaxRetries = ptr.Of(int32(0))
+	assert.Equal(t, int32(0), helper.MaxRetries(appDeployment))
+}
+
+func TestFailedJobsHistoryLimit(t *testing.T) {
+	helper := NewAppDeploymentHelper()
+	appDeployment := &v1beta1.AppDeployment{}
+	assert.Equal(t, v1beta1.DefaultFailedJobsHistoryLimit, helper.FailedJobsHistoryLimit(appDeployment))
+
+	appDeployment.Spec.FailedJobsHistoryLimit = ptr.Of(int32(0))
+	assert.Equal(t, int32(0), helper.FailedJobsHistoryLimit(appDeployment))
+}
+
+func TestTeardownPolicy(t *testing.T) {
+	helper := NewAppDeploymentHelper()
+	appDeployment := &v1beta1.AppDeployment{}
+	assert.Equal(t, v1beta1.TeardownPolicyRetry, helper.TeardownPolicy(appDeployment))
+
+	appDeployment.Spec.TeardownPolicy = v1beta1.TeardownPolicyBlock
+	assert.Equal(t, v1beta1.TeardownPolicyBlock, helper.TeardownPolicy(appDeployment))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	helper := NewAppDeploymentHelper()
+	assert.Equal(t, 10*time.Second, helper.RetryBackoff(1))
+	assert.Equal(t, 20*time.Second, helper.RetryBackoff(2))
+	assert.Equal(t, 80*time.Second, helper.RetryBackoff(4))
+	assert.Equal(t, 5*time.Minute, helper.RetryBackoff(10))
+	assert.Equal(t, 5*time.Minute, helper.RetryBackoff(100))
+}
+
+func TestJobFailureFromPods(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "provision-app"}}
+	failure := JobFailureFromPods(job, nil)
+	assert.Equal(t, JobFailure{Reason: JobFailureReasonUnknown, Message: "job provision-app failed"}, failure)
+
+	job.Status.Conditions = []batchv1.JobCondition{{
+		Type:    batchv1.JobFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  "BackoffLimitExceeded",
+		Message: "Job has reached the specified backoff limit",
+	}}
+	now := time.Now()
+	terminated := func(exitCode int32, message string, finishedAt time.Time) *corev1.ContainerStateTerminated {
+		return &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message, FinishedAt: metav1.NewTime(finishedAt)}
+	}
+	pods := []corev1.Pod{{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				State:                corev1.ContainerState{Terminated: terminated(2, "second attempt\n", now)},
+				LastTerminationState: corev1.ContainerState{Terminated: terminated(1, "first attempt", now.Add(-time.Minute))},
+			}},
+		},
+	}}
+	failure = JobFailureFromPods(job, pods)
+	assert.Equal(t, "BackoffLimitExceeded", failure.Reason)
+	assert.Equal(t, "job provision-app failed: Job has reached the specified backoff limit\nlast logs:\nsecond attempt", failure.Message)
+
+	// only the tail of long logs is kept
+	pods[0].Status.ContainerStatuses[0].State.Terminated.Message = "head" + string(make([]byte, maxLogExcerptLength))
+	failure = JobFailureFromPods(job, pods)
+	assert.NotContains(t, failure.Message, "head")
+
+	// a failed init container is reported as well
+	pods = []corev1.Pod{{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: terminated(1, "tools not found", now)},
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{}},
+		},
+	}}
+	failure = JobFailureFromPods(job, pods)
+	assert.Contains(t, failure.Message, "tools not found")
+}
@@ -8,12 +8,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/samber/lo"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
 )
 
-type Validater func(*v1alpha1.AppDeployment) error
+type Validater func(*v1beta1.AppDeployment) error
 
-func Validate(ap *v1alpha1.AppDeployment) error {
+func Validate(ap *v1beta1.AppDeployment) error {
 	var errs error
 	validaters := []Validater{
 		validateJobSpec,
@@ -24,11 +27,11 @@ func Validate(ap *v1alpha1.AppDeployment) error {
 	return errs
 }
 
-// validateJobSpec validates the container count in the AppDeployment Spec
-// * container count in AppDeployment Spec should be 1
-// * initCountainer is not allowed
-func validateJobSpec(ap *v1alpha1.AppDeployment) error {
-	if equality.Semantic.DeepEqual(ap.Spec, v1alpha1.AppDeploymentSpec{}) {
+// validateJobSpec validates the jobs in the AppDeployment Spec
+// * provision job is required, teardown job is optional
+// * fields of the jobs managed by the controller are not allowed
+func validateJobSpec(ap *v1beta1.AppDeployment) error {
+	if equality.Semantic.DeepEqual(ap.Spec, v1beta1.AppDeploymentSpec{}) {
 		return errors.New("spec of appdeployment is nil")
 	}
 	// provision job must be present
@@ -47,14 +50,28 @@ func validateJobSpec(ap *v1alpha1.AppDeployment) error {
 	return nil
 }
 
+// ValidateResetJob validates the reset job of a cache recycling its operations, it is constrained like the jobs of
+// the applications
+func ValidateResetJob(js batchv1.JobSpec) error {
+	if equality.Semantic.DeepEqual(js, batchv1.JobSpec{}) {
+		return errors.New("reset job is nil")
+	}
+	return jobConstraint(js)
+}
+
 // jobConstraint validates the JobSpec
-// only container is allowed in the JobSpec
+// only the pod template and a curated set of fields are allowed in the JobSpec: activeDeadlineSeconds, backoffLimit,
+// podFailurePolicy and ttlSecondsAfterFinished, the controller defaults the latter two when they are not set. Finished
+// jobs must be kept until the controller read them.
 func jobConstraint(js batchv1.JobSpec) error {
-	if js.ActiveDeadlineSeconds != nil {
-		return fmt.Errorf("activeDeadlineSeconds is not allowed")
+	if js.ActiveDeadlineSeconds != nil && *js.ActiveDeadlineSeconds <= 0 {
+		return fmt.Errorf("activeDeadlineSeconds must be positive")
+	}
+	if js.BackoffLimit != nil && *js.BackoffLimit < 0 {
+		return fmt.Errorf("backoffLimit must not be negative")
 	}
-	if js.BackoffLimit != nil {
-		return fmt.Errorf("backoffLimit is not allowed")
+	if minTTL := config.Current().MinJobTTLSecondsAfterFinished(); js.TTLSecondsAfterFinished != nil && *js.TTLSecondsAfterFinished < minTTL {
+		return fmt.Errorf("ttlSecondsAfterFinished must be at least %d", minTTL)
 	}
 	if js.BackoffLimitPerIndex != nil {
 		return fmt.Errorf("backoffLimitPerIndex is not allowed")
@@ -77,18 +94,12 @@ func jobConstraint(js batchv1.JobSpec) error {
 	if js.Parallelism != nil {
 		return fmt.Errorf("parallelism is not allowed")
 	}
-	if js.PodFailurePolicy != nil {
-		return fmt.Errorf("podFailurePolicy is not allowed")
-	}
 	if js.PodReplacementPolicy != nil {
 		return fmt.Errorf("podReplacementPolicy is not allowed")
 	}
 	if js.Selector != nil {
 		return fmt.Errorf("selector is not allowed")
 	}
-	if js.TTLSecondsAfterFinished != nil {
-		return fmt.Errorf("ttlSecondsAfterFinished is not allowed")
-	}
 	if js.SuccessPolicy != nil {
 		return fmt.Errorf("successPolicy is not allowed")
 	}
@@ -104,6 +115,9 @@ func jobConstraint(js batchv1.JobSpec) error {
 }
 
 // podConstraint validates the PodSpec
+// * at least one container is required, init containers and volumes are allowed
+// * the restart policy is Never, the controller defaults it when it is not set
+// * volume mounts must reference a volume of the pod
 func podConstraint(pt corev1.PodTemplateSpec) error {
 	if len(pt.Name) > 0 {
 		return fmt.Errorf("name is not allowed")
@@ -111,29 +125,36 @@ func podConstraint(pt corev1.PodTemplateSpec) error {
 	if len(pt.Namespace) > 0 {
 		return fmt.Errorf("namespace is not allowed")
 	}
-	if len(pt.Spec.Volumes) > 0 {
-		return fmt.Errorf("volumes are not allowed")
+	// failed containers are not restarted in place, so the pods of every failed attempt are kept with their logs
+	if pt.Spec.RestartPolicy != "" && pt.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		return fmt.Errorf("restartPolicy must be %s", corev1.RestartPolicyNever)
 	}
-	if len(pt.Spec.InitContainers) > 0 {
-		return fmt.Errorf("initContainers are not allowed")
+	if len(pt.Spec.Containers) == 0 {
+		return fmt.Errorf("at least one container is required")
 	}
-	if len(pt.Spec.Containers) != 1 {
-		return fmt.Errorf("container count should be 1")
+	volumes := lo.SliceToMap(pt.Spec.Volumes, func(volume corev1.Volume) (string, bool) { return volume.Name, true })
+	for _, c := range pt.Spec.InitContainers {
+		if err := containerConstraint(c, volumes); err != nil {
+			return fmt.Errorf("init container %s: %w", c.Name, err)
+		}
 	}
-	if containerConstraint(pt.Spec.Containers[0]) != nil {
-		return fmt.Errorf("container: %w", containerConstraint(pt.Spec.Containers[0]))
+	for _, c := range pt.Spec.Containers {
+		if err := containerConstraint(c, volumes); err != nil {
+			return fmt.Errorf("container %s: %w", c.Name, err)
+		}
 	}
 	return nil
 }
 
 // containerConstraint validates the Container
-func containerConstraint(c corev1.Container) error {
+func containerConstraint(c corev1.Container, volumes map[string]bool) error {
 	if c.Image == "" {
 		return fmt.Errorf("image is empty")
 	}
-	if len(c.VolumeMounts) > 0 {
-		return fmt.Errorf("volumeMounts are not allowed")
+	for _, mount := range c.VolumeMounts {
+		if !volumes[mount.Name] {
+			return fmt.Errorf("volumeMount %s references an unknown volume", mount.Name)
+		}
 	}
-
 	return nil
 }
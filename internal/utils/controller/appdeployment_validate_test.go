@@ -8,20 +8,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 )
 
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name    string
-		app     v1alpha1.AppDeployment
+		app     v1beta1.AppDeployment
 		wantErr bool
 	}{
 		{
 			name: "Valid AppDeployment",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -41,14 +41,14 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name:    "Empty Spec",
-			app:     v1alpha1.AppDeployment{},
+			app:     v1beta1.AppDeployment{},
 			wantErr: true,
 		},
 		{
 			name: "Invalid Provision Job",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
-					OpId: "test",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID: "test",
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -77,9 +77,9 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "Invalid TearDown Job",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
-					OpId: "test",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID: "test",
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -112,22 +112,22 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "jobConstraint activeDeadlineSeconds",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "jobConstraint activeDeadlineSeconds must be positive",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
-						ActiveDeadlineSeconds: ptr.Of(int64(1)),
+						ActiveDeadlineSeconds: ptr.Of(int64(0)),
 					},
 				},
 			},
 			wantErr: true,
 		},
 		{
-			name: "jobConstraint backoffLimit",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "jobConstraint backoffLimit must not be negative",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
-						BackoffLimit: ptr.Of(int32(1)),
+						BackoffLimit: ptr.Of(int32(-1)),
 					},
 				},
 			},
@@ -135,8 +135,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint backoffLimitPerIndex",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						BackoffLimitPerIndex: ptr.Of(int32(1)),
 					},
@@ -146,8 +146,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint completions",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Completions: ptr.Of(int32(1)),
 					},
@@ -157,8 +157,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint completionMode",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						CompletionMode: ptr.Of(batchv1.NonIndexedCompletion),
 					},
@@ -168,8 +168,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint managedBy",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						ManagedBy: ptr.Of("test"),
 					},
@@ -179,8 +179,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint manualSelector",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						ManualSelector: ptr.Of(true),
 					},
@@ -190,8 +190,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint maxFailedIndexes",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						MaxFailedIndexes: ptr.Of(int32(1)),
 					},
@@ -201,8 +201,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint parallelism",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Parallelism: ptr.Of(int32(1)),
 					},
@@ -211,26 +211,42 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "jobConstraint podFailurePolicy",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "jobConstraint curated fields are allowed",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
+						ActiveDeadlineSeconds:   ptr.Of(int64(3600)),
+						BackoffLimit:            ptr.Of(int32(2)),
+						TTLSecondsAfterFinished: ptr.Of(int32(3600)),
 						PodFailurePolicy: ptr.Of(batchv1.PodFailurePolicy{
 							Rules: []batchv1.PodFailurePolicyRule{
 								{
 									Action: batchv1.PodFailurePolicyActionFailJob,
+									OnExitCodes: &batchv1.PodFailurePolicyOnExitCodesRequirement{
+										Operator: batchv1.PodFailurePolicyOnExitCodesOpIn,
+										Values:   []int32{42},
+									},
 								},
 							},
 						}),
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Image: "nginx:latest",
+									},
+								},
+							},
+						},
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "jobConstraint podReplacementPolicy",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						PodReplacementPolicy: ptr.Of(batchv1.Failed),
 					},
@@ -240,8 +256,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint Selectors",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Selector: &metav1.LabelSelector{
 							MatchLabels: map[string]string{
@@ -254,11 +270,22 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "jobConstraint TTLSecondsAfterFinished",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "jobConstraint TTLSecondsAfterFinished must not be negative",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					Provision: batchv1.JobSpec{
+						TTLSecondsAfterFinished: ptr.Of(int32(-1)),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "jobConstraint TTLSecondsAfterFinished must outlive the reconciles reading the job",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
-						TTLSecondsAfterFinished: ptr.Of(int32(1)),
+						TTLSecondsAfterFinished: ptr.Of(int32(0)),
 					},
 				},
 			},
@@ -266,8 +293,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint SuccessPolicy",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						CompletionMode: new(batchv1.CompletionMode),
 					},
@@ -277,8 +304,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint Suspend",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Suspend: ptr.Of(true),
 					},
@@ -288,9 +315,9 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "jobConstraint PodTemplate",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
-					OpId: "test",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					OperationID: "test",
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{},
 					},
@@ -300,8 +327,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "podConstraint name",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							ObjectMeta: metav1.ObjectMeta{
@@ -325,8 +352,8 @@ func TestValidate(t *testing.T) {
 		},
 		{
 			name: "podConstraint namespace",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							ObjectMeta: metav1.ObjectMeta{
@@ -349,9 +376,48 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "podConstraint volumes",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "podConstraint restartPolicy other than Never",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					Provision: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								RestartPolicy: corev1.RestartPolicyOnFailure,
+								Containers: []corev1.Container{
+									{
+										Image: "nginx:latest",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "podConstraint restartPolicy is optional",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					Provision: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Image: "nginx:latest",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "podConstraint volumes are allowed",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -373,12 +439,12 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "podConstraint initContainers",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "podConstraint initContainers are allowed",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -403,12 +469,12 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "podConstraint container count should be 1",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "podConstraint multiple containers are allowed",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -431,12 +497,12 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "containerConstraint image is empty",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -455,9 +521,9 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "containerConstraint volumeMounts are not allowed",
-			app: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			name: "containerConstraint volumeMount of an unknown volume",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -481,6 +547,93 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "podConstraint at least one container",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					Provision: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								InitContainers: []corev1.Container{
+									{
+										Image: "nginx:latest",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "containerConstraint init container image is empty",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					Provision: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								InitContainers: []corev1.Container{
+									{
+										Name: "fetch-tools",
+									},
+								},
+								Containers: []corev1.Container{
+									{
+										Image: "nginx:latest",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "containerConstraint volumeMounts of pod volumes are allowed",
+			app: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
+					Provision: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{
+									{
+										Name: "credentials",
+										VolumeSource: corev1.VolumeSource{
+											Projected: &corev1.ProjectedVolumeSource{},
+										},
+									},
+								},
+								InitContainers: []corev1.Container{
+									{
+										Image: "busybox:latest",
+										VolumeMounts: []corev1.VolumeMount{
+											{
+												Name:      "credentials",
+												MountPath: "/credentials",
+											},
+										},
+									},
+								},
+								Containers: []corev1.Container{
+									{
+										Image: "nginx:latest",
+										VolumeMounts: []corev1.VolumeMount{
+											{
+												Name:      "credentials",
+												MountPath: "/credentials",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -3,106 +3,301 @@ package controller
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"math/rand"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/samber/lo"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
+)
+
+var (
+	// DemandWindow is the sliding window the demand for a cache is observed over
+	DemandWindow = time.Hour
+	// DemandBucketSize is the granularity of the demand window
+	DemandBucketSize = 5 * time.Minute
+
+	DefaultMinKeepAliveCount int32 = 0
+	DefaultMaxKeepAliveCount int32 = 5
+	// DefaultMaxReuse is how often an operation of a recycling cache is returned to the pool by default
+	DefaultMaxReuse int32 = 5
 )
 
 type CacheHelper struct{}
 
 func NewCacheHelper() CacheHelper { return CacheHelper{} }
 
-func (c CacheHelper) RandomSelectCachedOperation(cache *v1alpha1.Cache) string {
+func (c CacheHelper) RandomSelectCachedOperation(cache *v1beta1.Cache) string {
 	if len(cache.Status.AvailableCaches) == 0 {
 		return ""
 	}
 	// nolint:gosec, G404 // this is expected PRNG usage
 	return cache.Status.AvailableCaches[rand.Intn(len(cache.Status.AvailableCaches))]
 }
-func (c CacheHelper) DefaultCacheExpireTime() string {
-	// cache expire after 2 hours
-	return time.Now().Add(2 * time.Hour).Format(time.RFC3339)
+
+// ShuffledCachedOperations returns the operations available in the cache in random order
+func (c CacheHelper) ShuffledCachedOperations(cache *v1beta1.Cache) []string {
+	names := append([]string{}, cache.Status.AvailableCaches...)
+	// nolint:gosec, G404 // this is expected PRNG usage
+	rand.Shuffle(len(names), func(i, j int) { names[i], names[j] = names[j], names[i] })
+	return names
 }
 
-type AppCacheField struct {
-	Name         string
-	Image        string
-	Command      []string
-	Args         []string
-	WorkingDir   string
-	Env          []corev1.EnvVar
-	Dependencies []string
+// DefaultCacheExpireTime returns the expire time of a cache created now, after the cache expire time of the controller
+// config
+func (c CacheHelper) DefaultCacheExpireTime() *metav1.Time {
+	return &metav1.Time{Time: time.Now().Add(config.Current().CacheExpireTime.Duration)}
 }
 
-func (c *AppCacheField) NewCacheKey() string {
-	hasher := sha256.New()
-	hasher.Write([]byte(c.Name))
-	hasher.Write([]byte(c.Image))
-	hasher.Write([]byte(strings.Join(c.Command, " ")))
-	hasher.Write([]byte(strings.Join(c.Args, " ")))
-	hasher.Write([]byte(c.WorkingDir))
-
-	// Sort environment variables to ensure consistent hashing
-	sort.Slice(c.Env, func(i, j int) bool {
-		return c.Env[i].Name < c.Env[j].Name
-	})
-	for _, env := range c.Env {
-		hasher.Write([]byte(env.Name + "=" + env.Value))
+// RecordDemand counts a cache hit or a cache miss in the demand bucket of the given time, both are an access of the
+// cache
+func (c CacheHelper) RecordDemand(cache *v1beta1.Cache, hit bool, now time.Time) {
+	cache.Status.LastAccessTime = &metav1.Time{Time: now}
+	start := metav1.NewTime(now.Truncate(DemandBucketSize))
+	buckets := cache.Status.Demand.Buckets
+	if len(buckets) == 0 || !buckets[len(buckets)-1].Start.Equal(&start) {
+		buckets = append(buckets, v1beta1.CacheDemandBucket{Start: start})
+	}
+	if hit {
+		cache.Status.LastAcquisitionTime = &metav1.Time{Time: now}
+		buckets[len(buckets)-1].Acquisitions++
+	} else {
+		buckets[len(buckets)-1].Misses++
 	}
+	cache.Status.Demand.Buckets = buckets
+	c.ObserveDemand(cache, now)
+}
 
-	// Sort dependencies to ensure consistent hashing
-	sort.Strings(c.Dependencies)
-	for _, dep := range c.Dependencies {
-		hasher.Write([]byte(dep))
+// ObserveDemand drops the demand buckets which fell out of the demand window and sums up the remaining ones
+func (c CacheHelper) ObserveDemand(cache *v1beta1.Cache, now time.Time) {
+	windowStart := now.Add(-DemandWindow)
+	demand := v1beta1.CacheDemand{}
+	for _, bucket := range cache.Status.Demand.Buckets {
+		if !bucket.Start.Add(DemandBucketSize).After(windowStart) {
+			continue
+		}
+		demand.Buckets = append(demand.Buckets, bucket)
+		demand.Acquisitions += bucket.Acquisitions
+		demand.Misses += bucket.Misses
+	}
+	cache.Status.Demand = demand
+}
+
+// MaxReuse returns how often an operation of the cache is returned to the pool, 0 if the cache does not recycle
+// released operations
+func (c CacheHelper) MaxReuse(cache *v1beta1.Cache) int32 {
+	if cache.Spec.Recycle == nil {
+		return 0
+	}
+	if cache.Spec.Recycle.MaxReuse != nil {
+		return *cache.Spec.Recycle.MaxReuse
+	}
+	return DefaultMaxReuse
+}
+
+// KeepAliveCountBounds returns the bounds of the keepAliveCount set in the cache spec, or their defaults
+func (c CacheHelper) KeepAliveCountBounds(cache *v1beta1.Cache) (int32, int32) {
+	minCount, maxCount := DefaultMinKeepAliveCount, DefaultMaxKeepAliveCount
+	if cache.Spec.MinKeepAliveCount != nil {
+		minCount = *cache.Spec.MinKeepAliveCount
+	}
+	if cache.Spec.MaxKeepAliveCount != nil {
+		maxCount = *cache.Spec.MaxKeepAliveCount
+	}
+	return minCount, maxCount
+}
+
+// KeepAliveCountFromDemand returns the number of operations requested within the demand window, kept within the bounds
+func (c CacheHelper) KeepAliveCountFromDemand(cache *v1beta1.Cache) int32 {
+	minCount, maxCount := c.KeepAliveCountBounds(cache)
+	requested := cache.Status.Demand.Acquisitions + cache.Status.Demand.Misses
+	return min(max(requested, minCount), maxCount)
+}
+
+const (
+	// CacheKeyVersion prefixes every cache key, bump it whenever the canonical form of an application changes and
+	// teach CacheKeyMatches the previous version so existing keys can be migrated
+	CacheKeyVersion = "v2"
+	// CacheKeyVersionLegacy is the version of the unprefixed keys which only hashed the first provision container
+	CacheKeyVersionLegacy = "v1"
+
+	// cacheKeyVersionSeparator separates the version from the hash, cache keys end up in resource names and label
+	// values which do not allow a colon
+	cacheKeyVersionSeparator = "-"
+
+	// cachedOperationNameHashLength is the number of characters of the hash of the cache key in the names of the
+	// operations of a cache pool
+	cachedOperationNameHashLength = 8
+)
+
+var cacheKeyVersionPattern = regexp.MustCompile(`^(v[0-9]+)-([0-9a-f]+)$`)
+
+// canonicalApplication returns the normalized document of an application its cache key is computed from. The whole
+// application is covered except for the fields listed in its cacheKeyIgnoredFields, environment variables and
+// dependencies are sorted since their order does not change the result of a job.
+func canonicalApplication(app v1beta1.ApplicationSpec) map[string]any {
+	app = *app.DeepCopy()
+	ignoredFields := app.CacheKeyIgnoredFields
+	app.CacheKeyIgnoredFields = nil
+	// the retry budget, the kept failed jobs and the teardown policy do not change the environment
+	app.MaxRetries = nil
+	app.FailedJobsHistoryLimit = nil
+	app.TeardownPolicy = ""
+	sort.Strings(app.Dependencies)
+	for _, job := range []*batchv1.JobSpec{&app.Provision, &app.Teardown} {
+		for _, containers := range [][]corev1.Container{job.Template.Spec.InitContainers, job.Template.Spec.Containers} {
+			for i := range containers {
+				sort.SliceStable(containers[i].Env, func(a, b int) bool {
+					return containers[i].Env[a].Name < containers[i].Env[b].Name
+				})
+			}
+		}
 	}
 
+	// marshalling api types never fails
+	doc := map[string]any{}
+	lo.Must0(json.Unmarshal(lo.Must(json.Marshal(app)), &doc))
+	for _, path := range ignoredFields {
+		removeField(doc, strings.Split(path, "."))
+	}
+	return doc
+}
+
+// removeField removes the field at the path from the document, a list applies the path to all of its elements
+func removeField(node any, path []string) {
+	switch n := node.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			delete(n, path[0])
+			return
+		}
+		removeField(n[path[0]], path[1:])
+	case []any:
+		for _, item := range n {
+			removeField(item, path)
+		}
+	}
+}
+
+// AppCacheKey returns the cache key of a single application
+func (c CacheHelper) AppCacheKey(app v1beta1.ApplicationSpec) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(CacheKeyVersion))
+	// the keys of maps are sorted when marshalled
+	hasher.Write(lo.Must(json.Marshal(canonicalApplication(app))))
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
-func (c CacheHelper) NewCacheKeyFromApplications(apps []v1alpha1.ApplicationSpec) string {
-	// sort the apps by name to ensure consistent hashing
+func (c CacheHelper) NewCacheKeyFromApplications(apps []v1beta1.ApplicationSpec) string {
+	// sort the apps by name to ensure consistent hashing, without reordering the spec of the caller
+	apps = append([]v1beta1.ApplicationSpec{}, apps...)
 	sort.Slice(apps, func(i, j int) bool {
 		return apps[i].Name < apps[j].Name
 	})
 
-	srcCacheKeys := lo.Reduce(apps, func(acc []string, app v1alpha1.ApplicationSpec, index int) []string {
-		return append(acc, c.AppCacheFieldFromApplicationProvision(app).NewCacheKey())
-	}, []string{})
-
 	// get the cache id for the source
 	hasher := sha256.New()
-	for _, id := range srcCacheKeys {
-		hasher.Write([]byte(id))
+	for _, app := range apps {
+		hasher.Write([]byte(c.AppCacheKey(app)))
 	}
-	return hex.EncodeToString(hasher.Sum(nil))
+	return CacheKeyVersion + cacheKeyVersionSeparator + hex.EncodeToString(hasher.Sum(nil))
+}
+
+// CacheName returns the name of the cache requirements look up for a cache key
+func (c CacheHelper) CacheName(key string) string {
+	return "cache-" + key
+}
+
+// CacheKeyLabelValue returns the value of the cache key label, cache keys are longer than label values may be
+func (c CacheHelper) CacheKeyLabelValue(key string) string {
+	if len(key) > MaxResourceNameLength {
+		return key[:MaxResourceNameLength]
+	}
+	return key
+}
+
+// CacheKeyVersionOf returns the version of a cache key, unprefixed keys are legacy keys
+func (c CacheHelper) CacheKeyVersionOf(key string) string {
+	if matches := cacheKeyVersionPattern.FindStringSubmatch(key); matches != nil {
+		return matches[1]
+	}
+	return CacheKeyVersionLegacy
 }
 
-func (c CacheHelper) AppCacheFieldFromApplicationProvision(app v1alpha1.ApplicationSpec) *AppCacheField {
-	return &AppCacheField{
-		Name:         app.Name,
-		Image:        app.Provision.Template.Spec.Containers[0].Image,
-		Command:      app.Provision.Template.Spec.Containers[0].Command,
-		Args:         app.Provision.Template.Spec.Containers[0].Args,
-		WorkingDir:   app.Provision.Template.Spec.Containers[0].WorkingDir,
-		Env:          app.Provision.Template.Spec.Containers[0].Env,
-		Dependencies: app.Dependencies,
+// CachedOperationName returns the name of a new operation of the pool of a cache, made of the first characters of the
+// hash of the cache key, without its version prefix, and a random suffix
+func (c CacheHelper) CachedOperationName(key, suffix string) string {
+	hash := key
+	if matches := cacheKeyVersionPattern.FindStringSubmatch(key); matches != nil {
+		hash = matches[2]
+	}
+	if len(hash) > cachedOperationNameHashLength {
+		hash = hash[:cachedOperationNameHashLength]
 	}
+	return "cached-operation-" + hash + "-" + suffix
+}
+
+// IsCurrentCacheKey returns true if the cache key was computed by the current version of the algorithm
+func (c CacheHelper) IsCurrentCacheKey(key string) bool {
+	return c.CacheKeyVersionOf(key) == CacheKeyVersion
 }
 
-func (c CacheHelper) AppCacheFieldFromApplicationTeardown(app v1alpha1.ApplicationSpec) *AppCacheField {
-	return &AppCacheField{
-		Name:         app.Name,
-		Image:        app.Teardown.Template.Spec.Containers[0].Image,
-		Command:      app.Teardown.Template.Spec.Containers[0].Command,
-		Args:         app.Teardown.Template.Spec.Containers[0].Args,
-		WorkingDir:   app.Teardown.Template.Spec.Containers[0].WorkingDir,
-		Env:          app.Teardown.Template.Spec.Containers[0].Env,
-		Dependencies: app.Dependencies,
+// CacheKeyMatches returns true if the cache key, of the current or an older version, was computed from the applications.
+// Keys of an older version which match only have to be re-keyed, the environments they identify are still valid.
+func (c CacheHelper) CacheKeyMatches(key string, apps []v1beta1.ApplicationSpec) bool {
+	switch c.CacheKeyVersionOf(key) {
+	case CacheKeyVersion:
+		return key == c.NewCacheKeyFromApplications(apps)
+	case CacheKeyVersionLegacy:
+		return key == legacyCacheKeyFromApplications(apps)
+	default:
+		return false
 	}
 }
+
+// legacyCacheKeyFromApplications computes the unprefixed v1 cache key, which only covers the name, image, command,
+// args, working dir and literal env values of the first provision container and the dependencies
+func legacyCacheKeyFromApplications(apps []v1beta1.ApplicationSpec) string {
+	apps = append([]v1beta1.ApplicationSpec{}, apps...)
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+	hasher := sha256.New()
+	for _, app := range apps {
+		hasher.Write([]byte(legacyAppCacheKey(app)))
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func legacyAppCacheKey(app v1beta1.ApplicationSpec) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(app.Name))
+	if len(app.Provision.Template.Spec.Containers) > 0 {
+		container := app.Provision.Template.Spec.Containers[0]
+		hasher.Write([]byte(container.Image))
+		hasher.Write([]byte(strings.Join(container.Command, " ")))
+		hasher.Write([]byte(strings.Join(container.Args, " ")))
+		hasher.Write([]byte(container.WorkingDir))
+		env := append([]corev1.EnvVar{}, container.Env...)
+		sort.Slice(env, func(i, j int) bool {
+			return env[i].Name < env[j].Name
+		})
+		for _, e := range env {
+			hasher.Write([]byte(e.Name + "=" + e.Value))
+		}
+	}
+	dependencies := append([]string{}, app.Dependencies...)
+	sort.Strings(dependencies)
+	for _, dep := range dependencies {
+		hasher.Write([]byte(dep))
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
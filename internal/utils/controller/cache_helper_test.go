@@ -2,13 +2,17 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 )
 
 var cacheHelper = NewCacheHelper()
@@ -31,8 +35,8 @@ func TestRandomSelectCachedOperation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// ...existing setup code if any...
-			cacheInstance := &v1alpha1.Cache{
-				Status: v1alpha1.CacheStatus{
+			cacheInstance := &v1beta1.Cache{
+				Status: v1beta1.CacheStatus{
 					AvailableCaches: tt.caches,
 				},
 			}
@@ -48,94 +52,31 @@ func TestRandomSelectCachedOperation(t *testing.T) {
 		})
 	}
 }
-func TestNewCacheKey(t *testing.T) {
-	tests := []struct {
-		name     string
-		source   AppCacheField
-		expected string
-	}{
-		{
-			name: "basic",
-			source: AppCacheField{
-				Name:       "test-app-1",
-				Image:      "nginx:latest",
-				Command:    []string{"nginx"},
-				Args:       []string{"-g", "daemon off;"},
-				WorkingDir: "/",
-				Env: []corev1.EnvVar{
-					{Name: "ENV_VAR1", Value: "value1"},
-					{Name: "ENV_VAR2", Value: "value2"},
-				},
-			},
-			expected: "126075cdb21390e83c4703ace30f4e028568f38c193c9075171fea907570d23e", // Set the expected value after running the test once
-		},
-		{
-			name: "basic",
-			source: AppCacheField{
-				Name:       "test-app-1",
-				Image:      "nginx:latest",
-				Command:    []string{"nginx"},
-				Args:       []string{"-g", "daemon off;"},
-				WorkingDir: "/",
-				Env: []corev1.EnvVar{
-					{Name: "ENV_VAR2", Value: "value2"},
-					{Name: "ENV_VAR1", Value: "value1"},
-				},
-			},
-			expected: "126075cdb21390e83c4703ace30f4e028568f38c193c9075171fea907570d23e", // Set the expected value after running the test once
-		},
-		{
-			name: "basic with dependencies",
-			source: AppCacheField{
-				Name:       "test-app-2",
-				Image:      "nginx:latest",
-				Command:    []string{"nginx"},
-				Args:       []string{"-g", "daemon off;"},
-				WorkingDir: "/",
-				Env: []corev1.EnvVar{
-					{Name: "ENV_VAR1", Value: "value1"},
-					{Name: "ENV_VAR2", Value: "value2"},
-				},
-				Dependencies: []string{"test-app-1"},
-			},
-			expected: "b5eab152dfbbb5d9d0f0b6c2920204b72991a86af0d6ebd673aaf4b80761b207",
-		},
-		{
-			name: "basic with dependencies",
-			source: AppCacheField{
-				Name:       "test-app-2",
-				Image:      "nginx:latest",
-				Command:    []string{"nginx"},
-				Args:       []string{"-g", "daemon off;"},
-				WorkingDir: "/",
-				Env: []corev1.EnvVar{
-					{Name: "ENV_VAR2", Value: "value2"},
-					{Name: "ENV_VAR1", Value: "value1"},
-				},
-				Dependencies: []string{"test-app-1"},
-			},
-			expected: "b5eab152dfbbb5d9d0f0b6c2920204b72991a86af0d6ebd673aaf4b80761b207",
+func TestShuffledCachedOperations(t *testing.T) {
+	caches := []string{"cache1", "cache2", "cache3"}
+	cacheInstance := &v1beta1.Cache{
+		Status: v1beta1.CacheStatus{
+			AvailableCaches: caches,
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			actual := tt.source.NewCacheKey()
-
-			assert.Equal(t, tt.expected, actual)
-		})
-	}
+	result := cacheHelper.ShuffledCachedOperations(cacheInstance)
+	require.ElementsMatch(t, caches, result)
+	require.Equal(t, []string{"cache1", "cache2", "cache3"}, cacheInstance.Status.AvailableCaches)
+	require.Empty(t, cacheHelper.ShuffledCachedOperations(&v1beta1.Cache{}))
 }
 
 func TestNewCacheKeyFromApplications(t *testing.T) {
 	tests := []struct {
 		name     string
-		source   []v1alpha1.ApplicationSpec
+		source   []v1beta1.ApplicationSpec
 		expected string
+		// legacy is the v1 key of the source, computed before cache keys were versioned
+		legacy string
 	}{
 		{
 			name: "basic",
-			source: []v1alpha1.ApplicationSpec{
+			source: []v1beta1.ApplicationSpec{
 				{
 					Name: "test-app-1",
 					Provision: batchv1.JobSpec{
@@ -152,11 +93,12 @@ func TestNewCacheKeyFromApplications(t *testing.T) {
 					},
 				},
 			},
-			expected: "fac2c1ee35f29e0cf01df3d2d087aa63f816cddcf37519b86f9788a8fe437db0",
+			expected: "v2-22e547ef47d8e67acb0c8b175dc231eab23c6aa1ec3a189dfb916c8c96262c24",
+			legacy:   "fac2c1ee35f29e0cf01df3d2d087aa63f816cddcf37519b86f9788a8fe437db0",
 		},
 		{
 			name: "basic with dependencies",
-			source: []v1alpha1.ApplicationSpec{
+			source: []v1beta1.ApplicationSpec{
 				{
 					Name: "test-app-1",
 					Provision: batchv1.JobSpec{
@@ -189,12 +131,13 @@ func TestNewCacheKeyFromApplications(t *testing.T) {
 					Dependencies: []string{"test-app-1"},
 				},
 			},
-			expected: "4ad519825833e792749137e9ff7ad3efcab0907f9d3adc8751eef2cf871648a4",
+			expected: "v2-f09d59515d193d12b66bfd91524ddcb11674a158e635631968051cee482944a2",
+			legacy:   "4ad519825833e792749137e9ff7ad3efcab0907f9d3adc8751eef2cf871648a4",
 		},
 
 		{
 			name: "basic with multiple dependencies",
-			source: []v1alpha1.ApplicationSpec{
+			source: []v1beta1.ApplicationSpec{
 				{
 					Name: "test-app-1",
 					Provision: batchv1.JobSpec{
@@ -243,11 +186,12 @@ func TestNewCacheKeyFromApplications(t *testing.T) {
 					Dependencies: []string{"test-app-1", "test-app-2"},
 				},
 			},
-			expected: "d9dc8143d50f0758ab226d2a199d3f884569750fa7c0a1928b2597af3eb3f7f0",
+			expected: "v2-4d2fb5270183c7a355dcc9685a8dd6615917c4a66aa59c7e97ff93c092bb7bfa",
+			legacy:   "d9dc8143d50f0758ab226d2a199d3f884569750fa7c0a1928b2597af3eb3f7f0",
 		},
 		{
 			name: "basic with multiple dependencies and different order",
-			source: []v1alpha1.ApplicationSpec{
+			source: []v1beta1.ApplicationSpec{
 				{
 					Name: "test-app-1",
 					Provision: batchv1.JobSpec{
@@ -297,7 +241,8 @@ func TestNewCacheKeyFromApplications(t *testing.T) {
 				},
 			},
 
-			expected: "d9dc8143d50f0758ab226d2a199d3f884569750fa7c0a1928b2597af3eb3f7f0",
+			expected: "v2-4d2fb5270183c7a355dcc9685a8dd6615917c4a66aa59c7e97ff93c092bb7bfa",
+			legacy:   "d9dc8143d50f0758ab226d2a199d3f884569750fa7c0a1928b2597af3eb3f7f0",
 		},
 	}
 
@@ -306,172 +251,239 @@ func TestNewCacheKeyFromApplications(t *testing.T) {
 			actual := cacheHelper.NewCacheKeyFromApplications(tt.source)
 
 			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.legacy, legacyCacheKeyFromApplications(tt.source))
+			assert.True(t, cacheHelper.CacheKeyMatches(tt.expected, tt.source))
+			assert.True(t, cacheHelper.CacheKeyMatches(tt.legacy, tt.source))
 		})
 	}
 }
 
-func TestAppCacheFieldFromApplicationTeardown(t *testing.T) {
-	tests := []struct {
-		name     string
-		app      v1alpha1.ApplicationSpec
-		expected AppCacheField
-	}{
-		{
-			name: "basic teardown",
-			app: v1alpha1.ApplicationSpec{
-				Name: "test-app-1",
-				Teardown: batchv1.JobSpec{
-					Template: corev1.PodTemplateSpec{
-						Spec: corev1.PodSpec{
-							Containers: []corev1.Container{
-								{
-									Image:      "nginx:cleanup",
-									Command:    []string{"cleanup"},
-									Args:       []string{"--all"},
-									WorkingDir: "/cleanup",
-									Env: []corev1.EnvVar{
-										{Name: "CLEANUP_MODE", Value: "full"},
-										{Name: "DEBUG", Value: "true"},
-									},
-								},
-							},
-						},
+func TestAppCacheKey(t *testing.T) {
+	newApp := func() v1beta1.ApplicationSpec {
+		return v1beta1.ApplicationSpec{
+			Name: "test-app",
+			Provision: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:  "provision",
+							Image: "nginx:latest",
+							Env:   []corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "b"}},
+						}},
 					},
 				},
-				Dependencies: []string{"dep-1", "dep-2"},
-			},
-			expected: AppCacheField{
-				Name:         "test-app-1",
-				Image:        "nginx:cleanup",
-				Command:      []string{"cleanup"},
-				Args:         []string{"--all"},
-				WorkingDir:   "/cleanup",
-				Env:          []corev1.EnvVar{{Name: "CLEANUP_MODE", Value: "full"}, {Name: "DEBUG", Value: "true"}},
-				Dependencies: []string{"dep-1", "dep-2"},
 			},
-		},
-		{
-			name: "teardown with empty fields",
-			app: v1alpha1.ApplicationSpec{
-				Name: "test-app-minimal",
-				Teardown: batchv1.JobSpec{
-					Template: corev1.PodTemplateSpec{
-						Spec: corev1.PodSpec{
-							Containers: []corev1.Container{
-								{
-									Image: "minimal:latest",
-								},
-							},
-						},
+			Teardown: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "teardown", Image: "nginx:latest"}},
 					},
 				},
 			},
-			expected: AppCacheField{
-				Name:         "test-app-minimal",
-				Image:        "minimal:latest",
-				Command:      nil,
-				Args:         nil,
-				WorkingDir:   "",
-				Env:          nil,
-				Dependencies: nil,
-			},
-		},
+			Dependencies: []string{"dep-1", "dep-2"},
+		}
 	}
+	baseKey := cacheHelper.AppCacheKey(newApp())
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			actual := cacheHelper.AppCacheFieldFromApplicationTeardown(tt.app)
-
-			assert.Equal(t, tt.expected.Name, actual.Name)
-			assert.Equal(t, tt.expected.Image, actual.Image)
-			assert.Equal(t, tt.expected.Command, actual.Command)
-			assert.Equal(t, tt.expected.Args, actual.Args)
-			assert.Equal(t, tt.expected.WorkingDir, actual.WorkingDir)
-			assert.Equal(t, tt.expected.Env, actual.Env)
-			assert.Equal(t, tt.expected.Dependencies, actual.Dependencies)
+	changes := map[string]func(app *v1beta1.ApplicationSpec){
+		"envFrom": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.Containers[0].EnvFrom = []corev1.EnvFromSource{{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}},
+			}}
+		},
+		"valueFrom": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.Containers[0].Env[0] = corev1.EnvVar{Name: "A", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "secret"}, Key: "a"},
+			}}
+		},
+		"resources": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+		},
+		"service account": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.ServiceAccountName = "provisioner"
+		},
+		"node selector": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.NodeSelector = map[string]string{"pool": "gpu"}
+		},
+		"sidecar": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.Containers = append(app.Provision.Template.Spec.Containers, corev1.Container{Name: "credentials", Image: "sidecar:latest"})
+		},
+		"init container": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.InitContainers = []corev1.Container{{Name: "fetch-tools", Image: "tools:latest"}}
+		},
+		"volumes": func(app *v1beta1.ApplicationSpec) {
+			app.Provision.Template.Spec.Volumes = []corev1.Volume{{Name: "credentials", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{}}}}
+		},
+		"teardown job": func(app *v1beta1.ApplicationSpec) {
+			app.Teardown.Template.Spec.Containers[0].Image = "nginx:1.27"
+		},
+		"dependencies": func(app *v1beta1.ApplicationSpec) {
+			app.Dependencies = []string{"dep-1"}
+		},
+	}
+	for name, change := range changes {
+		t.Run(name+" changes the key", func(t *testing.T) {
+			app := newApp()
+			change(&app)
+			assert.NotEqual(t, baseKey, cacheHelper.AppCacheKey(app))
 		})
 	}
+
+	t.Run("order of env and dependencies does not change the key", func(t *testing.T) {
+		app := newApp()
+		app.Provision.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "B", Value: "b"}, {Name: "A", Value: "a"}}
+		app.Dependencies = []string{"dep-2", "dep-1"}
+		assert.Equal(t, baseKey, cacheHelper.AppCacheKey(app))
+		// the spec itself is not reordered
+		assert.Equal(t, "B", app.Provision.Template.Spec.Containers[0].Env[0].Name)
+		assert.Equal(t, []string{"dep-2", "dep-1"}, app.Dependencies)
+	})
+
+	t.Run("retry budget does not change the key", func(t *testing.T) {
+		app := newApp()
+		app.MaxRetries = ptr.Of(int32(10))
+		app.FailedJobsHistoryLimit = ptr.Of(int32(1))
+		assert.Equal(t, baseKey, cacheHelper.AppCacheKey(app))
+	})
+
+	t.Run("teardown policy does not change the key", func(t *testing.T) {
+		app := newApp()
+		app.TeardownPolicy = v1beta1.TeardownPolicyOrphan
+		assert.Equal(t, baseKey, cacheHelper.AppCacheKey(app))
+	})
+
+	t.Run("ignored fields do not change the key", func(t *testing.T) {
+		ignoring := newApp()
+		ignoring.CacheKeyIgnoredFields = []string{"provision.template.spec.containers.resources", "teardown", "unknown.field"}
+		ignoringKey := cacheHelper.AppCacheKey(ignoring)
+
+		app := ignoring.DeepCopy()
+		app.Provision.Template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+		app.Teardown.Template.Spec.Containers[0].Image = "nginx:1.27"
+		assert.Equal(t, ignoringKey, cacheHelper.AppCacheKey(*app))
+
+		app.Provision.Template.Spec.Containers[0].Image = "nginx:1.27"
+		assert.NotEqual(t, ignoringKey, cacheHelper.AppCacheKey(*app))
+	})
 }
 
-func TestAppCacheFieldFromApplicationProvision(t *testing.T) {
-	tests := []struct {
-		name     string
-		app      v1alpha1.ApplicationSpec
-		expected AppCacheField
-	}{
-		{
-			name: "basic provision",
-			app: v1alpha1.ApplicationSpec{
-				Name: "test-app-1",
-				Provision: batchv1.JobSpec{
-					Template: corev1.PodTemplateSpec{
-						Spec: corev1.PodSpec{
-							Containers: []corev1.Container{
-								{
-									Image:      "nginx:latest",
-									Command:    []string{"nginx"},
-									Args:       []string{"-g", "daemon off;"},
-									WorkingDir: "/app",
-									Env: []corev1.EnvVar{
-										{Name: "ENV_VAR1", Value: "value1"},
-										{Name: "ENV_VAR2", Value: "value2"},
-									},
-								},
-							},
-						},
-					},
-				},
-				Dependencies: []string{"dep-1", "dep-2"},
-			},
-			expected: AppCacheField{
-				Name:         "test-app-1",
-				Image:        "nginx:latest",
-				Command:      []string{"nginx"},
-				Args:         []string{"-g", "daemon off;"},
-				WorkingDir:   "/app",
-				Env:          []corev1.EnvVar{{Name: "ENV_VAR1", Value: "value1"}, {Name: "ENV_VAR2", Value: "value2"}},
-				Dependencies: []string{"dep-1", "dep-2"},
+func TestCacheKeyVersion(t *testing.T) {
+	apps := []v1beta1.ApplicationSpec{{
+		Name: "test-app",
+		Provision: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "nginx:latest"}}},
 			},
 		},
-		{
-			name: "provision with empty fields",
-			app: v1alpha1.ApplicationSpec{
-				Name: "test-app-minimal",
-				Provision: batchv1.JobSpec{
-					Template: corev1.PodTemplateSpec{
-						Spec: corev1.PodSpec{
-							Containers: []corev1.Container{
-								{
-									Image: "minimal:latest",
-								},
-							},
-						},
-					},
+	}}
+	key := cacheHelper.NewCacheKeyFromApplications(apps)
+	legacyKey := legacyCacheKeyFromApplications(apps)
+
+	assert.Equal(t, CacheKeyVersion, cacheHelper.CacheKeyVersionOf(key))
+	assert.True(t, cacheHelper.IsCurrentCacheKey(key))
+	assert.Equal(t, CacheKeyVersionLegacy, cacheHelper.CacheKeyVersionOf(legacyKey))
+	assert.False(t, cacheHelper.IsCurrentCacheKey(legacyKey))
+	assert.Equal(t, "v3", cacheHelper.CacheKeyVersionOf("v3-abcdef"))
+
+	assert.False(t, cacheHelper.CacheKeyMatches("", apps))
+	assert.False(t, cacheHelper.CacheKeyMatches("v3-abcdef", apps))
+	changed := []v1beta1.ApplicationSpec{*apps[0].DeepCopy()}
+	changed[0].Provision.Template.Spec.Containers[0].Image = "nginx:1.27"
+	assert.False(t, cacheHelper.CacheKeyMatches(key, changed))
+	assert.False(t, cacheHelper.CacheKeyMatches(legacyKey, changed))
+	// the legacy key did not cover the service account, so it still matches
+	changed[0].Provision.Template.Spec.Containers[0].Image = "nginx:latest"
+	changed[0].Provision.Template.Spec.ServiceAccountName = "provisioner"
+	assert.False(t, cacheHelper.CacheKeyMatches(key, changed))
+	assert.True(t, cacheHelper.CacheKeyMatches(legacyKey, changed))
+}
+
+func TestCachedOperationName(t *testing.T) {
+	assert.Equal(t, "cached-operation-0123abcd-xyz12", cacheHelper.CachedOperationName("v2-0123abcdef456789", "xyz12"))
+	assert.Equal(t, "cached-operation-0123abcd-xyz12", cacheHelper.CachedOperationName("0123abcdef456789", "xyz12"))
+	// short legacy keys are not sliced beyond their length
+	assert.Equal(t, "cached-operation-abc-xyz12", cacheHelper.CachedOperationName("abc", "xyz12"))
+	assert.Equal(t, "cached-operation--xyz12", cacheHelper.CachedOperationName("", "xyz12"))
+}
+
+func TestRecordDemand(t *testing.T) {
+	now := time.Now()
+	cache := &v1beta1.Cache{}
+
+	cacheHelper.RecordDemand(cache, true, now)
+	cacheHelper.RecordDemand(cache, false, now)
+	cacheHelper.RecordDemand(cache, true, now)
+	require.Len(t, cache.Status.Demand.Buckets, 1)
+	assert.Equal(t, int32(2), cache.Status.Demand.Acquisitions)
+	assert.Equal(t, int32(1), cache.Status.Demand.Misses)
+	require.NotNil(t, cache.Status.LastAccessTime)
+	assert.True(t, now.Equal(cache.Status.LastAccessTime.Time))
+
+	// a new bucket is started once the bucket size elapsed
+	cacheHelper.RecordDemand(cache, false, now.Add(DemandBucketSize))
+	require.Len(t, cache.Status.Demand.Buckets, 2)
+	// only acquisitions count as the last acquisition
+	require.NotNil(t, cache.Status.LastAcquisitionTime)
+	assert.True(t, now.Equal(cache.Status.LastAcquisitionTime.Time))
+	assert.Equal(t, int32(2), cache.Status.Demand.Acquisitions)
+	assert.Equal(t, int32(2), cache.Status.Demand.Misses)
+
+	// buckets fall out of the window
+	cacheHelper.RecordDemand(cache, true, now.Add(DemandWindow+2*DemandBucketSize))
+	require.Len(t, cache.Status.Demand.Buckets, 1)
+	assert.Equal(t, int32(1), cache.Status.Demand.Acquisitions)
+	assert.Equal(t, int32(0), cache.Status.Demand.Misses)
+}
+
+func TestObserveDemand(t *testing.T) {
+	now := time.Now()
+	cache := &v1beta1.Cache{
+		Status: v1beta1.CacheStatus{
+			Demand: v1beta1.CacheDemand{
+				Acquisitions: 100,
+				Buckets: []v1beta1.CacheDemandBucket{
+					{Start: metav1.NewTime(now.Add(-DemandWindow - DemandBucketSize)), Acquisitions: 5},
+					{Start: metav1.NewTime(now.Add(-DemandWindow + DemandBucketSize)), Acquisitions: 1, Misses: 2},
+					{Start: metav1.NewTime(now), Misses: 3},
 				},
 			},
-			expected: AppCacheField{
-				Name:         "test-app-minimal",
-				Image:        "minimal:latest",
-				Command:      nil,
-				Args:         nil,
-				WorkingDir:   "",
-				Env:          nil,
-				Dependencies: nil,
-			},
 		},
 	}
+	cacheHelper.ObserveDemand(cache, now)
+	assert.Len(t, cache.Status.Demand.Buckets, 2)
+	assert.Equal(t, int32(1), cache.Status.Demand.Acquisitions)
+	assert.Equal(t, int32(5), cache.Status.Demand.Misses)
+
+	cacheHelper.ObserveDemand(cache, now.Add(2*DemandWindow))
+	assert.Empty(t, cache.Status.Demand.Buckets)
+	assert.Equal(t, int32(0), cache.Status.Demand.Acquisitions+cache.Status.Demand.Misses)
+}
 
+func TestKeepAliveCountFromDemand(t *testing.T) {
+	minCount, maxCount := int32(1), int32(3)
+	tests := []struct {
+		name     string
+		spec     v1beta1.CacheSpec
+		demand   v1beta1.CacheDemand
+		expected int32
+	}{
+		{"defaults without demand", v1beta1.CacheSpec{}, v1beta1.CacheDemand{}, DefaultMinKeepAliveCount},
+		{"defaults with demand", v1beta1.CacheSpec{}, v1beta1.CacheDemand{Acquisitions: 2, Misses: 1}, 3},
+		{"raised to lower bound", v1beta1.CacheSpec{MinKeepAliveCount: &minCount}, v1beta1.CacheDemand{}, 1},
+		{"cut to upper bound", v1beta1.CacheSpec{MaxKeepAliveCount: &maxCount}, v1beta1.CacheDemand{Acquisitions: 10}, 3},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := cacheHelper.AppCacheFieldFromApplicationProvision(tt.app)
-
-			assert.Equal(t, tt.expected.Name, actual.Name)
-			assert.Equal(t, tt.expected.Image, actual.Image)
-			assert.Equal(t, tt.expected.Command, actual.Command)
-			assert.Equal(t, tt.expected.Args, actual.Args)
-			assert.Equal(t, tt.expected.WorkingDir, actual.WorkingDir)
-			assert.Equal(t, tt.expected.Env, actual.Env)
-			assert.Equal(t, tt.expected.Dependencies, actual.Dependencies)
+			cache := &v1beta1.Cache{Spec: tt.spec, Status: v1beta1.CacheStatus{Demand: tt.demand}}
+			assert.Equal(t, tt.expected, cacheHelper.KeepAliveCountFromDemand(cache))
 		})
 	}
 }
+
+func TestMaxReuse(t *testing.T) {
+	assert.Equal(t, int32(0), cacheHelper.MaxReuse(&v1beta1.Cache{}))
+	cache := &v1beta1.Cache{Spec: v1beta1.CacheSpec{Recycle: &v1beta1.CacheRecycle{}}}
+	assert.Equal(t, DefaultMaxReuse, cacheHelper.MaxReuse(cache))
+	cache.Spec.Recycle.MaxReuse = ptr.Of(int32(2))
+	assert.Equal(t, int32(2), cacheHelper.MaxReuse(cache))
+}
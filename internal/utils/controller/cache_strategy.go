@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+var (
+	ErrUnknownCacheStrategy = errors.New("unknown cache strategy")
+
+	// AdaptiveIdlePeriod is how long a pool has to go without any operation being acquired or the pool
+	// being resized before the adaptive strategy shrinks it
+	AdaptiveIdlePeriod = 30 * time.Minute
+)
+
+// CacheStrategy decides how many ready operations a cache keeps in its pool.
+type CacheStrategy interface {
+	// PoolSize returns the number of ready operations the cache should keep, given the
+	// operations currently owned by the cache. The cache status still holds the values
+	// observed in the previous reconcile when it is called.
+	PoolSize(cache *v1beta1.Cache, ownedOps []v1beta1.Operation) int32
+}
+
+// NewCacheStrategy returns the strategy named in the cache spec, an empty name selects the fixed strategy.
+func NewCacheStrategy(name string) (CacheStrategy, error) {
+	switch name {
+	case "", v1beta1.CacheStrategyFixed:
+		return FixedCacheStrategy{}, nil
+	case v1beta1.CacheStrategyOnDemand:
+		return OnDemandCacheStrategy{}, nil
+	case v1beta1.CacheStrategyAdaptive:
+		return AdaptiveCacheStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCacheStrategy, name)
+	}
+}
+
+// FixedCacheStrategy always keeps keepAliveCount operations warm.
+type FixedCacheStrategy struct{}
+
+func (FixedCacheStrategy) PoolSize(cache *v1beta1.Cache, _ []v1beta1.Operation) int32 {
+	return cache.Status.KeepAliveCount
+}
+
+// OnDemandCacheStrategy keeps no warm pool at all, requirements create their own operation on a cache miss.
+type OnDemandCacheStrategy struct{}
+
+func (OnDemandCacheStrategy) PoolSize(_ *v1beta1.Cache, _ []v1beta1.Operation) int32 {
+	return 0
+}
+
+// AdaptiveCacheStrategy starts with a single warm operation and doubles the pool every time it is
+// drained, up to keepAliveCount. When no operation was acquired from the pool and the pool was not
+// resized for AdaptiveIdlePeriod, it shrinks by one, so at most once per idle period.
+type AdaptiveCacheStrategy struct{}
+
+func (AdaptiveCacheStrategy) PoolSize(cache *v1beta1.Cache, ownedOps []v1beta1.Operation) int32 {
+	maxSize := cache.Status.KeepAliveCount
+	if maxSize <= 0 {
+		return 0
+	}
+	size := min(max(cache.Status.PoolSize, 1), maxSize)
+
+	oputils := NewOperationHelper()
+	ready := []v1beta1.Operation{}
+	for _, op := range ownedOps {
+		if oputils.IsOperationAvailable(&op) {
+			ready = append(ready, op)
+		}
+	}
+
+	switch {
+	case len(ready) == 0 && len(cache.Status.AvailableCaches) > 0:
+		// every operation that was available in the last reconcile has been acquired
+		return min(size*2, maxSize)
+	case len(ready) >= int(size) && idleFor(cache, AdaptiveIdlePeriod):
+		return max(size-1, 1)
+	}
+	return size
+}
+
+// idleFor returns true if no operation was acquired from the cache and its pool was not resized for the given
+// period. The age of the operations does not tell, operations returned to the pool keep their creation time.
+func idleFor(cache *v1beta1.Cache, d time.Duration) bool {
+	last := cache.CreationTimestamp.Time
+	for _, t := range []*metav1.Time{cache.Status.LastAcquisitionTime, cache.Status.PoolResizeTime} {
+		if t != nil && t.After(last) {
+			last = t.Time
+		}
+	}
+	return time.Since(last) >= d
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+func TestNewCacheStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		expected CacheStrategy
+		wantErr  bool
+	}{
+		{"empty defaults to fixed", "", FixedCacheStrategy{}, false},
+		{"fixed", v1beta1.CacheStrategyFixed, FixedCacheStrategy{}, false},
+		{"on-demand", v1beta1.CacheStrategyOnDemand, OnDemandCacheStrategy{}, false},
+		{"adaptive", v1beta1.CacheStrategyAdaptive, AdaptiveCacheStrategy{}, false},
+		{"unknown", "lru", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewCacheStrategy(tt.strategy)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrUnknownCacheStrategy)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy)
+		})
+	}
+}
+
+func newStrategyTestOperation(phase string, age time.Duration) v1beta1.Operation {
+	return v1beta1.Operation{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Status: v1beta1.OperationStatus{Phase: phase},
+	}
+}
+
+func TestFixedAndOnDemandCacheStrategy(t *testing.T) {
+	cache := &v1beta1.Cache{Status: v1beta1.CacheStatus{KeepAliveCount: 5}}
+	assert.Equal(t, int32(5), FixedCacheStrategy{}.PoolSize(cache, nil))
+	assert.Equal(t, int32(0), OnDemandCacheStrategy{}.PoolSize(cache, nil))
+}
+
+func TestAdaptiveCacheStrategy(t *testing.T) {
+	ready := newStrategyTestOperation(v1beta1.OperationPhaseReconciled, time.Minute)
+	idle := newStrategyTestOperation(v1beta1.OperationPhaseReconciled, 2*AdaptiveIdlePeriod)
+	reconciling := newStrategyTestOperation(v1beta1.OperationPhaseReconciling, time.Minute)
+	recently := &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	longAgo := &metav1.Time{Time: time.Now().Add(-2 * AdaptiveIdlePeriod)}
+
+	tests := []struct {
+		name     string
+		status   v1beta1.CacheStatus
+		ops      []v1beta1.Operation
+		expected int32
+	}{
+		{
+			name:     "starts with a single operation",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5},
+			expected: 1,
+		},
+		{
+			name:     "keepAliveCount zero disables the pool",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 0, PoolSize: 3},
+			expected: 0,
+		},
+		{
+			name:     "doubles when drained",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 2, AvailableCaches: []string{"op1", "op2"}},
+			ops:      []v1beta1.Operation{reconciling},
+			expected: 4,
+		},
+		{
+			name:     "growth is capped by keepAliveCount",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 4, AvailableCaches: []string{"op1"}},
+			expected: 5,
+		},
+		{
+			name:     "not drained while provisioning the first operations",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 2},
+			ops:      []v1beta1.Operation{reconciling, reconciling},
+			expected: 2,
+		},
+		{
+			name:     "shrinks when idle",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 2, AvailableCaches: []string{"op1", "op2"}},
+			ops:      []v1beta1.Operation{idle, idle},
+			expected: 1,
+		},
+		{
+			name:     "keeps size when recently acquired from",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 2, AvailableCaches: []string{"op1", "op2"}, LastAcquisitionTime: recently},
+			ops:      []v1beta1.Operation{idle, ready},
+			expected: 2,
+		},
+		{
+			name:     "keeps size when recently resized",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 2, AvailableCaches: []string{"op1", "op2"}, PoolResizeTime: recently},
+			ops:      []v1beta1.Operation{idle, idle},
+			expected: 2,
+		},
+		{
+			name:     "shrinks when last acquired and resized before the idle period",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 2, AvailableCaches: []string{"op1", "op2"}, LastAcquisitionTime: longAgo, PoolResizeTime: longAgo},
+			ops:      []v1beta1.Operation{ready, ready},
+			expected: 1,
+		},
+		{
+			name:     "never shrinks below one",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 5, PoolSize: 1, AvailableCaches: []string{"op1"}},
+			ops:      []v1beta1.Operation{idle},
+			expected: 1,
+		},
+		{
+			name:     "follows a lowered keepAliveCount",
+			status:   v1beta1.CacheStatus{KeepAliveCount: 2, PoolSize: 4, AvailableCaches: []string{"op1"}},
+			ops:      []v1beta1.Operation{ready},
+			expected: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &v1beta1.Cache{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: *longAgo}, Status: tt.status}
+			assert.Equal(t, tt.expected, AdaptiveCacheStrategy{}.PoolSize(cache, tt.ops))
+		})
+	}
+}
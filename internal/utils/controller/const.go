@@ -5,8 +5,16 @@ const (
 
 	AnnotationNameCacheMode = "operation-cache-controller.azure.github.com/cache-mode"
 	AnnotationNameCacheKey  = "operation-cache-controller.azure.github.com/cache-key"
-	AnnotationValueTrue     = "true"
-	AnnotationValueFalse    = "false"
+	// AnnotationNameRetry on a failed requirement, operation or app deployment retries its failed provision jobs
+	AnnotationNameRetry  = "operation-cache-controller.azure.github.com/retry"
+	AnnotationValueTrue  = "true"
+	AnnotationValueFalse = "false"
+	// AnnotationNameLastAccess on a requirement or operation is the RFC3339 time a client last used it, its
+	// ttlAfterLastAccess counts from it
+	AnnotationNameLastAccess = "operation-cache-controller.azure.github.com/last-access"
+	// AnnotationNameRenewUntil on a requirement is the RFC3339 time its consumer renews it until, each renewal is a
+	// heartbeat of the consumer
+	AnnotationNameRenewUntil = "operation-cache-controller.azure.github.com/renew-until"
 
 	MaxResourceNameLength int = 63
 )
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
+)
+
+// Expiry is when a requirement, an operation or a cache expires: at an absolute time, a time after it got ready or a
+// time after it was last accessed, whichever comes first
+type Expiry struct {
+	ExpireAt           *metav1.Time
+	TTLAfterReady      *metav1.Duration
+	TTLAfterLastAccess *metav1.Duration
+}
+
+// Deadline returns the earliest of the expire time, the ready time plus the ttl after ready and the last access time
+// plus the ttl after last access, nil if none of them applies yet. An object which was never accessed counts as
+// accessed when it got ready.
+func (e Expiry) Deadline(readyTime, lastAccessTime *metav1.Time) *metav1.Time {
+	var deadline *metav1.Time
+	earliest := func(t *metav1.Time) {
+		if t != nil && (deadline == nil || t.Before(deadline)) {
+			deadline = t
+		}
+	}
+	earliest(e.ExpireAt)
+	if e.TTLAfterReady != nil && readyTime != nil {
+		earliest(&metav1.Time{Time: readyTime.Add(e.TTLAfterReady.Duration)})
+	}
+	if lastAccessTime == nil || (readyTime != nil && readyTime.After(lastAccessTime.Time)) {
+		lastAccessTime = readyTime
+	}
+	if e.TTLAfterLastAccess != nil && lastAccessTime != nil {
+		earliest(&metav1.Time{Time: lastAccessTime.Add(e.TTLAfterLastAccess.Duration)})
+	}
+	return deadline
+}
+
+// LastAccessTime returns the time of the last-access annotation of the object, nil if it is not set
+func LastAccessTime(obj metav1.Object) (*metav1.Time, error) {
+	return timeAnnotation(obj, AnnotationNameLastAccess)
+}
+
+// RenewUntil returns the time of the renew-until annotation of the object, nil if it is not set
+func RenewUntil(obj metav1.Object) (*metav1.Time, error) {
+	return timeAnnotation(obj, AnnotationNameRenewUntil)
+}
+
+func timeAnnotation(obj metav1.Object, name string) (*metav1.Time, error) {
+	value, ok := obj.GetAnnotations()[name]
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation %q: %w", name, value, err)
+	}
+	return &metav1.Time{Time: t}, nil
+}
+
+// LeaseDeadline caps the time a consumer renews an object until at the max lifetime of the lease policy, counted from
+// the creation of the object. It returns whether the renewal was capped.
+func LeaseDeadline(renewUntil *metav1.Time, creationTime metav1.Time) (*metav1.Time, bool) {
+	maxDeadline := creationTime.Add(config.Current().Lease.MaxLifetime.Duration)
+	if renewUntil.After(maxDeadline) {
+		return &metav1.Time{Time: maxDeadline}, true
+	}
+	return renewUntil, false
+}
+
+// ValidateTTL checks that a ttl, if set, is positive
+func ValidateTTL(ttl *metav1.Duration) error {
+	if ttl != nil && ttl.Duration <= 0 {
+		return fmt.Errorf("ttl %s must be positive", ttl.Duration)
+	}
+	return nil
+}
+
+// TimeEqual returns whether two optional times are equal at the second precision they are serialized with
+func TimeEqual(a, b *metav1.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Rfc3339Copy().Time.Equal(b.Rfc3339Copy().Time)
+}
+
+// IsExpiringSoon returns whether an object expiring at expireAt expires within the expiring soon window
+func IsExpiringSoon(expireAt *metav1.Time, now time.Time) bool {
+	return expireAt != nil && expireAt.Sub(now) <= config.Current().Expiry.ExpiringSoonWindow.Duration
+}
+
+// ExpiryRequeueDelay returns how long to wait for the next expiry check of an object expiring at expireAt: until it
+// is expiring soon, or until it expires once it is, capped at the max requeue delay. Zero if it never expires.
+func ExpiryRequeueDelay(expireAt *metav1.Time, now time.Time) time.Duration {
+	if expireAt == nil {
+		return 0
+	}
+	expiryConfig := config.Current().Expiry
+	delay := expireAt.Sub(now)
+	if delay > expiryConfig.ExpiringSoonWindow.Duration {
+		delay -= expiryConfig.ExpiringSoonWindow.Duration
+	}
+	// a deadline at second precision may be a little behind now, check again shortly rather than right away
+	return min(max(delay, time.Second), expiryConfig.MaxRequeueDelay.Duration)
+}
+
+// SetExpiringSoonCondition sets the ExpiringSoon condition of an object expiring at expireAt. The condition is only
+// added once the object is expiring soon, and left as it is once the object expired since it is deleted anyway. It
+// returns whether the conditions changed and whether the object started expiring soon.
+func SetExpiringSoonCondition(conditions *[]metav1.Condition, expireAt *metav1.Time, now time.Time) (changed, started bool) {
+	if expireAt != nil && !now.Before(expireAt.Time) {
+		return false, false
+	}
+	wasExpiringSoon := meta.IsStatusConditionTrue(*conditions, v1beta1.ConditionExpiringSoon)
+	if IsExpiringSoon(expireAt, now) {
+		changed = meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    v1beta1.ConditionExpiringSoon,
+			Status:  metav1.ConditionTrue,
+			Reason:  v1beta1.ConditionReasonDeadlineApproaching,
+			Message: fmt.Sprintf("Expires at %s", expireAt.UTC().Format(time.RFC3339)),
+		})
+		return changed, !wasExpiringSoon
+	}
+	if meta.FindStatusCondition(*conditions, v1beta1.ConditionExpiringSoon) == nil {
+		return false, false
+	}
+	return meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    v1beta1.ConditionExpiringSoon,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1beta1.ConditionReasonDeadlineExtended,
+		Message: "Expiry extended past the expiring soon window",
+	}), false
+}
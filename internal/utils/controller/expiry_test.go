@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/config"
+)
+
+func TestExpiryDeadline(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: base.Add(d)} }
+	ttl := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	tests := []struct {
+		name       string
+		expiry     Expiry
+		readyTime  *metav1.Time
+		lastAccess *metav1.Time
+		expected   *metav1.Time
+	}{
+		{"no expiry", Expiry{}, at(0), at(time.Hour), nil},
+		{"expire at only", Expiry{ExpireAt: at(time.Hour)}, nil, nil, at(time.Hour)},
+		{"ttl after ready before ready", Expiry{TTLAfterReady: ttl(time.Hour)}, nil, nil, nil},
+		{"ttl after ready", Expiry{TTLAfterReady: ttl(time.Hour)}, at(0), nil, at(time.Hour)},
+		{"ttl after ready before expire at", Expiry{ExpireAt: at(2 * time.Hour), TTLAfterReady: ttl(time.Hour)}, at(0), nil, at(time.Hour)},
+		{"expire at before ttl after ready", Expiry{ExpireAt: at(30 * time.Minute), TTLAfterReady: ttl(time.Hour)}, at(0), nil, at(30 * time.Minute)},
+		{"ttl after last access counts from ready", Expiry{TTLAfterLastAccess: ttl(time.Hour)}, at(0), nil, at(time.Hour)},
+		{"ttl after last access", Expiry{TTLAfterLastAccess: ttl(time.Hour)}, at(0), at(2 * time.Hour), at(3 * time.Hour)},
+		{"last access before ready", Expiry{TTLAfterLastAccess: ttl(time.Hour)}, at(time.Hour), at(0), at(2 * time.Hour)},
+		{"ttl after last access never accessed", Expiry{TTLAfterLastAccess: ttl(time.Hour)}, nil, nil, nil},
+		{"earliest of all", Expiry{ExpireAt: at(5 * time.Hour), TTLAfterReady: ttl(4 * time.Hour), TTLAfterLastAccess: ttl(time.Hour)}, at(0), at(time.Hour), at(2 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, TimeEqual(tt.expected, tt.expiry.Deadline(tt.readyTime, tt.lastAccess)))
+		})
+	}
+}
+
+func TestLastAccessTime(t *testing.T) {
+	obj := &metav1.ObjectMeta{}
+	lastAccess, err := LastAccessTime(obj)
+	require.NoError(t, err)
+	assert.Nil(t, lastAccess)
+
+	obj.Annotations = map[string]string{AnnotationNameLastAccess: "2025-01-01T00:00:00Z"}
+	lastAccess, err = LastAccessTime(obj)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), lastAccess.UTC())
+
+	obj.Annotations[AnnotationNameLastAccess] = "yesterday"
+	_, err = LastAccessTime(obj)
+	assert.ErrorContains(t, err, AnnotationNameLastAccess)
+}
+
+func TestRenewUntil(t *testing.T) {
+	obj := &metav1.ObjectMeta{}
+	renewUntil, err := RenewUntil(obj)
+	require.NoError(t, err)
+	assert.Nil(t, renewUntil)
+
+	obj.Annotations = map[string]string{AnnotationNameRenewUntil: "2025-01-01T00:00:00Z"}
+	renewUntil, err = RenewUntil(obj)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), renewUntil.UTC())
+
+	obj.Annotations[AnnotationNameRenewUntil] = "1h"
+	_, err = RenewUntil(obj)
+	assert.ErrorContains(t, err, AnnotationNameRenewUntil)
+}
+
+func TestLeaseDeadline(t *testing.T) {
+	created := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	renewUntil := &metav1.Time{Time: created.Add(time.Hour)}
+	deadline, capped := LeaseDeadline(renewUntil, created)
+	assert.Equal(t, renewUntil, deadline)
+	assert.False(t, capped)
+
+	deadline, capped = LeaseDeadline(&metav1.Time{Time: created.Add(2 * config.DefaultLeaseMaxLifetime)}, created)
+	assert.Equal(t, created.Add(config.DefaultLeaseMaxLifetime), deadline.Time)
+	assert.True(t, capped)
+}
+
+func TestValidateTTL(t *testing.T) {
+	assert.NoError(t, ValidateTTL(nil))
+	assert.NoError(t, ValidateTTL(&metav1.Duration{Duration: time.Minute}))
+	assert.Error(t, ValidateTTL(&metav1.Duration{}))
+	assert.Error(t, ValidateTTL(&metav1.Duration{Duration: -time.Minute}))
+}
+
+func TestTimeEqual(t *testing.T) {
+	now := time.Now()
+	assert.True(t, TimeEqual(nil, nil))
+	assert.False(t, TimeEqual(nil, &metav1.Time{Time: now}))
+	assert.False(t, TimeEqual(&metav1.Time{Time: now}, nil))
+	assert.True(t, TimeEqual(&metav1.Time{Time: now.Truncate(time.Second)}, &metav1.Time{Time: now.Truncate(time.Second).Add(time.Millisecond)}))
+	assert.False(t, TimeEqual(&metav1.Time{Time: now}, &metav1.Time{Time: now.Add(time.Minute)}))
+}
+
+func TestExpiryRequeueDelay(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: now.Add(d)} }
+	window := config.DefaultExpiringSoonWindow
+
+	assert.Equal(t, time.Duration(0), ExpiryRequeueDelay(nil, now))
+	// checked again when it is expiring soon
+	assert.Equal(t, 20*time.Minute, ExpiryRequeueDelay(at(window+20*time.Minute), now))
+	// checked again when it expires once it is expiring soon
+	assert.Equal(t, window/2, ExpiryRequeueDelay(at(window/2), now))
+	// capped
+	assert.Equal(t, config.DefaultExpiryMaxRequeueDelay, ExpiryRequeueDelay(at(24*time.Hour), now))
+	// a past deadline is checked again shortly
+	assert.Equal(t, time.Second, ExpiryRequeueDelay(at(-time.Minute), now))
+}
+
+func TestSetExpiringSoonCondition(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: now.Add(d)} }
+	var conditions []metav1.Condition
+
+	changed, started := SetExpiringSoonCondition(&conditions, nil, now)
+	assert.False(t, changed)
+	assert.False(t, started)
+	changed, started = SetExpiringSoonCondition(&conditions, at(time.Hour), now)
+	assert.False(t, changed)
+	assert.False(t, started)
+	assert.Empty(t, conditions)
+
+	changed, started = SetExpiringSoonCondition(&conditions, at(time.Minute), now)
+	assert.True(t, changed)
+	assert.True(t, started)
+	assert.True(t, meta.IsStatusConditionTrue(conditions, v1beta1.ConditionExpiringSoon))
+
+	changed, started = SetExpiringSoonCondition(&conditions, at(time.Minute), now)
+	assert.False(t, changed)
+	assert.False(t, started)
+
+	// an expired object keeps its condition
+	changed, started = SetExpiringSoonCondition(&conditions, at(-time.Minute), now)
+	assert.False(t, changed)
+	assert.False(t, started)
+	assert.True(t, meta.IsStatusConditionTrue(conditions, v1beta1.ConditionExpiringSoon))
+
+	changed, started = SetExpiringSoonCondition(&conditions, at(time.Hour), now)
+	assert.True(t, changed)
+	assert.False(t, started)
+	condition := meta.FindStatusCondition(conditions, v1beta1.ConditionExpiringSoon)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1beta1.ConditionReasonDeadlineExtended, condition.Reason)
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrNoWatchNamespace = errors.New("no namespace matches the namespace selector")
+
+// ParseNamespaces splits a comma separated list of namespaces, ignoring blanks
+func ParseNamespaces(namespaces string) []string {
+	var result []string
+	for _, ns := range strings.Split(namespaces, ",") {
+		if ns = strings.TrimSpace(ns); len(ns) > 0 {
+			result = append(result, ns)
+		}
+	}
+	return result
+}
+
+// WatchNamespaces resolves the namespaces the controllers are restricted to, the listed namespaces plus the namespaces
+// matching the label selector. No namespaces and no selector means all namespaces are watched. A selector which
+// matches nothing is an error rather than silently widening the controllers to the whole cluster.
+func WatchNamespaces(ctx context.Context, c client.Reader, namespaces []string, selector string) ([]string, error) {
+	result := slices.Clone(namespaces)
+	if len(selector) > 0 {
+		labelSelector, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector %q: %w", selector, err)
+		}
+		namespaceList := &corev1.NamespaceList{}
+		if err := c.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+			return nil, fmt.Errorf("failed to list namespaces matching %q: %w", selector, err)
+		}
+		for _, ns := range namespaceList.Items {
+			result = append(result, ns.Name)
+		}
+		if len(result) == 0 {
+			return nil, fmt.Errorf("%w %q", ErrNoWatchNamespace, selector)
+		}
+	}
+	slices.Sort(result)
+	return slices.Compact(result), nil
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mockpkg "github.com/Azure/operation-cache-controller/internal/utils/mocks"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	assert.Empty(t, ParseNamespaces(""))
+	assert.Equal(t, []string{"team-a", "team-b"}, ParseNamespaces(" team-a,,team-b ,"))
+}
+
+func TestWatchNamespaces(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all namespaces", func(t *testing.T) {
+		namespaces, err := WatchNamespaces(ctx, nil, nil, "")
+		require.NoError(t, err)
+		assert.Empty(t, namespaces)
+	})
+
+	t.Run("listed namespaces", func(t *testing.T) {
+		namespaces, err := WatchNamespaces(ctx, nil, []string{"team-b", "team-a", "team-b"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a", "team-b"}, namespaces)
+	})
+
+	t.Run("namespace selector", func(t *testing.T) {
+		mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+		matching := corev1.NamespaceList{Items: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "team-c"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		}}
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).SetArg(1, matching).Return(nil)
+
+		namespaces, err := WatchNamespaces(ctx, mockClient, []string{"team-a", "team-b"}, "team=platform")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a", "team-b", "team-c"}, namespaces)
+	})
+
+	t.Run("selector matches nothing", func(t *testing.T) {
+		mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := WatchNamespaces(ctx, mockClient, nil, "team=platform")
+		assert.ErrorIs(t, err, ErrNoWatchNamespace)
+	})
+
+	t.Run("list failure", func(t *testing.T) {
+		mockClient := mockpkg.NewMockClient(gomock.NewController(t))
+		mockClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("forbidden"))
+
+		_, err := WatchNamespaces(ctx, mockClient, nil, "team=platform")
+		assert.ErrorContains(t, err, "forbidden")
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		_, err := WatchNamespaces(ctx, nil, nil, "team in (")
+		assert.ErrorContains(t, err, "invalid namespace selector")
+	})
+}
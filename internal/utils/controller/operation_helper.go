@@ -3,27 +3,52 @@ package controller
 import (
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 )
 
 type OperationHelper struct{}
 
 func NewOperationHelper() OperationHelper { return OperationHelper{} }
 
-func (ou OperationHelper) IsOperationReady(operation *v1alpha1.Operation) bool {
+func (ou OperationHelper) IsOperationReady(operation *v1beta1.Operation) bool {
 	if operation == nil {
 		return false
 	}
-	return operation.Status.Phase == v1alpha1.OperationPhaseReconciled
+	return operation.Status.Phase == v1beta1.OperationPhaseReconciled
 }
 
-func (ou OperationHelper) ClearConditions(operation *v1alpha1.Operation) {
+// IsOperationReleased returns true if a requirement released the operation to the cache pool and it is not reset yet
+func (ou OperationHelper) IsOperationReleased(operation *v1beta1.Operation) bool {
+	if operation == nil {
+		return false
+	}
+	_, ok := operation.Annotations[v1beta1.OperationReleasedAnnotationKey]
+	return ok
+}
+
+// IsOperationAvailable returns true if the operation is ready to be acquired from the cache pool
+func (ou OperationHelper) IsOperationAvailable(operation *v1beta1.Operation) bool {
+	return ou.IsOperationReady(operation) && !ou.IsOperationReleased(operation)
+}
+
+// IsOperationStuck returns true if the operation is not ready the given timeout after its creation
+func (ou OperationHelper) IsOperationStuck(operation *v1beta1.Operation, timeout time.Duration, now time.Time) bool {
+	if operation == nil || ou.IsOperationReady(operation) || !operation.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return now.After(operation.CreationTimestamp.Add(timeout))
+}
+
+func (ou OperationHelper) ClearConditions(operation *v1beta1.Operation) {
 	operation.Status.Conditions = []metav1.Condition{}
 }
 
@@ -32,9 +57,10 @@ func (ou OperationHelper) NewOperationId() string {
 	return strings.Replace(uuid.New().String(), "-", "", -1)
 }
 
-// DiffAppDeployments returns the difference between two slices of AppDeployment.
-func (ou OperationHelper) DiffAppDeployments(expected, actual []v1alpha1.AppDeployment,
-	equals func(a, b v1alpha1.AppDeployment) bool) (added, removed, updated []v1alpha1.AppDeployment) {
+// DiffAppDeployments returns the difference between two slices of AppDeployment. Updated app deployments carry the
+// expected spec with the metadata of the actual ones, e.g. their resource version, owner and finalizers.
+func (ou OperationHelper) DiffAppDeployments(expected, actual []v1beta1.AppDeployment,
+	equals func(a, b v1beta1.AppDeployment) bool) (added, removed, updated []v1beta1.AppDeployment) {
 	// Find added and updated AppDeployments.
 	for _, e := range expected {
 		found := false
@@ -42,7 +68,9 @@ func (ou OperationHelper) DiffAppDeployments(expected, actual []v1alpha1.AppDepl
 			if a.Name == e.Name {
 				found = true
 				if !equals(a, e) {
-					updated = append(updated, a)
+					update := *a.DeepCopy()
+					update.Spec = *e.Spec.DeepCopy()
+					updated = append(updated, update)
 				}
 				break
 			}
@@ -69,49 +97,62 @@ func (ou OperationHelper) DiffAppDeployments(expected, actual []v1alpha1.AppDepl
 	return added, removed, updated
 }
 
+// isJobResultIdentical compares the fields of two jobs which change what they do: the init containers, the containers
+// and the volumes. Containers are compared regardless of the order of their env vars, volumes with the defaults the api
+// server fills in their sources.
 func (ou OperationHelper) isJobResultIdentical(a, b batchv1.JobSpec) bool {
-	if len(a.Template.Spec.Containers) == 0 && len(b.Template.Spec.Containers) == 0 {
-		return true
-	}
-	if len(a.Template.Spec.Containers) == 0 || len(b.Template.Spec.Containers) == 0 {
-		return false
-	}
-	if a.Template.Spec.Containers[0].Image != b.Template.Spec.Containers[0].Image {
-		return false
-	}
-	if !isStringSliceIdentical(a.Template.Spec.Containers[0].Command, b.Template.Spec.Containers[0].Command) {
+	if !ou.isContainersIdentical(a.Template.Spec.InitContainers, b.Template.Spec.InitContainers) {
 		return false
 	}
-	if !isStringSliceIdentical(a.Template.Spec.Containers[0].Args, b.Template.Spec.Containers[0].Args) {
+	if !ou.isContainersIdentical(a.Template.Spec.Containers, b.Template.Spec.Containers) {
 		return false
 	}
-	if a.Template.Spec.Containers[0].WorkingDir != b.Template.Spec.Containers[0].WorkingDir {
-		return false
-	}
-	if !ou.isEnvVarsIdentical(a.Template.Spec.Containers[0].Env, b.Template.Spec.Containers[0].Env) {
-		return false
-	}
-
-	return true
+	return equality.Semantic.DeepEqual(withVolumeDefaults(a.Template.Spec.Volumes), withVolumeDefaults(b.Template.Spec.Volumes))
 }
 
-func (ou OperationHelper) isEnvVarsIdentical(a, b []corev1.EnvVar) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	// sort environment variables to ensure consistent hashing
-	sort.Slice(a, func(i, j int) bool {
-		return a[i].Name < a[j].Name
-	})
-	sort.Slice(b, func(i, j int) bool {
-		return b[i].Name < b[j].Name
-	})
-	for i, env := range a {
-		if env.Name != b[i].Name || env.Value != b[i].Value {
-			return false
+// withVolumeDefaults returns a copy of the volumes with the default file mode of their sources set
+func withVolumeDefaults(volumes []corev1.Volume) []corev1.Volume {
+	defaultMode := func(mode *int32) *int32 {
+		if mode == nil {
+			return ptr.Of(corev1.SecretVolumeSourceDefaultMode)
 		}
+		return mode
+	}
+	result := make([]corev1.Volume, 0, len(volumes))
+	for _, volume := range volumes {
+		volume := *volume.DeepCopy()
+		switch {
+		case volume.Secret != nil:
+			volume.Secret.DefaultMode = defaultMode(volume.Secret.DefaultMode)
+		case volume.ConfigMap != nil:
+			volume.ConfigMap.DefaultMode = defaultMode(volume.ConfigMap.DefaultMode)
+		case volume.DownwardAPI != nil:
+			volume.DownwardAPI.DefaultMode = defaultMode(volume.DownwardAPI.DefaultMode)
+		case volume.Projected != nil:
+			volume.Projected.DefaultMode = defaultMode(volume.Projected.DefaultMode)
+		}
+		result = append(result, volume)
 	}
-	return true
+	return result
+}
+
+// isContainersIdentical compares the containers as a whole, with their env vars sorted by name since their order does
+// not change what the job does
+func (ou OperationHelper) isContainersIdentical(a, b []corev1.Container) bool {
+	return equality.Semantic.DeepEqual(withSortedEnv(a), withSortedEnv(b))
+}
+
+// withSortedEnv returns a copy of the containers with their env vars sorted by name
+func withSortedEnv(containers []corev1.Container) []corev1.Container {
+	result := make([]corev1.Container, 0, len(containers))
+	for _, container := range containers {
+		container := *container.DeepCopy()
+		sort.SliceStable(container.Env, func(i, j int) bool {
+			return container.Env[i].Name < container.Env[j].Name
+		})
+		result = append(result, container)
+	}
+	return result
 }
 
 func isStringSliceIdentical(a, b []string) bool {
@@ -128,7 +169,7 @@ func isStringSliceIdentical(a, b []string) bool {
 	return true
 }
 
-func (ou OperationHelper) CompareProvisionJobs(a, b v1alpha1.AppDeployment) bool {
+func (ou OperationHelper) CompareProvisionJobs(a, b v1beta1.AppDeployment) bool {
 	if sameProvisionJob := ou.isJobResultIdentical(a.Spec.Provision, b.Spec.Provision); !sameProvisionJob {
 		return false
 	}
@@ -137,9 +178,40 @@ func (ou OperationHelper) CompareProvisionJobs(a, b v1alpha1.AppDeployment) bool
 	return isStringSliceIdentical(a.Spec.Dependencies, b.Spec.Dependencies)
 }
 
-func (ou OperationHelper) CompareTeardownJobs(a, b v1alpha1.AppDeployment) bool {
+// CompareAppDeployments returns true if two app deployments have the same spec: the provision and teardown jobs, the
+// dependencies and the retry and teardown settings
+func (ou OperationHelper) CompareAppDeployments(a, b v1beta1.AppDeployment) bool {
+	return ou.CompareProvisionJobs(a, b) && ou.CompareTeardownJobs(a, b) &&
+		equality.Semantic.DeepEqual(a.Spec.MaxRetries, b.Spec.MaxRetries) &&
+		equality.Semantic.DeepEqual(a.Spec.FailedJobsHistoryLimit, b.Spec.FailedJobsHistoryLimit) &&
+		a.Spec.TeardownPolicy == b.Spec.TeardownPolicy
+}
+
+func (ou OperationHelper) CompareTeardownJobs(a, b v1beta1.AppDeployment) bool {
 	if sameTeardownJob := ou.isJobResultIdentical(a.Spec.Teardown, b.Spec.Teardown); !sameTeardownJob {
 		return false
 	}
 	return true
 }
+
+// AppDeploymentsToTeardown returns the app deployments no other remaining app deployment depends on, so tearing
+// them down in successive rounds deletes the applications in reverse dependency order. When the dependencies form
+// a cycle nothing would ever become deletable, so all remaining app deployments are returned instead.
+func (ou OperationHelper) AppDeploymentsToTeardown(apps []v1beta1.AppDeployment) []v1beta1.AppDeployment {
+	dependedOn := map[string]bool{}
+	for _, app := range apps {
+		for _, dep := range app.Spec.Dependencies {
+			dependedOn[OperationScopedAppDeployment(dep, app.Spec.OperationID)] = true
+		}
+	}
+	deletable := []v1beta1.AppDeployment{}
+	for _, app := range apps {
+		if !dependedOn[app.Name] {
+			deletable = append(deletable, app)
+		}
+	}
+	if len(deletable) == 0 {
+		return apps
+	}
+	return deletable
+}
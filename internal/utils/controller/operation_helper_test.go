@@ -2,14 +2,18 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
 )
 
 var helper = NewOperationHelper()
@@ -31,18 +35,18 @@ func TestNewOperationId(t *testing.T) {
 func TestDiffAppDeployments(t *testing.T) {
 	tests := []struct {
 		name        string
-		expected    []v1alpha1.AppDeployment
-		actual      []v1alpha1.AppDeployment
-		equals      func(a, b v1alpha1.AppDeployment) bool
+		expected    []v1beta1.AppDeployment
+		actual      []v1beta1.AppDeployment
+		equals      func(a, b v1beta1.AppDeployment) bool
 		wantAdded   int
 		wantRemoved int
 		wantUpdated int
 	}{
 		{
 			name:      "basic example",
-			expected:  []v1alpha1.AppDeployment{{ObjectMeta: metav1.ObjectMeta{Name: "app1"}}, {ObjectMeta: metav1.ObjectMeta{Name: "app2"}}},
-			actual:    []v1alpha1.AppDeployment{{ObjectMeta: metav1.ObjectMeta{Name: "app2"}}, {ObjectMeta: metav1.ObjectMeta{Name: "app3"}}},
-			equals:    func(a, b v1alpha1.AppDeployment) bool { return a.Name == b.Name },
+			expected:  []v1beta1.AppDeployment{{ObjectMeta: metav1.ObjectMeta{Name: "app1"}}, {ObjectMeta: metav1.ObjectMeta{Name: "app2"}}},
+			actual:    []v1beta1.AppDeployment{{ObjectMeta: metav1.ObjectMeta{Name: "app2"}}, {ObjectMeta: metav1.ObjectMeta{Name: "app3"}}},
+			equals:    func(a, b v1beta1.AppDeployment) bool { return a.Name == b.Name },
 			wantAdded: 1, wantRemoved: 1, wantUpdated: 0,
 		},
 	}
@@ -56,17 +60,71 @@ func TestDiffAppDeployments(t *testing.T) {
 	}
 }
 
+func TestDiffAppDeploymentsUpdated(t *testing.T) {
+	actual := v1beta1.AppDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", ResourceVersion: "42", Finalizers: []string{"finalizer"}},
+		Spec:       v1beta1.AppDeploymentSpec{OperationID: "op", MaxRetries: ptr.Of(int32(3))},
+	}
+	expected := v1beta1.AppDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec:       v1beta1.AppDeploymentSpec{OperationID: "op", MaxRetries: ptr.Of(int32(5))},
+	}
+
+	_, _, updated := helper.DiffAppDeployments([]v1beta1.AppDeployment{expected}, []v1beta1.AppDeployment{actual}, helper.CompareAppDeployments)
+	require.Len(t, updated, 1)
+	// the update carries the expected spec with the metadata of the actual app deployment
+	assert.Equal(t, expected.Spec, updated[0].Spec)
+	assert.Equal(t, "42", updated[0].ResourceVersion)
+	assert.Equal(t, []string{"finalizer"}, updated[0].Finalizers)
+}
+
+func TestCompareAppDeployments(t *testing.T) {
+	base := v1beta1.AppDeployment{Spec: v1beta1.AppDeploymentSpec{
+		Provision:    newTestJobSpecWithImage("provision"),
+		Teardown:     newTestJobSpecWithImage("teardown"),
+		Dependencies: []string{"dep"},
+	}}
+	tests := []struct {
+		name   string
+		change func(app *v1beta1.AppDeployment)
+		want   bool
+	}{
+		{name: "identical", change: func(app *v1beta1.AppDeployment) {}, want: true},
+		{name: "different provision job", change: func(app *v1beta1.AppDeployment) {
+			app.Spec.Provision.Template.Spec.Containers[0].Image = "other"
+		}},
+		{name: "different teardown job", change: func(app *v1beta1.AppDeployment) {
+			app.Spec.Teardown.Template.Spec.Containers[0].Image = "other"
+		}},
+		{name: "different dependencies", change: func(app *v1beta1.AppDeployment) { app.Spec.Dependencies = nil }},
+		{name: "different max retries", change: func(app *v1beta1.AppDeployment) { app.Spec.MaxRetries = ptr.Of(int32(5)) }},
+		{name: "different failed jobs history limit", change: func(app *v1beta1.AppDeployment) {
+			app.Spec.FailedJobsHistoryLimit = ptr.Of(int32(1))
+		}},
+		{name: "different teardown policy", change: func(app *v1beta1.AppDeployment) {
+			app.Spec.TeardownPolicy = v1beta1.TeardownPolicyOrphan
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := *base.DeepCopy()
+			tt.change(&changed)
+			assert.Equal(t, tt.want, helper.CompareAppDeployments(*base.DeepCopy(), changed))
+		})
+	}
+}
+
 func TestCompareProvisionJobs(t *testing.T) {
 	tests := []struct {
 		name string
-		a    v1alpha1.AppDeployment
-		b    v1alpha1.AppDeployment
+		a    v1beta1.AppDeployment
+		b    v1beta1.AppDeployment
 		want bool
 	}{
 		{
 			name: "different images",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -77,8 +135,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -93,8 +151,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with same dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -105,8 +163,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -121,8 +179,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with different dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -133,8 +191,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -149,8 +207,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with different number of dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -161,8 +219,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2", "dep3"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -177,8 +235,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with empty dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -189,8 +247,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 					Dependencies: []string{},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -205,8 +263,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with nil and empty dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -217,8 +275,8 @@ func TestCompareProvisionJobs(t *testing.T) {
 					Dependencies: nil,
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -243,14 +301,14 @@ func TestCompareProvisionJobs(t *testing.T) {
 func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 	tests := []struct {
 		name string
-		a    v1alpha1.AppDeployment
-		b    v1alpha1.AppDeployment
+		a    v1beta1.AppDeployment
+		b    v1beta1.AppDeployment
 		want bool
 	}{
 		{
 			name: "different images",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -261,8 +319,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -277,8 +335,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with same dependencies in different order",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -289,8 +347,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -305,8 +363,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with different dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -317,8 +375,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -333,8 +391,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with different number of dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -345,8 +403,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 					Dependencies: []string{"dep1", "dep2", "dep3"},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -361,8 +419,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with empty dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -373,8 +431,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 					Dependencies: []string{},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -389,8 +447,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 		},
 		{
 			name: "identical provision jobs with nil and empty dependencies",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -401,8 +459,8 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 					Dependencies: nil,
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Provision: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -427,14 +485,14 @@ func TestCompareProvisionJobsWithDependencies(t *testing.T) {
 func TestCompareTeardownJobs(t *testing.T) {
 	tests := []struct {
 		name string
-		a    v1alpha1.AppDeployment
-		b    v1alpha1.AppDeployment
+		a    v1beta1.AppDeployment
+		b    v1beta1.AppDeployment
 		want bool
 	}{
 		{
 			name: "different teardown images",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -444,8 +502,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -459,8 +517,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "identical teardown jobs",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -470,8 +528,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -485,8 +543,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "both empty teardown containers",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -496,8 +554,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -511,8 +569,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "one empty teardown container",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -522,8 +580,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -537,8 +595,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "different commands in teardown",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -551,8 +609,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -569,8 +627,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "different args in teardown",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -583,8 +641,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -601,8 +659,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "different working dir in teardown",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -615,8 +673,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -633,8 +691,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "different env vars in teardown",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -649,8 +707,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -669,8 +727,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 		},
 		{
 			name: "complex identical teardown jobs",
-			a: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			a: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -689,8 +747,8 @@ func TestCompareTeardownJobs(t *testing.T) {
 					},
 				},
 			},
-			b: v1alpha1.AppDeployment{
-				Spec: v1alpha1.AppDeploymentSpec{
+			b: v1beta1.AppDeployment{
+				Spec: v1beta1.AppDeploymentSpec{
 					Teardown: batchv1.JobSpec{
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
@@ -723,7 +781,7 @@ func TestCompareTeardownJobs(t *testing.T) {
 func TestIsOperationReady(t *testing.T) {
 	tests := []struct {
 		name      string
-		operation *v1alpha1.Operation
+		operation *v1beta1.Operation
 		want      bool
 	}{
 		{
@@ -733,18 +791,18 @@ func TestIsOperationReady(t *testing.T) {
 		},
 		{
 			name: "operation not ready",
-			operation: &v1alpha1.Operation{
-				Status: v1alpha1.OperationStatus{
-					Phase: v1alpha1.OperationPhaseReconciling,
+			operation: &v1beta1.Operation{
+				Status: v1beta1.OperationStatus{
+					Phase: v1beta1.OperationPhaseReconciling,
 				},
 			},
 			want: false,
 		},
 		{
 			name: "operation ready",
-			operation: &v1alpha1.Operation{
-				Status: v1alpha1.OperationStatus{
-					Phase: v1alpha1.OperationPhaseReconciled,
+			operation: &v1beta1.Operation{
+				Status: v1beta1.OperationStatus{
+					Phase: v1beta1.OperationPhaseReconciled,
 				},
 			},
 			want: true,
@@ -758,10 +816,53 @@ func TestIsOperationReady(t *testing.T) {
 	}
 }
 
+func TestIsOperationAvailable(t *testing.T) {
+	ready := &v1beta1.Operation{Status: v1beta1.OperationStatus{Phase: v1beta1.OperationPhaseReconciled}}
+	released := ready.DeepCopy()
+	released.Annotations = map[string]string{v1beta1.OperationReleasedAnnotationKey: time.Now().Format(time.RFC3339)}
+
+	assert.False(t, helper.IsOperationAvailable(nil))
+	assert.True(t, helper.IsOperationAvailable(ready))
+	assert.False(t, helper.IsOperationReleased(ready))
+	// a released operation is ready, but waits for its reset
+	assert.False(t, helper.IsOperationAvailable(released))
+	assert.True(t, helper.IsOperationReleased(released))
+}
+
+func TestIsOperationStuck(t *testing.T) {
+	now := time.Now()
+	newOperation := func(age time.Duration, phase string) *v1beta1.Operation {
+		return &v1beta1.Operation{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Status:     v1beta1.OperationStatus{Phase: phase},
+		}
+	}
+	deletingOperation := newOperation(time.Hour, v1beta1.OperationPhaseDeleting)
+	deletingOperation.DeletionTimestamp = ptr.Of(metav1.NewTime(now))
+
+	tests := []struct {
+		name      string
+		operation *v1beta1.Operation
+		want      bool
+	}{
+		{name: "nil operation", operation: nil, want: false},
+		{name: "not ready within timeout", operation: newOperation(time.Minute, v1beta1.OperationPhaseReconciling), want: false},
+		{name: "not ready after timeout", operation: newOperation(time.Hour, v1beta1.OperationPhaseReconciling), want: true},
+		{name: "never reconciled after timeout", operation: newOperation(time.Hour, v1beta1.OperationPhaseEmpty), want: true},
+		{name: "ready after timeout", operation: newOperation(time.Hour, v1beta1.OperationPhaseReconciled), want: false},
+		{name: "deleting after timeout", operation: deletingOperation, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, helper.IsOperationStuck(tt.operation, 30*time.Minute, now))
+		})
+	}
+}
+
 func TestClearOperationConditions(t *testing.T) {
 	t.Run("clear conditions", func(t *testing.T) {
-		operation := &v1alpha1.Operation{
-			Status: v1alpha1.OperationStatus{
+		operation := &v1beta1.Operation{
+			Status: v1beta1.OperationStatus{
 				Conditions: []metav1.Condition{
 					{
 						Type:    "Test",
@@ -840,70 +941,92 @@ func TestIsStringSliceIdentical(t *testing.T) {
 	}
 }
 
-func TestIsEnvVarsIdentical(t *testing.T) {
+func TestIsContainersIdentical(t *testing.T) {
+	container := func(env ...corev1.EnvVar) corev1.Container {
+		return corev1.Container{Name: "deploy", Image: "test-image", Env: env}
+	}
 	tests := []struct {
 		name string
-		a    []corev1.EnvVar
-		b    []corev1.EnvVar
+		a    []corev1.Container
+		b    []corev1.Container
 		want bool
 	}{
 		{
-			name: "different length",
-			a: []corev1.EnvVar{
-				{Name: "VAR1", Value: "value1"},
-				{Name: "VAR2", Value: "value2"},
-			},
-			b: []corev1.EnvVar{
-				{Name: "VAR1", Value: "value1"},
-			},
+			name: "different number of env vars",
+			a:    []corev1.Container{container(corev1.EnvVar{Name: "VAR1", Value: "value1"}, corev1.EnvVar{Name: "VAR2", Value: "value2"})},
+			b:    []corev1.Container{container(corev1.EnvVar{Name: "VAR1", Value: "value1"})},
 			want: false,
 		},
 		{
-			name: "same vars different order",
-			a: []corev1.EnvVar{
-				{Name: "VAR1", Value: "value1"},
-				{Name: "VAR2", Value: "value2"},
-			},
-			b: []corev1.EnvVar{
-				{Name: "VAR2", Value: "value2"},
-				{Name: "VAR1", Value: "value1"},
-			},
+			name: "same env vars different order",
+			a:    []corev1.Container{container(corev1.EnvVar{Name: "VAR1", Value: "value1"}, corev1.EnvVar{Name: "VAR2", Value: "value2"})},
+			b:    []corev1.Container{container(corev1.EnvVar{Name: "VAR2", Value: "value2"}, corev1.EnvVar{Name: "VAR1", Value: "value1"})},
 			want: true,
 		},
 		{
-			name: "different values",
-			a: []corev1.EnvVar{
-				{Name: "VAR1", Value: "value1"},
-				{Name: "VAR2", Value: "value2"},
-			},
-			b: []corev1.EnvVar{
-				{Name: "VAR1", Value: "value1"},
-				{Name: "VAR2", Value: "different"},
-			},
+			name: "different env values",
+			a:    []corev1.Container{container(corev1.EnvVar{Name: "VAR1", Value: "value1"})},
+			b:    []corev1.Container{container(corev1.EnvVar{Name: "VAR1", Value: "different"})},
 			want: false,
 		},
 		{
-			name: "different names",
-			a: []corev1.EnvVar{
-				{Name: "VAR1", Value: "value1"},
-				{Name: "VAR2", Value: "value2"},
-			},
-			b: []corev1.EnvVar{
-				{Name: "VAR1", Value: "value1"},
-				{Name: "DIFFERENT", Value: "value2"},
-			},
+			name: "different env names",
+			a:    []corev1.Container{container(corev1.EnvVar{Name: "VAR1", Value: "value1"})},
+			b:    []corev1.Container{container(corev1.EnvVar{Name: "DIFFERENT", Value: "value1"})},
 			want: false,
 		},
 		{
-			name: "empty slices",
-			a:    []corev1.EnvVar{},
-			b:    []corev1.EnvVar{},
+			name: "different env value sources",
+			a: []corev1.Container{container(corev1.EnvVar{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "a"}, Key: "token"},
+			}})},
+			b: []corev1.Container{container(corev1.EnvVar{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "b"}, Key: "token"},
+			}})},
+			want: false,
+		},
+		{
+			name: "different env from",
+			a:    []corev1.Container{{Name: "deploy", EnvFrom: []corev1.EnvFromSource{{Prefix: "A_"}}}},
+			b:    []corev1.Container{{Name: "deploy", EnvFrom: []corev1.EnvFromSource{{Prefix: "B_"}}}},
+			want: false,
+		},
+		{
+			name: "different resources",
+			a: []corev1.Container{{Name: "deploy", Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}}},
+			b: []corev1.Container{{Name: "deploy", Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			}}},
+			want: false,
+		},
+		{
+			name: "semantically equal resources",
+			a: []corev1.Container{{Name: "deploy", Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}}},
+			b: []corev1.Container{{Name: "deploy", Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1024Mi")},
+			}}},
+			want: true,
+		},
+		{
+			name: "different security context",
+			a:    []corev1.Container{{Name: "deploy"}},
+			b:    []corev1.Container{{Name: "deploy", SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.Of(true)}}},
+			want: false,
+		},
+		{
+			name: "empty containers",
+			a:    []corev1.Container{},
+			b:    nil,
 			want: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := helper.isEnvVarsIdentical(tt.a, tt.b)
+			got := helper.isContainersIdentical(tt.a, tt.b)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -1112,11 +1235,218 @@ func TestIsJobResultIdentical(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "different sidecars",
+			a: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}, {Name: "credentials", Image: "sidecar:v1"}},
+					},
+				},
+			},
+			b: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}, {Name: "credentials", Image: "sidecar:v2"}},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "different init containers",
+			a: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{{Name: "fetch-tools", Image: "tools:v1"}},
+						Containers:     []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			b: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "different volume mounts",
+			a: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes:    []corev1.Volume{{Name: "credentials"}},
+						Containers: []corev1.Container{{Name: "main", Image: "test-image", VolumeMounts: []corev1.VolumeMount{{Name: "credentials", MountPath: "/a"}}}},
+					},
+				},
+			},
+			b: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes:    []corev1.Volume{{Name: "credentials"}},
+						Containers: []corev1.Container{{Name: "main", Image: "test-image", VolumeMounts: []corev1.VolumeMount{{Name: "credentials", MountPath: "/b"}}}},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "different volumes",
+			a: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes:    []corev1.Volume{{Name: "credentials"}},
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			b: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "different volume sources",
+			a: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{Name: "credentials", VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "credentials-v1"},
+						}}},
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			b: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{Name: "credentials", VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "credentials-v2"},
+						}}},
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "volume sources with defaults filled in",
+			a: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{Name: "credentials", VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "credentials"},
+						}}},
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			b: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{Name: "credentials", VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "credentials", DefaultMode: ptr.Of(corev1.SecretVolumeSourceDefaultMode)},
+						}}},
+						Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "reordered command",
+			a: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Image: "test-image", Command: []string{"deploy", "--force"}}},
+					},
+				},
+			},
+			b: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Image: "test-image", Command: []string{"--force", "deploy"}}},
+					},
+				},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.a.DeepCopy(), tt.b.DeepCopy()
 			got := helper.isJobResultIdentical(tt.a, tt.b)
 			assert.Equal(t, tt.want, got)
+			// the compared specs are left untouched
+			assert.Equal(t, a, &tt.a)
+			assert.Equal(t, b, &tt.b)
 		})
 	}
 }
+
+func TestAppDeploymentsToTeardown(t *testing.T) {
+	newApp := func(name string, deps ...string) v1beta1.AppDeployment {
+		return v1beta1.AppDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: OperationScopedAppDeployment(name, "op")},
+			Spec:       v1beta1.AppDeploymentSpec{OperationID: "op", Dependencies: deps},
+		}
+	}
+	names := func(apps []v1beta1.AppDeployment) []string {
+		result := []string{}
+		for _, app := range apps {
+			result = append(result, app.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		apps     []v1beta1.AppDeployment
+		expected []string
+	}{
+		{
+			name:     "no app deployments",
+			apps:     nil,
+			expected: []string{},
+		},
+		{
+			name:     "independent apps are torn down together",
+			apps:     []v1beta1.AppDeployment{newApp("a"), newApp("b")},
+			expected: []string{"op-a", "op-b"},
+		},
+		{
+			name:     "dependents are torn down before their dependencies",
+			apps:     []v1beta1.AppDeployment{newApp("db"), newApp("api", "db"), newApp("web", "api")},
+			expected: []string{"op-web"},
+		},
+		{
+			name:     "dependency is torn down once its dependents are gone",
+			apps:     []v1beta1.AppDeployment{newApp("db"), newApp("cache")},
+			expected: []string{"op-db", "op-cache"},
+		},
+		{
+			name:     "cycle tears down everything",
+			apps:     []v1beta1.AppDeployment{newApp("a", "b"), newApp("b", "a")},
+			expected: []string{"op-a", "op-b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, names(helper.AppDeploymentsToTeardown(tt.apps)))
+		})
+	}
+}
+
+func newTestJobSpecWithImage(image string) batchv1.JobSpec {
+	return batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "job", Image: image}}},
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+var (
+	ErrDuplicateApplication = errors.New("duplicate application name")
+	ErrDanglingDependency   = errors.New("dependency on unknown application")
+	ErrDependencyCycle      = errors.New("dependency cycle")
+	ErrInvalidIgnoredField  = errors.New("invalid cache key ignored field")
+)
+
+// ValidateApplicationDependencies checks that the dependencies of the applications form a DAG
+// * application names are unique
+// * every dependency names an application of the same operation
+// * there is no cycle, including an application depending on itself
+func ValidateApplicationDependencies(apps []v1beta1.ApplicationSpec) error {
+	dependencies := map[string][]string{}
+	for _, app := range apps {
+		if _, ok := dependencies[app.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateApplication, app.Name)
+		}
+		dependencies[app.Name] = app.Dependencies
+	}
+	var errs error
+	for _, app := range apps {
+		for _, dep := range app.Dependencies {
+			if _, ok := dependencies[dep]; !ok {
+				errs = errors.Join(errs, fmt.Errorf("%w: %s depends on %s", ErrDanglingDependency, app.Name, dep))
+			}
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	path := []string{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// the path holds the cycle from the first occurrence of name
+			for i, p := range path {
+				if p == name {
+					return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range dependencies[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, app := range apps {
+		if err := visit(app.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateOperationSpec validates the applications of an operation spec, which is also the template of
+// requirements and caches: the job specs of every application and the dependencies between them
+func ValidateOperationSpec(spec v1beta1.OperationSpec) error {
+	var errs error
+	for _, app := range spec.Applications {
+		ap := &v1beta1.AppDeployment{
+			Spec: v1beta1.AppDeploymentSpec{
+				Provision:    app.Provision,
+				Teardown:     app.Teardown,
+				Dependencies: app.Dependencies,
+			},
+		}
+		if err := errors.Join(Validate(ap), validateCacheKeyIgnoredFields(app)); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("application %s: %w", app.Name, err))
+		}
+	}
+	return errors.Join(errs, ValidateApplicationDependencies(spec.Applications))
+}
+
+// validateCacheKeyIgnoredFields checks that the ignored fields of an application are paths into its jobs or
+// dependencies, the name always identifies the application
+func validateCacheKeyIgnoredFields(app v1beta1.ApplicationSpec) error {
+	var errs error
+	for _, path := range app.CacheKeyIgnoredFields {
+		segments := strings.Split(path, ".")
+		if slices.Contains(segments, "") {
+			errs = errors.Join(errs, fmt.Errorf("%w: %q has an empty segment", ErrInvalidIgnoredField, path))
+			continue
+		}
+		if !slices.Contains([]string{"provision", "teardown", "dependencies"}, segments[0]) {
+			errs = errors.Join(errs, fmt.Errorf("%w: %q must start with provision, teardown or dependencies", ErrInvalidIgnoredField, path))
+		}
+	}
+	return errs
+}
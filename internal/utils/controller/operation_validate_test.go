@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+func TestValidateApplicationDependencies(t *testing.T) {
+	app := func(name string, deps ...string) v1beta1.ApplicationSpec {
+		return v1beta1.ApplicationSpec{Name: name, Dependencies: deps}
+	}
+	tests := []struct {
+		name    string
+		apps    []v1beta1.ApplicationSpec
+		wantErr error
+		errMsg  string
+	}{
+		{
+			name: "no dependencies",
+			apps: []v1beta1.ApplicationSpec{app("a"), app("b")},
+		},
+		{
+			name: "diamond",
+			apps: []v1beta1.ApplicationSpec{app("a", "b", "c"), app("b", "d"), app("c", "d"), app("d")},
+		},
+		{
+			name:    "duplicate application",
+			apps:    []v1beta1.ApplicationSpec{app("a"), app("a")},
+			wantErr: ErrDuplicateApplication,
+		},
+		{
+			name:    "dangling dependency",
+			apps:    []v1beta1.ApplicationSpec{app("a", "missing")},
+			wantErr: ErrDanglingDependency,
+			errMsg:  "a depends on missing",
+		},
+		{
+			name:    "self dependency",
+			apps:    []v1beta1.ApplicationSpec{app("a", "a")},
+			wantErr: ErrDependencyCycle,
+			errMsg:  "a -> a",
+		},
+		{
+			name:    "cycle",
+			apps:    []v1beta1.ApplicationSpec{app("a", "b"), app("b", "c"), app("c", "a"), app("d", "a")},
+			wantErr: ErrDependencyCycle,
+			errMsg:  "a -> b -> c -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateApplicationDependencies(tt.apps)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestValidateOperationSpec(t *testing.T) {
+	validJob := batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "test", Image: "test-image"}},
+			},
+		},
+	}
+	invalidJob := batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "a", Image: "a"}, {Name: "b"}},
+			},
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		spec := v1beta1.OperationSpec{Applications: []v1beta1.ApplicationSpec{
+			{Name: "a", Provision: validJob, Teardown: validJob},
+			{Name: "b", Provision: validJob, Dependencies: []string{"a"}},
+		}}
+		assert.NoError(t, ValidateOperationSpec(spec))
+	})
+
+	t.Run("invalid job and dangling dependency", func(t *testing.T) {
+		spec := v1beta1.OperationSpec{Applications: []v1beta1.ApplicationSpec{
+			{Name: "a", Provision: invalidJob},
+			{Name: "b", Provision: validJob, Dependencies: []string{"c"}},
+		}}
+		err := ValidateOperationSpec(spec)
+		assert.ErrorContains(t, err, "application a: provision")
+		assert.ErrorIs(t, err, ErrDanglingDependency)
+	})
+}
+
+func TestValidateCacheKeyIgnoredFields(t *testing.T) {
+	app := v1beta1.ApplicationSpec{Name: "a", CacheKeyIgnoredFields: []string{
+		"provision.template.spec.containers.resources",
+		"teardown",
+		"dependencies",
+	}}
+	assert.NoError(t, validateCacheKeyIgnoredFields(app))
+
+	app.CacheKeyIgnoredFields = []string{"name", "provision..spec"}
+	err := validateCacheKeyIgnoredFields(app)
+	assert.ErrorIs(t, err, ErrInvalidIgnoredField)
+	assert.ErrorContains(t, err, `"name" must start with`)
+	assert.ErrorContains(t, err, `"provision..spec" has an empty segment`)
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// OrphanedResourcesConfigMapName is the config map in the namespace of the app deployments which records the
+// resources left behind by failed teardown jobs, one entry per app deployment
+const OrphanedResourcesConfigMapName = "operation-cache-controller-orphaned-resources"
+
+// OrphanedResource describes the resources of an app deployment whose teardown job failed, with everything a cleanup
+// process needs to tear them down later
+type OrphanedResource struct {
+	AppDeployment string            `json:"appDeployment"`
+	OperationID   string            `json:"opId"`
+	Job           string            `json:"job"`
+	Reason        string            `json:"reason"`
+	Message       string            `json:"message,omitempty"`
+	OrphanedAt    metav1.Time       `json:"orphanedAt"`
+	Outputs       map[string]string `json:"outputs,omitempty"`
+	Teardown      batchv1.JobSpec   `json:"teardown"`
+}
+
+// NewOrphanedResource records the resources of the app deployment left behind by its failed teardown job
+func NewOrphanedResource(appdeployment *v1beta1.AppDeployment, job string, failure JobFailure, now metav1.Time) OrphanedResource {
+	return OrphanedResource{
+		AppDeployment: appdeployment.Name,
+		OperationID:   appdeployment.Spec.OperationID,
+		Job:           job,
+		Reason:        failure.Reason,
+		Message:       failure.Message,
+		OrphanedAt:    now,
+		Outputs:       appdeployment.Status.Outputs,
+		Teardown:      appdeployment.Spec.Teardown,
+	}
+}
+
+// OrphanedResourceKey returns the key of the app deployment in the orphaned resources config map, the uid keeps the
+// records of app deployments reusing a name apart
+func OrphanedResourceKey(appdeployment *v1beta1.AppDeployment) string {
+	if appdeployment.UID == "" {
+		return appdeployment.Name
+	}
+	return fmt.Sprintf("%s.%s", appdeployment.Name, appdeployment.UID)
+}
+
+// AddOrphanedResource adds the record to the orphaned resources config map
+func AddOrphanedResource(configMap *corev1.ConfigMap, key string, resource OrphanedResource) error {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return fmt.Errorf("failed to marshal orphaned resource %s: %w", key, err)
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = string(data)
+	return nil
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
+)
+
+func TestOrphanedResourceKey(t *testing.T) {
+	appDeployment := &v1beta1.AppDeployment{ObjectMeta: metav1.ObjectMeta{Name: "op-app"}}
+	assert.Equal(t, "op-app", OrphanedResourceKey(appDeployment))
+
+	appDeployment.UID = "1234"
+	assert.Equal(t, "op-app.1234", OrphanedResourceKey(appDeployment))
+}
+
+func TestAddOrphanedResource(t *testing.T) {
+	appDeployment := &v1beta1.AppDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "op-app", UID: "1234"},
+		Spec: v1beta1.AppDeploymentSpec{
+			OperationID: "op",
+			Teardown:    batchv1.JobSpec{BackoffLimit: ptr.Of(int32(1))},
+		},
+		Status: v1beta1.AppDeploymentStatus{Outputs: map[string]string{"endpoint": "db.example.com"}},
+	}
+	now := metav1.Now()
+	resource := NewOrphanedResource(appDeployment, "teardown-op-app", JobFailure{Reason: "BackoffLimitExceeded", Message: "timeout"}, now)
+
+	configMap := &corev1.ConfigMap{Data: map[string]string{"other": "{}"}}
+	require.NoError(t, AddOrphanedResource(configMap, OrphanedResourceKey(appDeployment), resource))
+	assert.Equal(t, "{}", configMap.Data["other"])
+
+	var recorded OrphanedResource
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data["op-app.1234"]), &recorded))
+	assert.Equal(t, "op-app", recorded.AppDeployment)
+	assert.Equal(t, "op", recorded.OperationID)
+	assert.Equal(t, "teardown-op-app", recorded.Job)
+	assert.Equal(t, "BackoffLimitExceeded", recorded.Reason)
+	assert.Equal(t, "timeout", recorded.Message)
+	assert.Equal(t, map[string]string{"endpoint": "db.example.com"}, recorded.Outputs)
+	assert.Equal(t, ptr.Of(int32(1)), recorded.Teardown.BackoffLimit)
+
+	// a config map without data gets some
+	empty := &corev1.ConfigMap{}
+	require.NoError(t, AddOrphanedResource(empty, "op-app", resource))
+	assert.Contains(t, empty.Data, "op-app")
+}
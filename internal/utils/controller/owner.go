@@ -0,0 +1,18 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+// IsOwnerOfKind returns whether the owner reference points to the given kind of the api group of the controller. The
+// version is ignored, owner references written before the api was promoted keep their old version.
+func IsOwnerOfKind(owner *metav1.OwnerReference, kind string) bool {
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == v1beta1.GroupVersion.Group && owner.Kind == kind
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+func TestIsOwnerOfKind(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		kind       string
+		want       bool
+	}{
+		{"current version", v1beta1.GroupVersion.String(), "Operation", true},
+		{"previous version", "controller.azure.github.com/v1alpha1", "Operation", true},
+		{"other kind", v1beta1.GroupVersion.String(), "Cache", false},
+		{"other group", "apps/v1", "Operation", false},
+		{"invalid api version", "a/b/c", "Operation", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := &metav1.OwnerReference{APIVersion: tt.apiVersion, Kind: tt.kind}
+			assert.Equal(t, tt.want, IsOwnerOfKind(owner, "Operation"))
+		})
+	}
+}
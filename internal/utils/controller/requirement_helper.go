@@ -3,19 +3,19 @@ package controller
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 )
 
 type RequirementHelper struct{}
 
 func NewRequirementHelper() RequirementHelper { return RequirementHelper{} }
-func (rh RequirementHelper) ClearConditions(r *v1alpha1.Requirement) {
+func (rh RequirementHelper) ClearConditions(r *v1beta1.Requirement) {
 	if r.Status.Conditions == nil {
 		r.Status.Conditions = []metav1.Condition{}
 	}
 }
 
-func (rh RequirementHelper) getCondition(r *v1alpha1.Requirement, conditionType string) (int, *metav1.Condition) {
+func (rh RequirementHelper) getCondition(r *v1beta1.Requirement, conditionType string) (int, *metav1.Condition) {
 	for i, condition := range r.Status.Conditions {
 		if condition.Type == conditionType {
 			return i, &condition
@@ -24,12 +24,12 @@ func (rh RequirementHelper) getCondition(r *v1alpha1.Requirement, conditionType
 	return -1, nil
 }
 
-func (rh RequirementHelper) IsCacheMissed(r *v1alpha1.Requirement) bool {
-	_, condition := rh.getCondition(r, v1alpha1.RequirementConditionCachedOperationAcquired)
+func (rh RequirementHelper) IsCacheMissed(r *v1beta1.Requirement) bool {
+	_, condition := rh.getCondition(r, v1beta1.RequirementConditionCachedOperationAcquired)
 	return condition == nil || condition.Status == metav1.ConditionFalse
 }
 
-func (rh RequirementHelper) UpdateCondition(r *v1alpha1.Requirement, conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) bool {
+func (rh RequirementHelper) UpdateCondition(r *v1beta1.Requirement, conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) bool {
 	condition := &metav1.Condition{
 		Type:               conditionType,
 		Status:             conditionStatus,
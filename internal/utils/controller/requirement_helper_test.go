@@ -7,7 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 )
 
 var reqHelper = NewRequirementHelper()
@@ -15,8 +15,8 @@ var reqHelper = NewRequirementHelper()
 // --- Test ClearConditions ---
 func TestClearConditions(t *testing.T) {
 	// Requirement with nil Conditions.
-	req := &v1alpha1.Requirement{
-		Status: v1alpha1.RequirementStatus{
+	req := &v1beta1.Requirement{
+		Status: v1beta1.RequirementStatus{
 			// Conditions nil for test.
 		},
 	}
@@ -41,7 +41,7 @@ func TestIsCacheMissed(t *testing.T) {
 			name: "condition exists - false status",
 			conditions: []metav1.Condition{
 				{
-					Type:   v1alpha1.RequirementConditionCachedOperationAcquired,
+					Type:   v1beta1.RequirementConditionCachedOperationAcquired,
 					Status: metav1.ConditionFalse,
 				},
 			},
@@ -51,7 +51,7 @@ func TestIsCacheMissed(t *testing.T) {
 			name: "condition exists - true status",
 			conditions: []metav1.Condition{
 				{
-					Type:   v1alpha1.RequirementConditionCachedOperationAcquired,
+					Type:   v1beta1.RequirementConditionCachedOperationAcquired,
 					Status: metav1.ConditionTrue,
 				},
 			},
@@ -60,8 +60,8 @@ func TestIsCacheMissed(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := &v1alpha1.Requirement{
-				Status: v1alpha1.RequirementStatus{
+			req := &v1beta1.Requirement{
+				Status: v1beta1.RequirementStatus{
 					Conditions: tt.conditions,
 				},
 			}
@@ -74,9 +74,9 @@ func TestIsCacheMissed(t *testing.T) {
 // --- Test UpdateCondition ---
 func TestUpdateCondition(t *testing.T) {
 	// Helper to create a Requirement with given conditions.
-	newReq := func(conds []metav1.Condition) *v1alpha1.Requirement {
-		return &v1alpha1.Requirement{
-			Status: v1alpha1.RequirementStatus{
+	newReq := func(conds []metav1.Condition) *v1beta1.Requirement {
+		return &v1beta1.Requirement{
+			Status: v1beta1.RequirementStatus{
 				Conditions: conds,
 			},
 		}
@@ -3,9 +3,17 @@ package reconciler
 import (
 	"context"
 	"time"
+
+	"github.com/Azure/operation-cache-controller/internal/config"
 )
 
-var DefaultRequeueDelay = 10 * time.Second
+// DefaultRequeueDelay is the requeue delay unless the controller config sets another one
+var DefaultRequeueDelay = config.DefaultRequeueDelay
+
+// RequeueDelay returns the requeue delay of the current controller config
+func RequeueDelay() time.Duration {
+	return config.Current().RequeueDelay.Duration
+}
 
 type ReconcileOperation func(ctx context.Context) (OperationResult, error)
 
@@ -13,6 +21,9 @@ type OperationResult struct {
 	RequeueDelay   time.Duration
 	RequeueRequest bool
 	CancelRequest  bool
+	// RequeueWithin bounds the delay of the next reconcile whether the following operations continue, requeue or
+	// cancel the request, e.g. so an object is reconciled again when it expires. Zero means unbounded.
+	RequeueWithin time.Duration
 }
 
 func (r OperationResult) RequeueOrCancel() bool {
@@ -41,7 +52,7 @@ func StopProcessing() (OperationResult, error) {
 
 func Requeue() (result OperationResult, err error) {
 	result = OperationResult{
-		RequeueDelay:   DefaultRequeueDelay,
+		RequeueDelay:   RequeueDelay(),
 		RequeueRequest: true,
 		CancelRequest:  false,
 	}
@@ -50,7 +61,7 @@ func Requeue() (result OperationResult, err error) {
 
 func RequeueWithError(err error) (OperationResult, error) {
 	result := OperationResult{
-		RequeueDelay:   DefaultRequeueDelay,
+		RequeueDelay:   RequeueDelay(),
 		RequeueRequest: true,
 		CancelRequest:  false,
 	}
@@ -59,7 +70,7 @@ func RequeueWithError(err error) (OperationResult, error) {
 
 func RequeueOnErrorOrStop(err error) (OperationResult, error) {
 	return OperationResult{
-		RequeueDelay:   DefaultRequeueDelay,
+		RequeueDelay:   RequeueDelay(),
 		RequeueRequest: false,
 		CancelRequest:  true,
 	}, err
@@ -67,7 +78,7 @@ func RequeueOnErrorOrStop(err error) (OperationResult, error) {
 
 func RequeueOnErrorOrContinue(err error) (OperationResult, error) {
 	return OperationResult{
-		RequeueDelay:   DefaultRequeueDelay,
+		RequeueDelay:   RequeueDelay(),
 		RequeueRequest: false,
 		CancelRequest:  false,
 	}, err
@@ -83,3 +94,18 @@ func RequeueAfter(delay time.Duration, err error) (OperationResult, error) {
 func ContinueProcessing() (OperationResult, error) {
 	return ContinueOperationResult(), nil
 }
+
+// ContinueProcessingWithin continues processing and has the request reconciled again within the delay
+func ContinueProcessingWithin(delay time.Duration) (OperationResult, error) {
+	result := ContinueOperationResult()
+	result.RequeueWithin = delay
+	return result, nil
+}
+
+// Sooner returns the shorter of two requeue delays, a zero delay means none
+func Sooner(delay, other time.Duration) time.Duration {
+	if delay == 0 || (other > 0 && other < delay) {
+		return other
+	}
+	return delay
+}
@@ -3,6 +3,7 @@ package reconciler
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -95,6 +96,21 @@ func TestOperations(t *testing.T) {
 		})
 	})
 
+	t.Run("ContinueProcessingWithin", func(t *testing.T) {
+		result, err := ContinueProcessingWithin(time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, OperationResult{RequeueWithin: time.Minute}, result)
+		assert.False(t, result.RequeueOrCancel())
+	})
+
+	t.Run("Sooner", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), Sooner(0, 0))
+		assert.Equal(t, time.Minute, Sooner(0, time.Minute))
+		assert.Equal(t, time.Minute, Sooner(time.Minute, 0))
+		assert.Equal(t, time.Second, Sooner(time.Minute, time.Second))
+		assert.Equal(t, time.Second, Sooner(time.Second, time.Minute))
+	})
+
 	t.Run("RequeueOrCancel", func(t *testing.T) {
 		result1 := OperationResult{
 			RequeueDelay:   0,
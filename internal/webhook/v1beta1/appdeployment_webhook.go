@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+)
+
+var appdeploymentlog = logf.Log.WithName("appdeployment-resource")
+
+// SetupAppDeploymentWebhookWithManager registers the webhook for AppDeployment in the manager.
+func SetupAppDeploymentWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1beta1.AppDeployment{}).
+		WithValidator(&AppDeploymentCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-controller-azure-github-com-v1beta1-appdeployment,mutating=false,failurePolicy=fail,sideEffects=None,groups=controller.azure.github.com,resources=appdeployments,verbs=create;update,versions=v1beta1,name=vappdeployment-v1beta1.kb.io,admissionReviewVersions=v1
+
+// AppDeploymentCustomValidator rejects app deployments with job specs the controller cannot run.
+type AppDeploymentCustomValidator struct{}
+
+var _ webhook.CustomValidator = &AppDeploymentCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type AppDeployment.
+func (v *AppDeploymentCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	appdeployment, ok := obj.(*v1beta1.AppDeployment)
+	if !ok {
+		return nil, fmt.Errorf("expected an AppDeployment object but got %T", obj)
+	}
+	appdeploymentlog.V(1).Info("validation for AppDeployment upon creation", "name", appdeployment.GetName())
+	return nil, v.validate(appdeployment)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type AppDeployment.
+func (v *AppDeploymentCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldAppDeployment, ok := oldObj.(*v1beta1.AppDeployment)
+	if !ok {
+		return nil, fmt.Errorf("expected an AppDeployment object for the oldObj but got %T", oldObj)
+	}
+	appdeployment, ok := newObj.(*v1beta1.AppDeployment)
+	if !ok {
+		return nil, fmt.Errorf("expected an AppDeployment object for the newObj but got %T", newObj)
+	}
+	appdeploymentlog.V(1).Info("validation for AppDeployment upon update", "name", appdeployment.GetName())
+	// metadata only updates like removing the finalizer must pass even for objects admitted before the webhook
+	if equality.Semantic.DeepEqual(oldAppDeployment.Spec, appdeployment.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(appdeployment)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type AppDeployment.
+func (v *AppDeploymentCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *AppDeploymentCustomValidator) validate(appdeployment *v1beta1.AppDeployment) error {
+	errs := field.ErrorList{}
+	if err := ctrlutils.Validate(appdeployment); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec"), appdeployment.Name, err.Error()))
+	}
+	return toInvalidError("AppDeployment", appdeployment.Name, errs)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+)
+
+func TestAppDeploymentCustomValidator(t *testing.T) {
+	ctx := context.Background()
+	v := &AppDeploymentCustomValidator{}
+	newAppDeployment := func(provision batchv1.JobSpec) *v1beta1.AppDeployment {
+		return &v1beta1.AppDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+			Spec:       v1beta1.AppDeploymentSpec{OperationID: "op", Provision: provision},
+		}
+	}
+
+	t.Run("valid create", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, newAppDeployment(validJob))
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid create", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, newAppDeployment(invalidJob))
+		assert.True(t, apierrors.IsInvalid(err))
+	})
+
+	t.Run("restart policy other than Never", func(t *testing.T) {
+		job := *validJob.DeepCopy()
+		job.Template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+		_, err := v.ValidateCreate(ctx, newAppDeployment(job))
+		assert.True(t, apierrors.IsInvalid(err))
+	})
+
+	t.Run("update with unchanged spec is allowed", func(t *testing.T) {
+		oldObj := newAppDeployment(invalidJob)
+		newObj := oldObj.DeepCopy()
+		newObj.Finalizers = nil
+		_, err := v.ValidateUpdate(ctx, oldObj, newObj)
+		assert.NoError(t, err)
+	})
+
+	t.Run("update with invalid spec", func(t *testing.T) {
+		_, err := v.ValidateUpdate(ctx, newAppDeployment(validJob), newAppDeployment(invalidJob))
+		assert.True(t, apierrors.IsInvalid(err))
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, &v1beta1.Operation{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+)
+
+var cachelog = logf.Log.WithName("cache-resource")
+
+// SetupCacheWebhookWithManager registers the webhook for Cache in the manager.
+func SetupCacheWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1beta1.Cache{}).
+		WithValidator(&CacheCustomValidator{}).
+		WithDefaulter(&CacheCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-controller-azure-github-com-v1beta1-cache,mutating=true,failurePolicy=fail,sideEffects=None,groups=controller.azure.github.com,resources=caches,verbs=create;update,versions=v1beta1,name=mcache-v1beta1.kb.io,admissionReviewVersions=v1
+
+// CacheCustomDefaulter sets the cache strategy.
+type CacheCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &CacheCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type Cache.
+func (d *CacheCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	cache, ok := obj.(*v1beta1.Cache)
+	if !ok {
+		return fmt.Errorf("expected a Cache object but got %T", obj)
+	}
+	cachelog.V(1).Info("defaulting for Cache", "name", cache.GetName())
+	if cache.Spec.Strategy == "" {
+		cache.Spec.Strategy = v1beta1.CacheStrategyFixed
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-controller-azure-github-com-v1beta1-cache,mutating=false,failurePolicy=fail,sideEffects=None,groups=controller.azure.github.com,resources=caches,verbs=create;update,versions=v1beta1,name=vcache-v1beta1.kb.io,admissionReviewVersions=v1
+
+// CacheCustomValidator rejects caches with an invalid operation template, strategy, keep-alive bounds or reset job.
+type CacheCustomValidator struct{}
+
+var _ webhook.CustomValidator = &CacheCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Cache.
+func (v *CacheCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cache, ok := obj.(*v1beta1.Cache)
+	if !ok {
+		return nil, fmt.Errorf("expected a Cache object but got %T", obj)
+	}
+	cachelog.V(1).Info("validation for Cache upon creation", "name", cache.GetName())
+	return nil, v.validate(cache)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Cache.
+func (v *CacheCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCache, ok := oldObj.(*v1beta1.Cache)
+	if !ok {
+		return nil, fmt.Errorf("expected a Cache object for the oldObj but got %T", oldObj)
+	}
+	cache, ok := newObj.(*v1beta1.Cache)
+	if !ok {
+		return nil, fmt.Errorf("expected a Cache object for the newObj but got %T", newObj)
+	}
+	cachelog.V(1).Info("validation for Cache upon update", "name", cache.GetName())
+	if equality.Semantic.DeepEqual(oldCache.Spec, cache.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(cache)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Cache.
+func (v *CacheCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *CacheCustomValidator) validate(cache *v1beta1.Cache) error {
+	specPath := field.NewPath("spec")
+	errs := validateOperationSpec(specPath.Child("operationTemplate"), cache.Spec.OperationTemplate)
+	if _, err := ctrlutils.NewCacheStrategy(cache.Spec.Strategy); err != nil {
+		errs = append(errs, field.NotSupported(specPath.Child("strategy"), cache.Spec.Strategy,
+			[]string{v1beta1.CacheStrategyFixed, v1beta1.CacheStrategyOnDemand, v1beta1.CacheStrategyAdaptive}))
+	}
+	if cache.Spec.MinKeepAliveCount != nil && cache.Spec.MaxKeepAliveCount != nil &&
+		*cache.Spec.MinKeepAliveCount > *cache.Spec.MaxKeepAliveCount {
+		errs = append(errs, field.Invalid(specPath.Child("minKeepAliveCount"), *cache.Spec.MinKeepAliveCount, "must not be greater than maxKeepAliveCount"))
+	}
+	errs = append(errs, validateTTL(specPath.Child("ttlAfterLastAccess"), cache.Spec.TTLAfterLastAccess)...)
+	if cache.Spec.ProvisionTimeout != nil && cache.Spec.ProvisionTimeout.Duration <= 0 {
+		errs = append(errs, field.Invalid(specPath.Child("provisionTimeout"), cache.Spec.ProvisionTimeout.Duration.String(), "must be positive"))
+	}
+	if cache.Spec.Recycle != nil {
+		if err := ctrlutils.ValidateResetJob(cache.Spec.Recycle.Reset); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("recycle", "reset"), "", err.Error()))
+		}
+	}
+	return toInvalidError("Cache", cache.Name, errs)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	"github.com/Azure/operation-cache-controller/internal/utils/ptr"
+)
+
+func newCache() *v1beta1.Cache {
+	return &v1beta1.Cache{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cache", Namespace: "default"},
+		Spec: v1beta1.CacheSpec{
+			OperationTemplate: newOperationSpec(v1beta1.ApplicationSpec{Name: "a", Provision: validJob}),
+		},
+	}
+}
+
+func TestCacheCustomDefaulter(t *testing.T) {
+	ctx := context.Background()
+	d := &CacheCustomDefaulter{}
+
+	cache := newCache()
+	require.NoError(t, d.Default(ctx, cache))
+	assert.Equal(t, v1beta1.CacheStrategyFixed, cache.Spec.Strategy)
+
+	cache = newCache()
+	cache.Spec.Strategy = v1beta1.CacheStrategyOnDemand
+	require.NoError(t, d.Default(ctx, cache))
+	assert.Equal(t, v1beta1.CacheStrategyOnDemand, cache.Spec.Strategy)
+}
+
+func TestCacheCustomValidator(t *testing.T) {
+	ctx := context.Background()
+	v := &CacheCustomValidator{}
+
+	t.Run("valid create", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, newCache())
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		cache := newCache()
+		cache.Spec.Strategy = "unknown"
+		_, err := v.ValidateCreate(ctx, cache)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "spec.strategy")
+	})
+
+	t.Run("min keep alive above max", func(t *testing.T) {
+		cache := newCache()
+		cache.Spec.MinKeepAliveCount = ptr.Of(int32(5))
+		cache.Spec.MaxKeepAliveCount = ptr.Of(int32(2))
+		_, err := v.ValidateCreate(ctx, cache)
+		assert.ErrorContains(t, err, "spec.minKeepAliveCount")
+	})
+
+	t.Run("non-positive provisionTimeout", func(t *testing.T) {
+		cache := newCache()
+		cache.Spec.ProvisionTimeout = &metav1.Duration{}
+		_, err := v.ValidateCreate(ctx, cache)
+		assert.ErrorContains(t, err, "spec.provisionTimeout")
+	})
+
+	t.Run("non-positive ttlAfterLastAccess", func(t *testing.T) {
+		cache := newCache()
+		cache.Spec.TTLAfterLastAccess = &metav1.Duration{Duration: -time.Minute}
+		_, err := v.ValidateCreate(ctx, cache)
+		assert.ErrorContains(t, err, "spec.ttlAfterLastAccess")
+	})
+
+	t.Run("valid reset job", func(t *testing.T) {
+		cache := newCache()
+		cache.Spec.Recycle = &v1beta1.CacheRecycle{Reset: validJob}
+		_, err := v.ValidateCreate(ctx, cache)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid reset job", func(t *testing.T) {
+		cache := newCache()
+		cache.Spec.Recycle = &v1beta1.CacheRecycle{Reset: invalidJob}
+		_, err := v.ValidateCreate(ctx, cache)
+		assert.ErrorContains(t, err, "spec.recycle.reset")
+
+		cache.Spec.Recycle = &v1beta1.CacheRecycle{}
+		_, err = v.ValidateCreate(ctx, cache)
+		assert.ErrorContains(t, err, "spec.recycle.reset")
+	})
+
+	t.Run("invalid operation template", func(t *testing.T) {
+		cache := newCache()
+		cache.Spec.OperationTemplate.Applications[0].Provision = invalidJob
+		_, err := v.ValidateUpdate(ctx, newCache(), cache)
+		assert.ErrorContains(t, err, "spec.operationTemplate.applications")
+	})
+}
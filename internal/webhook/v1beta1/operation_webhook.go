@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+)
+
+var operationlog = logf.Log.WithName("operation-resource")
+
+// SetupOperationWebhookWithManager registers the webhook for Operation in the manager.
+func SetupOperationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1beta1.Operation{}).
+		WithValidator(&OperationCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-controller-azure-github-com-v1beta1-operation,mutating=false,failurePolicy=fail,sideEffects=None,groups=controller.azure.github.com,resources=operations,verbs=create;update,versions=v1beta1,name=voperation-v1beta1.kb.io,admissionReviewVersions=v1
+
+// OperationCustomValidator rejects operations with invalid applications.
+type OperationCustomValidator struct{}
+
+var _ webhook.CustomValidator = &OperationCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Operation.
+func (v *OperationCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	operation, ok := obj.(*v1beta1.Operation)
+	if !ok {
+		return nil, fmt.Errorf("expected an Operation object but got %T", obj)
+	}
+	operationlog.V(1).Info("validation for Operation upon creation", "name", operation.GetName())
+	return nil, v.validate(operation)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Operation.
+func (v *OperationCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldOperation, ok := oldObj.(*v1beta1.Operation)
+	if !ok {
+		return nil, fmt.Errorf("expected an Operation object for the oldObj but got %T", oldObj)
+	}
+	operation, ok := newObj.(*v1beta1.Operation)
+	if !ok {
+		return nil, fmt.Errorf("expected an Operation object for the newObj but got %T", newObj)
+	}
+	operationlog.V(1).Info("validation for Operation upon update", "name", operation.GetName())
+	// metadata only updates like acquiring the operation or removing the finalizer must always pass
+	if equality.Semantic.DeepEqual(oldOperation.Spec, operation.Spec) {
+		return nil, toInvalidError("Operation", operation.Name, validateTimeAnnotationsUpdate(oldOperation, operation, ctrlutils.AnnotationNameLastAccess))
+	}
+	return nil, v.validate(operation)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Operation.
+func (v *OperationCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *OperationCustomValidator) validate(operation *v1beta1.Operation) error {
+	errs := validateOperationSpec(field.NewPath("spec"), operation.Spec)
+	errs = append(errs, validateTimeAnnotations(operation, ctrlutils.AnnotationNameLastAccess)...)
+	return toInvalidError("Operation", operation.Name, errs)
+}
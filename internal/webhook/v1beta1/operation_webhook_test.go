@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+)
+
+func TestOperationCustomValidator(t *testing.T) {
+	ctx := context.Background()
+	v := &OperationCustomValidator{}
+	newOperation := func(spec v1beta1.OperationSpec) *v1beta1.Operation {
+		return &v1beta1.Operation{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-operation", Namespace: "default"},
+			Spec:       spec,
+		}
+	}
+	valid := newOperationSpec(
+		v1beta1.ApplicationSpec{Name: "a", Provision: validJob},
+		v1beta1.ApplicationSpec{Name: "b", Provision: validJob, Dependencies: []string{"a"}},
+	)
+	cyclic := newOperationSpec(
+		v1beta1.ApplicationSpec{Name: "a", Provision: validJob, Dependencies: []string{"b"}},
+		v1beta1.ApplicationSpec{Name: "b", Provision: validJob, Dependencies: []string{"a"}},
+	)
+
+	t.Run("valid create", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, newOperation(valid))
+		assert.NoError(t, err)
+	})
+
+	t.Run("dependency cycle", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, newOperation(cyclic))
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "a -> b -> a")
+	})
+
+	t.Run("invalid job", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, newOperation(newOperationSpec(v1beta1.ApplicationSpec{Name: "a", Provision: invalidJob})))
+		assert.True(t, apierrors.IsInvalid(err))
+	})
+
+	t.Run("non-positive ttlAfterReady", func(t *testing.T) {
+		spec := valid.DeepCopy()
+		spec.TTLAfterReady = &metav1.Duration{}
+		_, err := v.ValidateCreate(ctx, newOperation(*spec))
+		assert.ErrorContains(t, err, "spec.ttlAfterReady")
+	})
+
+	t.Run("invalid last access annotation on update", func(t *testing.T) {
+		oldObj := newOperation(valid)
+		newObj := oldObj.DeepCopy()
+		newObj.Annotations = map[string]string{ctrlutils.AnnotationNameLastAccess: "now"}
+		_, err := v.ValidateUpdate(ctx, oldObj, newObj)
+		assert.ErrorContains(t, err, ctrlutils.AnnotationNameLastAccess)
+	})
+
+	t.Run("metadata only update is allowed", func(t *testing.T) {
+		oldObj := newOperation(cyclic)
+		newObj := oldObj.DeepCopy()
+		newObj.Labels = map[string]string{"acquired": "true"}
+		_, err := v.ValidateUpdate(ctx, oldObj, newObj)
+		assert.NoError(t, err)
+	})
+
+	t.Run("update introducing a cycle", func(t *testing.T) {
+		_, err := v.ValidateUpdate(ctx, newOperation(valid), newOperation(cyclic))
+		assert.True(t, apierrors.IsInvalid(err))
+	})
+}
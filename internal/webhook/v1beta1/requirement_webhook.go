@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+)
+
+var requirementlog = logf.Log.WithName("requirement-resource")
+
+// requirementTimeAnnotations are the annotations by which consumers report the use of a requirement
+var requirementTimeAnnotations = []string{ctrlutils.AnnotationNameLastAccess, ctrlutils.AnnotationNameRenewUntil}
+
+// SetupRequirementWebhookWithManager registers the webhook for Requirement in the manager.
+// A positive defaultTTL sets the expireAt of requirements created without one, zero keeps them forever.
+func SetupRequirementWebhookWithManager(mgr ctrl.Manager, defaultTTL time.Duration) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1beta1.Requirement{}).
+		WithValidator(&RequirementCustomValidator{}).
+		WithDefaulter(&RequirementCustomDefaulter{DefaultTTL: defaultTTL}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-controller-azure-github-com-v1beta1-requirement,mutating=true,failurePolicy=fail,sideEffects=None,groups=controller.azure.github.com,resources=requirements,verbs=create,versions=v1beta1,name=mrequirement-v1beta1.kb.io,admissionReviewVersions=v1
+
+// RequirementCustomDefaulter sets the expireAt of new requirements.
+type RequirementCustomDefaulter struct {
+	DefaultTTL time.Duration
+}
+
+var _ webhook.CustomDefaulter = &RequirementCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type Requirement.
+func (d *RequirementCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	requirement, ok := obj.(*v1beta1.Requirement)
+	if !ok {
+		return fmt.Errorf("expected a Requirement object but got %T", obj)
+	}
+	requirementlog.V(1).Info("defaulting for Requirement", "name", requirement.GetName())
+	if requirement.Spec.ExpireAt == nil && d.DefaultTTL > 0 {
+		requirement.Spec.ExpireAt = &metav1.Time{Time: time.Now().Add(d.DefaultTTL)}
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-controller-azure-github-com-v1beta1-requirement,mutating=false,failurePolicy=fail,sideEffects=None,groups=controller.azure.github.com,resources=requirements,verbs=create;update,versions=v1beta1,name=vrequirement-v1beta1.kb.io,admissionReviewVersions=v1
+
+// RequirementCustomValidator rejects requirements with an invalid operation template.
+type RequirementCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RequirementCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Requirement.
+func (v *RequirementCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	requirement, ok := obj.(*v1beta1.Requirement)
+	if !ok {
+		return nil, fmt.Errorf("expected a Requirement object but got %T", obj)
+	}
+	requirementlog.V(1).Info("validation for Requirement upon creation", "name", requirement.GetName())
+	return nil, v.validate(requirement)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Requirement.
+func (v *RequirementCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldRequirement, ok := oldObj.(*v1beta1.Requirement)
+	if !ok {
+		return nil, fmt.Errorf("expected a Requirement object for the oldObj but got %T", oldObj)
+	}
+	requirement, ok := newObj.(*v1beta1.Requirement)
+	if !ok {
+		return nil, fmt.Errorf("expected a Requirement object for the newObj but got %T", newObj)
+	}
+	requirementlog.V(1).Info("validation for Requirement upon update", "name", requirement.GetName())
+	if equality.Semantic.DeepEqual(oldRequirement.Spec, requirement.Spec) {
+		return nil, toInvalidError("Requirement", requirement.Name, validateTimeAnnotationsUpdate(oldRequirement, requirement, requirementTimeAnnotations...))
+	}
+	return nil, v.validate(requirement)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Requirement.
+func (v *RequirementCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *RequirementCustomValidator) validate(requirement *v1beta1.Requirement) error {
+	specPath := field.NewPath("spec")
+	errs := validateOperationSpec(specPath.Child("template"), requirement.Spec.Template)
+	errs = append(errs, validateTTL(specPath.Child("ttlAfterReady"), requirement.Spec.TTLAfterReady)...)
+	errs = append(errs, validateTTL(specPath.Child("ttlAfterLastAccess"), requirement.Spec.TTLAfterLastAccess)...)
+	errs = append(errs, validateTimeAnnotations(requirement, requirementTimeAnnotations...)...)
+	return toInvalidError("Requirement", requirement.Name, errs)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+)
+
+func newRequirement(expireAt *metav1.Time) *v1beta1.Requirement {
+	return &v1beta1.Requirement{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-requirement", Namespace: "default"},
+		Spec: v1beta1.RequirementSpec{
+			Template: newOperationSpec(v1beta1.ApplicationSpec{Name: "a", Provision: validJob}),
+			ExpireAt: expireAt,
+		},
+	}
+}
+
+func TestRequirementCustomDefaulter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no default ttl", func(t *testing.T) {
+		requirement := newRequirement(nil)
+		require.NoError(t, (&RequirementCustomDefaulter{}).Default(ctx, requirement))
+		assert.Nil(t, requirement.Spec.ExpireAt)
+	})
+
+	t.Run("default ttl", func(t *testing.T) {
+		requirement := newRequirement(nil)
+		require.NoError(t, (&RequirementCustomDefaulter{DefaultTTL: time.Hour}).Default(ctx, requirement))
+		require.NotNil(t, requirement.Spec.ExpireAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), requirement.Spec.ExpireAt.Time, time.Minute)
+	})
+
+	t.Run("explicit expireAt is kept", func(t *testing.T) {
+		expireAt := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		requirement := newRequirement(&expireAt)
+		require.NoError(t, (&RequirementCustomDefaulter{DefaultTTL: time.Hour}).Default(ctx, requirement))
+		assert.Equal(t, &expireAt, requirement.Spec.ExpireAt)
+	})
+}
+
+func TestRequirementCustomValidator(t *testing.T) {
+	ctx := context.Background()
+	v := &RequirementCustomValidator{}
+
+	t.Run("valid create", func(t *testing.T) {
+		_, err := v.ValidateCreate(ctx, newRequirement(&metav1.Time{Time: time.Now()}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		requirement := newRequirement(nil)
+		requirement.Spec.Template.Applications[0].Dependencies = []string{"missing"}
+		_, err := v.ValidateCreate(ctx, requirement)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "spec.template.applications")
+	})
+
+	t.Run("non-positive ttls", func(t *testing.T) {
+		requirement := newRequirement(nil)
+		requirement.Spec.TTLAfterReady = &metav1.Duration{}
+		requirement.Spec.Template.TTLAfterLastAccess = &metav1.Duration{Duration: -time.Minute}
+		_, err := v.ValidateCreate(ctx, requirement)
+		assert.ErrorContains(t, err, "spec.ttlAfterReady")
+		assert.ErrorContains(t, err, "spec.template.ttlAfterLastAccess")
+	})
+
+	t.Run("invalid last access annotation", func(t *testing.T) {
+		requirement := newRequirement(nil)
+		requirement.Annotations = map[string]string{ctrlutils.AnnotationNameLastAccess: "yesterday"}
+		_, err := v.ValidateCreate(ctx, requirement)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, ctrlutils.AnnotationNameLastAccess)
+	})
+
+	t.Run("invalid renew until annotation", func(t *testing.T) {
+		requirement := newRequirement(nil)
+		requirement.Annotations = map[string]string{ctrlutils.AnnotationNameRenewUntil: "1h"}
+		_, err := v.ValidateCreate(ctx, requirement)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, ctrlutils.AnnotationNameRenewUntil)
+
+		oldObj := newRequirement(nil)
+		_, err = v.ValidateUpdate(ctx, oldObj, requirement)
+		assert.ErrorContains(t, err, ctrlutils.AnnotationNameRenewUntil)
+	})
+
+	t.Run("last access annotation change is validated", func(t *testing.T) {
+		oldObj := newRequirement(nil)
+		newObj := oldObj.DeepCopy()
+		newObj.Annotations = map[string]string{ctrlutils.AnnotationNameLastAccess: "yesterday"}
+		_, err := v.ValidateUpdate(ctx, oldObj, newObj)
+		assert.ErrorContains(t, err, ctrlutils.AnnotationNameLastAccess)
+
+		newObj.Annotations[ctrlutils.AnnotationNameLastAccess] = time.Now().Format(time.RFC3339)
+		_, err = v.ValidateUpdate(ctx, oldObj, newObj)
+		assert.NoError(t, err)
+	})
+
+	t.Run("update with unchanged spec is allowed", func(t *testing.T) {
+		oldObj := newRequirement(nil)
+		oldObj.Spec.Template.Applications[0].Dependencies = []string{"missing"}
+		newObj := oldObj.DeepCopy()
+		newObj.Finalizers = []string{"finalizer"}
+		_, err := v.ValidateUpdate(ctx, oldObj, newObj)
+		assert.NoError(t, err)
+	})
+}
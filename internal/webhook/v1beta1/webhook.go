@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains the admission webhooks of the v1beta1 API.
+package v1beta1
+
+import (
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
+	ctrlutils "github.com/Azure/operation-cache-controller/internal/utils/controller"
+)
+
+// validateOperationSpec validates an operation spec at the given path, operations, requirements
+// and caches all carry one
+func validateOperationSpec(path *field.Path, spec v1beta1.OperationSpec) field.ErrorList {
+	errs := field.ErrorList{}
+	if err := ctrlutils.ValidateOperationSpec(spec); err != nil {
+		errs = append(errs, field.Invalid(path.Child("applications"), len(spec.Applications), err.Error()))
+	}
+	errs = append(errs, validateTTL(path.Child("ttlAfterReady"), spec.TTLAfterReady)...)
+	errs = append(errs, validateTTL(path.Child("ttlAfterLastAccess"), spec.TTLAfterLastAccess)...)
+	return errs
+}
+
+// validateTTL validates a ttl of the expiry of an object
+func validateTTL(path *field.Path, ttl *metav1.Duration) field.ErrorList {
+	if err := ctrlutils.ValidateTTL(ttl); err != nil {
+		return field.ErrorList{field.Invalid(path, ttl.Duration.String(), err.Error())}
+	}
+	return nil
+}
+
+// validateTimeAnnotations validates the annotations holding times of a requirement or an operation, like the
+// last-access annotation
+func validateTimeAnnotations(obj metav1.Object, names ...string) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, name := range names {
+		value, ok := obj.GetAnnotations()[name]
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "annotations").Key(name), value, "must be an RFC3339 time"))
+		}
+	}
+	return errs
+}
+
+// validateTimeAnnotationsUpdate validates the annotations holding times which were changed, so other metadata only
+// updates pass for objects admitted before
+func validateTimeAnnotationsUpdate(oldObj, obj metav1.Object, names ...string) field.ErrorList {
+	changed := slices.DeleteFunc(slices.Clone(names), func(name string) bool {
+		return oldObj.GetAnnotations()[name] == obj.GetAnnotations()[name]
+	})
+	return validateTimeAnnotations(obj, changed...)
+}
+
+// toInvalidError turns the field errors of an object into the error returned to the api server, nil if there are none
+func toInvalidError(kind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(v1beta1.GroupVersion.WithKind(kind).GroupKind(), name, errs)
+}
@@ -5,7 +5,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/Azure/operation-cache-controller/api/v1alpha1"
+	"github.com/Azure/operation-cache-controller/api/v1beta1"
 )
 
 const (
@@ -29,30 +29,31 @@ func NewTestJobSpec(name string) batchv1.JobSpec {
 	}
 }
 
-func NewTestApplicationSpec(name string) v1alpha1.ApplicationSpec {
-	return v1alpha1.ApplicationSpec{
+func NewTestApplicationSpec(name string) v1beta1.ApplicationSpec {
+	return v1beta1.ApplicationSpec{
 		Name:      name,
 		Provision: NewTestJobSpec("provision"),
 		Teardown:  NewTestJobSpec("teardown"),
 	}
 }
 
-func NewSimpleOperationSpec(name string) *v1alpha1.OperationSpec {
-	return &v1alpha1.OperationSpec{
-		Applications: []v1alpha1.ApplicationSpec{NewTestApplicationSpec("app1")},
+func NewSimpleOperationSpec(name string) *v1beta1.OperationSpec {
+	return &v1beta1.OperationSpec{
+		Applications: []v1beta1.ApplicationSpec{NewTestApplicationSpec("app1")},
 	}
 }
 
-func NewRequirement(name, namespace string) *v1alpha1.Requirement {
-	return &v1alpha1.Requirement{
+func NewRequirement(name, namespace string) *v1beta1.Requirement {
+	return &v1beta1.Requirement{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
 		},
-		Spec: v1alpha1.RequirementSpec{
-			Template: v1alpha1.OperationSpec{
-				Applications: []v1alpha1.ApplicationSpec{NewTestApplicationSpec("app1")},
+		Spec: v1beta1.RequirementSpec{
+			Template: v1beta1.OperationSpec{
+				Applications: []v1beta1.ApplicationSpec{NewTestApplicationSpec("app1")},
 			},
+			CachePolicy: v1beta1.CachePolicyDisabled,
 		},
 	}
 }